@@ -0,0 +1,296 @@
+// Package client is a typed Go client for the favourites HTTP API.
+// It decodes the polymorphic asset payloads into the Chart, Insight and Audience models,
+// so consumers never handle the raw JSON themselves.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// The asset models are aliased so packages outside this module can use them
+type (
+	Asset     = models.Asset
+	AssetType = models.AssetType
+	Chart     = models.Chart
+	Point     = models.Point
+	Insight   = models.Insight
+	Audience  = models.Audience
+)
+
+// Define constants for Asset Types
+const (
+	ChartType    = models.ChartType
+	InsightType  = models.InsightType
+	AudienceType = models.AudienceType
+)
+
+// Default settings of a new Client
+const (
+	DefaultTimeout        = 10 * time.Second
+	DefaultMaxRetries     = 2
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// Client sends requests to the favourites API at BaseURL.
+// Requests that failed without changing anything, because the API could not be reached, was unavailable
+// or rate limited the client, are retried with exponential backoff. Additions are sent with an Idempotency-Key,
+// so they are also retried after a timeout: the API replays the response of an addition it already stored.
+type Client struct {
+	BaseURL        string
+	APIKey         string // APIKey is sent in the X-API-Key header when set, identifying the client
+	HTTPClient     *http.Client
+	Timeout        time.Duration // Timeout limits every attempt of a request, zero disables it
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration // MaxBackoff also caps how long a Retry-After header is honoured
+}
+
+// NewClient creates a new Client for the API at baseURL, e.g. http://localhost:8080
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:        strings.TrimRight(baseURL, "/"),
+		HTTPClient:     http.DefaultClient,
+		Timeout:        DefaultTimeout,
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// GetUsers returns the IDs of all users in ascending order
+func (c *Client) GetUsers(ctx context.Context) ([]int, error) {
+	var ids []int
+	if err := c.do(ctx, http.MethodGet, "/users", "", nil, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetUserFavorites returns the favourites of a user by asset ID
+func (c *Client) GetUserFavorites(ctx context.Context, userID int) (map[int]Asset, error) {
+	var raw map[string]json.RawMessage
+	if err := c.do(ctx, http.MethodGet, userPath(userID, "favorites"), "", nil, &raw); err != nil {
+		return nil, err
+	}
+
+	favorites := make(map[int]Asset, len(raw))
+	for key, data := range raw {
+		assetID, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset id %q", key)
+		}
+		asset, err := utils.DecodeAsset(data)
+		if err != nil {
+			return nil, err
+		}
+		favorites[assetID] = asset
+	}
+	return favorites, nil
+}
+
+// AddUserFavorite adds an asset to a user's favourites, returning the asset as it was stored.
+// Every call is sent with a new Idempotency-Key, shared by its retries.
+func (c *Client) AddUserFavorite(ctx context.Context, userID int, asset Asset) (Asset, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	return c.doAsset(ctx, http.MethodPost, userPath(userID, "favorites"), key, asset)
+}
+
+// EditUserFavorite replaces an asset of a user's favourites, returning the asset as it was stored
+func (c *Client) EditUserFavorite(ctx context.Context, userID, assetID int, asset Asset) (Asset, error) {
+	return c.doAsset(ctx, http.MethodPut, userPath(userID, "favorites", strconv.Itoa(assetID)), "", asset)
+}
+
+// DeleteUserFavorite deletes an asset from a user's favourites
+func (c *Client) DeleteUserFavorite(ctx context.Context, userID, assetID int) error {
+	return c.do(ctx, http.MethodDelete, userPath(userID, "favorites", strconv.Itoa(assetID)), "", nil, nil)
+}
+
+// doAsset sends an asset and decodes the asset in the response
+func (c *Client) doAsset(ctx context.Context, method, path, idempotencyKey string, asset Asset) (Asset, error) {
+	var raw json.RawMessage
+	if err := c.do(ctx, method, path, idempotencyKey, asset, &raw); err != nil {
+		return nil, err
+	}
+	return utils.DecodeAsset(raw)
+}
+
+// do sends a request with body encoded as JSON, and with the Idempotency-Key header unless idempotencyKey is empty,
+// retrying it when possible, and decodes the JSON response into out unless it is nil
+func (c *Client) do(ctx context.Context, method, path, idempotencyKey string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	// handled is set once an attempt failed in a way the API may still have applied the request
+	handled := false
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, path, idempotencyKey, data, out)
+		if handled && method == http.MethodDelete && IsNotFound(err) {
+			// The asset is gone because the previous attempt deleted it
+			return nil
+		}
+		if err == nil || retryAfter < 0 || attempt >= c.MaxRetries {
+			return err
+		}
+		handled = handled || mayHaveBeenHandled(err)
+
+		wait := c.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		if wait > c.MaxBackoff {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a request once. When it fails, it also returns how long the API asked to wait before
+// retrying, or a negative duration if the request must not be retried.
+func (c *Client) attempt(ctx context.Context, method, path, idempotencyKey string, body []byte, out interface{}) (time.Duration, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	// Continue the caller's trace on the server, if ctx carries a span
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Requests are safe to send again if they are idempotent, or made so by their key
+	idempotent := method != http.MethodPost || idempotencyKey != ""
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if !idempotent || ctx.Err() == context.Canceled {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		apiErr := newError(resp.StatusCode, strings.TrimSpace(string(message)))
+		return retryAfter(idempotent, resp, apiErr), apiErr
+	}
+	if out == nil {
+		return -1, nil
+	}
+	return -1, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// backoff returns how long to wait before retrying after the given attempt, doubling from InitialBackoff up to MaxBackoff
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := c.InitialBackoff
+	for i := 0; i < attempt && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > c.MaxBackoff {
+		return c.MaxBackoff
+	}
+	return backoff
+}
+
+// retryAfter returns how long to wait before retrying a failed response, honouring its Retry-After header,
+// or a negative duration if it must not be retried
+func retryAfter(idempotent bool, resp *http.Response, apiErr *Error) time.Duration {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The request was rejected before being handled, so even additions without a key can be retried
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		if !idempotent {
+			return -1
+		}
+	case http.StatusConflict:
+		// A previous attempt with the same key is still running, its response is replayed once it completes
+		if !errors.Is(apiErr, ErrRequestInProgress) {
+			return -1
+		}
+	default:
+		return -1
+	}
+
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// mayHaveBeenHandled reports whether a failed request may have reached the API and been applied,
+// i.e. it failed without response or the gateway gave up waiting for the API
+func mayHaveBeenHandled(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusGatewayTimeout
+}
+
+// newIdempotencyKey returns a random Idempotency-Key
+func newIdempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// userPath returns the escaped path of a resource of a user
+func userPath(userID int, elems ...string) string {
+	path := "/users/" + strconv.Itoa(userID)
+	for _, elem := range elems {
+		path += "/" + url.PathEscape(elem)
+	}
+	return path
+}
@@ -0,0 +1,293 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup starts the API with 2 users having an Insight, an Audience and a Chart with IDs 1 to 3,
+// and returns a client for it without backoff between retries
+func setup(t *testing.T, mwf ...mux.MiddlewareFunc) *client.Client {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(2, 3)
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(service.NewUserService(repo)).RegisterRoutes(r)
+	r.Use(mwf...)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	c := client.NewClient(server.URL)
+	c.InitialBackoff = time.Millisecond
+	return c
+}
+
+// failing responds with status to the first n requests, counting all requests
+func failing(n int32, status int, requests *int32) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(requests, 1) <= n {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	c := setup(t)
+
+	ids, err := c.GetUsers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
+func TestGetUserFavorites(t *testing.T) {
+	c := setup(t)
+	ctx := context.Background()
+
+	favorites, err := c.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 3)
+	assert.IsType(t, &client.Insight{}, favorites[1])
+	assert.IsType(t, &client.Audience{}, favorites[2])
+	assert.IsType(t, &client.Chart{}, favorites[3])
+
+	_, err = c.GetUserFavorites(ctx, 999)
+	assert.ErrorIs(t, err, client.ErrUserNotFound)
+	assert.True(t, client.IsNotFound(err))
+
+	var apiErr *client.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "user not found", apiErr.Message)
+}
+
+func TestAddUserFavorite(t *testing.T) {
+	c := setup(t)
+	ctx := context.Background()
+
+	audience := &client.Audience{
+		ID:                10,
+		Type:              client.AudienceType,
+		Description:       "New Audience",
+		Age:               30,
+		Gender:            "f",
+		BirthCountry:      "Greece",
+		HoursSpentOnMedia: 2,
+		NumberOfPurchases: 3,
+	}
+	added, err := c.AddUserFavorite(ctx, 1, audience)
+	assert.NoError(t, err)
+	if assert.IsType(t, &client.Audience{}, added) {
+		// The stored asset is returned as normalised by the API
+		assert.Equal(t, "26-40", added.(*client.Audience).AgeGroup)
+		assert.Equal(t, "Female", added.(*client.Audience).Gender)
+		assert.Equal(t, "GR", added.(*client.Audience).BirthCountry)
+	}
+
+	_, err = c.AddUserFavorite(ctx, 1, audience)
+	assert.ErrorIs(t, err, client.ErrAssetAlreadyExists)
+
+	invalid := *audience
+	invalid.ID = 11
+	invalid.Gender = "unknown"
+	_, err = c.AddUserFavorite(ctx, 1, &invalid)
+	assert.ErrorIs(t, err, client.ErrInvalidGender)
+
+	_, err = c.AddUserFavorite(ctx, 999, &client.Insight{ID: 12, Type: client.InsightType})
+	assert.ErrorIs(t, err, client.ErrUserNotFound)
+}
+
+func TestEditUserFavorite(t *testing.T) {
+	c := setup(t)
+	ctx := context.Background()
+
+	chart := client.Chart{
+		ID:          3,
+		Type:        client.ChartType,
+		Description: "Edited Chart",
+		Title:       "Edited",
+		DataPoints:  []client.Point{{X: 1, Y: 2}},
+	}
+	edited, err := c.EditUserFavorite(ctx, 1, 3, chart)
+	assert.NoError(t, err)
+	assert.Equal(t, &chart, edited)
+
+	_, err = c.EditUserFavorite(ctx, 1, 1, chart)
+	assert.ErrorIs(t, err, client.ErrAssetIDMismatch)
+
+	_, err = c.EditUserFavorite(ctx, 1, 1, client.Chart{ID: 1, Type: client.ChartType})
+	assert.ErrorIs(t, err, client.ErrAssetTypeMismatch)
+
+	_, err = c.EditUserFavorite(ctx, 1, 999, client.Chart{ID: 999, Type: client.ChartType})
+	assert.ErrorIs(t, err, client.ErrAssetNotFound)
+}
+
+func TestDeleteUserFavorite(t *testing.T) {
+	c := setup(t)
+	ctx := context.Background()
+
+	assert.NoError(t, c.DeleteUserFavorite(ctx, 1, 1))
+
+	err := c.DeleteUserFavorite(ctx, 1, 1)
+	assert.ErrorIs(t, err, client.ErrAssetNotFound)
+	assert.True(t, client.IsNotFound(err))
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RetriesUnavailable", func(t *testing.T) {
+		var requests int32
+		c := setup(t, failing(2, http.StatusServiceUnavailable, &requests))
+
+		_, err := c.GetUserFavorites(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), requests)
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		var requests int32
+		c := setup(t, failing(10, http.StatusBadGateway, &requests))
+		c.MaxRetries = 1
+
+		err := c.DeleteUserFavorite(ctx, 1, 1)
+		var apiErr *client.Error
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		assert.Equal(t, int32(2), requests)
+	})
+
+	t.Run("RetriedAdditionIsReplayed", func(t *testing.T) {
+		var keys []string
+		idempotency := middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour)
+		c := setup(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The first addition is stored but its response is lost by the gateway
+				keys = append(keys, r.Header.Get(middleware.IdempotencyKeyHeader))
+				if len(keys) == 1 {
+					next.ServeHTTP(httptest.NewRecorder(), r)
+					http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}, idempotency.Middleware)
+
+		asset, err := c.AddUserFavorite(ctx, 1, &client.Insight{ID: 10, Type: client.InsightType, Text: "Retried"})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, asset.GetID())
+		assert.Len(t, keys, 2)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+
+		// Another addition is sent with a new key, so it is not replayed
+		_, err = c.AddUserFavorite(ctx, 1, &client.Insight{ID: 10, Type: client.InsightType, Text: "Retried"})
+		assert.ErrorIs(t, err, client.ErrAssetAlreadyExists)
+		assert.Len(t, keys, 3)
+		assert.NotEqual(t, keys[0], keys[2])
+	})
+
+	t.Run("RetriesAdditionInProgress", func(t *testing.T) {
+		var requests int32
+		c := setup(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					http.Error(w, client.ErrRequestInProgress.Error(), http.StatusConflict)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		_, err := c.AddUserFavorite(ctx, 1, &client.Insight{ID: 10, Type: client.InsightType})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests)
+	})
+
+	t.Run("RetriedDeleteOfDeletedAssetSucceeds", func(t *testing.T) {
+		var requests int32
+		c := setup(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The first deletion is applied but its response is lost by the gateway
+				if atomic.AddInt32(&requests, 1) == 1 {
+					next.ServeHTTP(httptest.NewRecorder(), r)
+					http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		assert.NoError(t, c.DeleteUserFavorite(ctx, 1, 1))
+		assert.Equal(t, int32(2), requests)
+
+		err := c.DeleteUserFavorite(ctx, 1, 1)
+		assert.ErrorIs(t, err, client.ErrAssetNotFound)
+	})
+
+	t.Run("DoesNotRetryClientErrors", func(t *testing.T) {
+		var requests int32
+		c := setup(t, failing(0, 0, &requests))
+
+		_, err := c.GetUserFavorites(ctx, 999)
+		assert.ErrorIs(t, err, client.ErrUserNotFound)
+		assert.Equal(t, int32(1), requests)
+	})
+}
+
+func TestRateLimited(t *testing.T) {
+	limiter := middleware.NewRateLimiter(middleware.NewInMemoryRateLimitStore(), middleware.RateLimitConfig{
+		Enabled: true,
+		Default: middleware.Limit{Rate: 0.1, Burst: 1},
+	})
+	c := setup(t, limiter.Middleware)
+	c.MaxBackoff = 100 * time.Millisecond
+	ctx := context.Background()
+
+	_, err := c.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+
+	// The API asks to wait longer than MaxBackoff, so the client gives up without waiting
+	start := time.Now()
+	_, err = c.GetUserFavorites(ctx, 1)
+	assert.ErrorIs(t, err, client.ErrTooManyRequests)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeout(t *testing.T) {
+	var requests int32
+	slow := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	c := setup(t, slow)
+	c.Timeout = 20 * time.Millisecond
+	c.MaxRetries = 1
+
+	_, err := c.GetUserFavorites(context.Background(), 1)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
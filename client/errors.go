@@ -0,0 +1,67 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The errors returned by the API, which can be matched with errors.Is
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrAssetNotFound      = errors.New("asset not found")
+	ErrAssetAlreadyExists = errors.New("asset already exists")
+	ErrAssetIDMismatch    = errors.New("edited asset ID does not match existing asset ID")
+	ErrAssetTypeMismatch  = errors.New("edited asset type does not match existing asset type")
+	ErrInvalidAssetType   = errors.New("invalid asset type")
+	ErrInvalidAssetID     = errors.New("invalid asset id")
+	ErrInvalidDataPoint   = errors.New("invalid data point")
+	ErrInvalidGender      = errors.New("invalid gender")
+	ErrInvalidCountry     = errors.New("invalid birth country")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrRequestInProgress  = errors.New("a request with the same idempotency key is in progress")
+)
+
+// knownErrors are the errors above by the message the API responds with
+var knownErrors = map[string]error{}
+
+func init() {
+	for _, err := range []error{
+		ErrUserNotFound, ErrAssetNotFound, ErrAssetAlreadyExists, ErrAssetIDMismatch, ErrAssetTypeMismatch,
+		ErrInvalidAssetType, ErrInvalidAssetID, ErrInvalidDataPoint, ErrInvalidGender, ErrInvalidCountry, ErrTooManyRequests,
+		ErrRequestInProgress,
+	} {
+		knownErrors[err.Error()] = err
+	}
+}
+
+// Error is returned for the responses of the API that are not successful.
+// It unwraps to one of the errors above when the API responded with a known error.
+type Error struct {
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+// newError creates the Error of a response with the message the API responded with
+func newError(statusCode int, message string) *Error {
+	err := knownErrors[message]
+	if err == nil && statusCode == http.StatusTooManyRequests {
+		err = ErrTooManyRequests
+	}
+	return &Error{StatusCode: statusCode, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound reports whether err is an API response saying the user or asset does not exist
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -1,34 +1,299 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/ceciivanov/platform-go-challenge/internal/config"
+	"github.com/ceciivanov/platform-go-challenge/internal/gql"
+	"github.com/ceciivanov/platform-go-challenge/internal/grpcserver"
 	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
 	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
 	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 func main() {
 
+	// Load the configuration from the file given in GWI_CONFIG, or use the defaults
+	cfg, err := config.Load(os.Getenv("GWI_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Export the spans of the requests with the configured exporter, continuing the traces of the callers
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Try to change the number of users and assets to see how the application behaves with large data sets
-	NumberOfUsers := 2
-	NumberOfAssets := 3
+	NumberOfUsers := cfg.NumberOfUsers
+	NumberOfAssets := cfg.NumberOfAssets
+
+	// Use the users of the configured fixture, or generate the sample users
+	users, err := sampleUsers(cfg.Fixture, NumberOfUsers, NumberOfAssets)
+	if err != nil {
+		log.Fatalf("Failed to read the fixture: %v", err)
+	}
+
+	// Create and initialize the UserRepository of the configured database
+	var userRepo repository.UserRepository
+	switch cfg.Database.Driver {
+	case "memory":
+		repo := repository.NewInMemoryUserRepository()
+		repo.ReplaceUsers(context.Background(), users)
+		userRepo = repo
+	case "postgres":
+		pool, err := repository.OpenPostgres(context.Background(), cfg.Database.URL, repository.PostgresOptions{
+			MaxConns:        cfg.Database.MaxConns,
+			MinConns:        cfg.Database.MinConns,
+			MaxConnLifetime: time.Duration(cfg.Database.MaxConnLifetime),
+			MaxConnIdleTime: time.Duration(cfg.Database.MaxConnIdleTime),
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+		}
+		defer pool.Close()
+		if err := repository.MigratePostgres(context.Background(), pool); err != nil {
+			log.Fatalf("Failed to migrate the database: %v", err)
+		}
 
-	// Create and initialize a new UsersRepository instance
-	repo := repository.NewInMemoryUserRepository()
-	repo.GenerateSampleUsers(NumberOfUsers, NumberOfAssets)
+		// Save the sample users only in an empty database, the existing users are kept across restarts
+		repo := repository.NewPostgresUserRepository(pool)
+		ids, err := repo.GetUserIDs(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to read the users: %v", err)
+		}
+		if len(ids) == 0 {
+			if err := repo.ReplaceUsers(context.Background(), users); err != nil {
+				log.Fatalf("Failed to save the sample users: %v", err)
+			}
+		}
+		userRepo = repo
+	default:
+		log.Fatalf("Unknown database driver %q", cfg.Database.Driver)
+	}
+
+	// Cache the favourites of the users in process and on a shared Redis, if configured
+	if cfg.Cache.Size > 0 || cfg.Cache.RedisAddr != "" {
+		var local *cache.LRU
+		if cfg.Cache.Size > 0 {
+			local = cache.NewLRU(cfg.Cache.Size)
+		}
+		var remote cache.Cache
+		if cfg.Cache.RedisAddr != "" {
+			client := cache.NewRESPClient(cfg.Cache.RedisAddr)
+			client.Password = cfg.Cache.RedisPassword
+			client.DB = cfg.Cache.RedisDB
+			defer client.Close()
+			remote = client
+		}
+		cachedRepo := repository.NewCachedUserRepository(userRepo, local, remote)
+		cachedRepo.LocalTTL = time.Duration(cfg.Cache.TTL)
+		cachedRepo.RemoteTTL = time.Duration(cfg.Cache.RedisTTL)
+		userRepo = cachedRepo
+	}
 
 	// Create UserService and Handler for it
-	userService := service.NewUserService(repo)
+	userService := service.NewUserService(userRepo)
+	userService.TrashRetention = time.Duration(cfg.TrashRetention)
+	userService.AssetLimits = cfg.AssetLimits
+
+	// Append every favourite change to the audit log of the configured sink
+	switch cfg.Audit.Sink {
+	case "file":
+		if cfg.Audit.File == "" {
+			log.Fatalf("The file audit sink requires audit.file to be set")
+		}
+		auditSink, err := repository.OpenFileAuditSink(cfg.Audit.File)
+		if err != nil {
+			log.Fatalf("Failed to open the audit log: %v", err)
+		}
+		defer auditSink.Close()
+		userService.AuditSink = auditSink
+		if cfg.Audit.KeyFile != "" {
+			if userService.AuditKeys, err = repository.OpenFileAuditKeyStore(cfg.Audit.KeyFile); err != nil {
+				log.Fatalf("Failed to open the audit keys: %v", err)
+			}
+		}
+	case "memory":
+		userService.AuditSink = repository.NewInMemoryAuditSink(cfg.Audit.MaxRecords)
+		userService.AuditKeys = repository.NewInMemoryAuditKeyStore()
+	case "none":
+		userService.AuditSink = nil
+	default:
+		log.Fatalf("Unknown audit sink %q", cfg.Audit.Sink)
+	}
+	userService.AuditErrorHandler = func(err error) {
+		log.Printf("Failed to append a change to the audit log: %v", err)
+	}
 	userHandler := handlers.NewUserHandler(userService)
+	referenceHandler := handlers.NewReferenceHandler()
+
+	// Create CollectionService and Handler for the collections shared between the same users
+	collectionService := service.NewCollectionService(userRepo, repository.NewInMemoryCollectionRepository())
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+
+	// Deliver the favourite changes to the webhook subscriptions
+	webhookService := service.NewWebhookService(repository.NewInMemoryWebhookRepository())
+	webhookService.AllowPrivateTargets = cfg.Webhooks.AllowPrivateTargets
+	userService.AddListener(webhookService.Notify)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, cfg.Admin.Keys)
+
+	// Stream the favourite changes to the connected clients
+	eventStream := service.NewEventStream(service.DefaultEventLogSize)
+	userService.AddListener(eventStream.Publish)
+	eventStreamHandler := handlers.NewEventStreamHandler(userService, eventStream)
+
+	// Serve the same UserService over GraphQL
+	schema, err := gql.NewSchema(userService)
+	if err != nil {
+		log.Fatalf("Failed to parse the GraphQL schema: %v", err)
+	}
+	graphQLHandler := handlers.NewGraphQLHandler(schema)
 
 	// Create a new router from the Gorilla Mux package and register the respective routes for the userHandler
 	r := mux.NewRouter()
 	userHandler.RegisterRoutes(r)
+	referenceHandler.RegisterRoutes(r)
+	collectionHandler.RegisterRoutes(r)
+	webhookHandler.RegisterRoutes(r)
+	eventStreamHandler.RegisterRoutes(r)
+	graphQLHandler.RegisterRoutes(r)
+
+	rateLimiter := useMiddleware(r, cfg)
+
+	// Serve the admin API to the operators holding one of the admin keys
+	if len(cfg.Admin.Keys) > 0 {
+		adminService := service.NewAdminService(userService, collectionService)
+		adminService.WebhookService = webhookService
+		adminService.EventStream = eventStream
+		adminService.SnapshotDir = cfg.Admin.SnapshotDir
+		adminService.Reloader = configReloader(os.Getenv("GWI_CONFIG"), cfg, rateLimiter)
+		if cfg.Admin.ErasureFile != "" {
+			erasures, err := repository.OpenFileErasureRepository(cfg.Admin.ErasureFile)
+			if err != nil {
+				log.Fatalf("Failed to open the erasure records: %v", err)
+			}
+			defer erasures.Close()
+			adminService.ErasureRepository = erasures
+		}
+		handlers.NewAdminHandler(adminService, cfg.Admin.Keys).RegisterRoutes(r)
+	}
+
+	// Permanently delete the favourites that stayed in the trash longer than the retention
+	go func() {
+		for now := range time.Tick(time.Hour) {
+			if purged, err := userService.PurgeExpiredTrash(now); err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d expired favorites from the trash", purged)
+			}
+		}
+	}()
+
+	// Serve the same UserService over gRPC next to the REST API
+	if cfg.GRPCAddr != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s: %v", cfg.GRPCAddr, err)
+		}
+		grpcServer := grpc.NewServer()
+		grpcserver.NewServer(userService).Register(grpcServer)
+		go func() {
+			fmt.Printf("gRPC server is running on %s...\n", cfg.GRPCAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
 
 	// Start the server
-	fmt.Println("Server is running on port 8080...")
-	http.ListenAndServe(":8080", r)
+	fmt.Printf("Server is running on %s...\n", cfg.Addr)
+	http.ListenAndServe(cfg.Addr, r)
+}
+
+// useMiddleware adds the middleware shared by all routes to the router, in the order they handle the requests,
+// and returns the rate limiter, whose limits can be reloaded
+func useMiddleware(r *mux.Router, cfg config.Config) *middleware.RateLimiter {
+	// Identify every request, so its changes can be found in the audit log
+	r.Use(middleware.RequestID)
+
+	// Trace every request, from the router down to the repository
+	r.Use(middleware.Tracing)
+
+	// Verify the API keys before the rate limiter, so their holders are limited by key rather than by IP address
+	r.Use(middleware.IdentifyAPIKeys(cfg.Admin.Keys))
+
+	// Limit the request rate of every client per route
+	rateLimiter := middleware.NewRateLimiter(middleware.NewInMemoryRateLimitStore(), cfg.RateLimit)
+	r.Use(rateLimiter.Middleware)
+
+	// Reject the request bodies larger than the configured limit
+	r.Use(middleware.LimitBody(cfg.MaxBodySize))
+
+	// Compress the larger responses for the clients accepting gzip or zstd
+	r.Use(middleware.NewCompressor(cfg.Compression).Middleware)
+
+	// Replay the responses of the changes retried with the same Idempotency-Key
+	idempotency := middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Duration(cfg.IdempotencyTTL))
+	r.Use(idempotency.Middleware)
+	return rateLimiter
+}
+
+// sampleUsers reads the users of a fixture file written by cmd/seed,
+// or generates NumberOfUsers users with NumberOfAssets assets each if there is no fixture
+func sampleUsers(fixture string, NumberOfUsers, NumberOfAssets int) (map[int]models.User, error) {
+	if fixture == "" {
+		return mock_data.GenerateMockData(NumberOfUsers, NumberOfAssets), nil
+	}
+	file, err := os.Open(fixture)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return mock_data.ReadFixture(file)
+}
+
+// configReloader reloads the configuration file, applying the rate limits to the running server.
+// The other changed settings are reported as only taking effect after a restart.
+func configReloader(path string, current config.Config, rateLimiter *middleware.RateLimiter) service.ConfigReloader {
+	var mu sync.Mutex
+	return func(ctx context.Context) (service.ReloadResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		cfg, err := config.Load(path)
+		if err != nil {
+			return service.ReloadResult{}, err
+		}
+
+		result := service.ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+		for _, name := range config.Diff(current, cfg) {
+			switch name {
+			case "rateLimit":
+				rateLimiter.SetConfig(cfg.RateLimit)
+				current.RateLimit = cfg.RateLimit
+				result.Applied = append(result.Applied, name)
+			default:
+				result.RestartRequired = append(result.RestartRequired, name)
+			}
+		}
+		log.Printf("Reloaded configuration, applied %v, restart required for %v", result.Applied, result.RestartRequired)
+		return result, nil
+	}
 }
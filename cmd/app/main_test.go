@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/config"
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareLimitsAPIKeyHolders(t *testing.T) {
+	cfg := config.Default()
+	cfg.Admin.Keys = map[string]string{"ops": "secret"}
+	cfg.RateLimit.Routes = map[string]middleware.Limit{"GET /users/{id}/favorites": {Rate: 0.001, Burst: 1}}
+
+	repo := repository.NewInMemoryUserRepository()
+	repo.ReplaceUsers(context.Background(), mock_data.GenerateMockData(1, 1))
+	r := mux.NewRouter()
+	handlers.NewUserHandler(service.NewUserService(repo)).RegisterRoutes(r)
+	useMiddleware(r, cfg)
+
+	send := func(remoteAddr, key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/users/1/favorites", nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set(middleware.APIKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Test the holder of an API key is limited by key, whatever address it sends from
+	assert.Equal(t, http.StatusOK, send("192.0.2.1:1234", "secret"))
+	assert.Equal(t, http.StatusTooManyRequests, send("198.51.100.7:1234", "secret"))
+
+	// Test the other clients are limited by address, including the ones sending an unverified key
+	assert.Equal(t, http.StatusOK, send("198.51.100.7:1234", ""))
+	assert.Equal(t, http.StatusOK, send("203.0.113.5:1234", "unknown-1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("203.0.113.5:1234", "unknown-2"))
+}
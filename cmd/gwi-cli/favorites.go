@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// importFailure is an asset that could not be imported
+type importFailure struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
+}
+
+// importResult reports which assets were imported
+type importResult struct {
+	Added   []int           `json:"added"`
+	Updated []int           `json:"updated"`
+	Failed  []importFailure `json:"failed"`
+}
+
+// listFavorites prints the favourites of a user ordered by ID, optionally only those of a type
+func (a *app) listFavorites(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("favorites list", flag.ContinueOnError)
+	assetType := flags.String("type", "", "only list the assets of a type (Chart, Insight or Audience)")
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+
+	assets, err := a.favorites(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	filtered := assets[:0]
+	for _, asset := range assets {
+		if *assetType == "" || string(asset.GetType()) == *assetType {
+			filtered = append(filtered, asset)
+		}
+	}
+	return a.printAssets(filtered)
+}
+
+// addFavorite adds the asset of a file to the favourites of a user
+func (a *app) addFavorite(ctx context.Context, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("favorites add", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	asset, err := a.readAsset(args[1])
+	if err != nil {
+		return err
+	}
+
+	added, err := a.client.AddUserFavorite(ctx, userID, asset)
+	if err != nil {
+		return err
+	}
+	return a.printAssets([]client.Asset{added})
+}
+
+// editFavorite replaces a favourite of a user with the asset of a file
+func (a *app) editFavorite(ctx context.Context, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("favorites edit", flag.ContinueOnError), args, 3, 3)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	assetID, err := parseID("asset", args[1])
+	if err != nil {
+		return err
+	}
+	asset, err := a.readAsset(args[2])
+	if err != nil {
+		return err
+	}
+
+	edited, err := a.client.EditUserFavorite(ctx, userID, assetID, asset)
+	if err != nil {
+		return err
+	}
+	return a.printAssets([]client.Asset{edited})
+}
+
+// deleteFavorite deletes a favourite of a user
+func (a *app) deleteFavorite(ctx context.Context, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("favorites rm", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	assetID, err := parseID("asset", args[1])
+	if err != nil {
+		return err
+	}
+
+	return a.client.DeleteUserFavorite(ctx, userID, assetID)
+}
+
+// exportFavorites writes the favourites of a user as a JSON array, which can be imported again
+func (a *app) exportFavorites(ctx context.Context, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("favorites export", flag.ContinueOnError), args, 1, 2)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+
+	assets, err := a.favorites(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	out := a.stdout
+	if len(args) == 2 && args[1] != "-" {
+		file, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	return writeJSON(out, assets)
+}
+
+// importFavorites adds the assets of a JSON array to the favourites of a user.
+// Every asset is tried, and the command fails if any of them could not be imported.
+func (a *app) importFavorites(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("favorites import", flag.ContinueOnError)
+	update := flags.Bool("update", false, "edit the assets that are already favourites instead of failing")
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	assets, err := a.readAssets(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := importResult{Added: []int{}, Updated: []int{}, Failed: []importFailure{}}
+	for _, asset := range assets {
+		_, err := a.client.AddUserFavorite(ctx, userID, asset)
+		if *update && errors.Is(err, client.ErrAssetAlreadyExists) {
+			if _, err = a.client.EditUserFavorite(ctx, userID, asset.GetID(), asset); err == nil {
+				result.Updated = append(result.Updated, asset.GetID())
+				continue
+			}
+		}
+		if err != nil {
+			// A missing user or an unreachable API fails every asset, so there is no point going on
+			if code := exitCode(err); code == exitNotFound || code == exitUnavailable {
+				return err
+			}
+			result.Failed = append(result.Failed, importFailure{ID: asset.GetID(), Error: err.Error()})
+			continue
+		}
+		result.Added = append(result.Added, asset.GetID())
+	}
+
+	if err := a.printImportResult(result); err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d assets could not be imported", len(result.Failed), len(assets))
+	}
+	return nil
+}
+
+// favorites returns the favourites of a user ordered by ID
+func (a *app) favorites(ctx context.Context, userID int) ([]client.Asset, error) {
+	favorites, err := a.client.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]client.Asset, 0, len(favorites))
+	for _, asset := range favorites {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].GetID() < assets[j].GetID() })
+	return assets, nil
+}
+
+// readAsset reads an asset from a file
+func (a *app) readAsset(name string) (client.Asset, error) {
+	data, err := a.readFile(name)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := utils.DecodeAsset(data)
+	if err != nil {
+		return nil, &inputError{name, err}
+	}
+	return asset, nil
+}
+
+// readAssets reads a JSON array of assets, or a single asset, from a file
+func (a *app) readAssets(name string) ([]client.Asset, error) {
+	data, err := a.readFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		asset, err := utils.DecodeAsset(data)
+		if err != nil {
+			return nil, &inputError{name, err}
+		}
+		return []client.Asset{asset}, nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, &inputError{name, err}
+	}
+	assets := make([]client.Asset, len(raw))
+	for i, item := range raw {
+		if assets[i], err = utils.DecodeAsset(item); err != nil {
+			return nil, &inputError{fmt.Sprintf("%s: asset %d", name, i+1), err}
+		}
+	}
+	return assets, nil
+}
+
+// readFile reads a file, or the standard input for "-"
+func (a *app) readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(a.stdin)
+	}
+	return os.ReadFile(name)
+}
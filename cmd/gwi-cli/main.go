@@ -0,0 +1,220 @@
+// Command gwi-cli manages users' favourites through the HTTP API.
+//
+// Usage:
+//
+//	gwi-cli [flags] favorites list [-type TYPE] USER
+//	gwi-cli [flags] favorites add USER FILE
+//	gwi-cli [flags] favorites edit USER ASSET FILE
+//	gwi-cli [flags] favorites rm USER ASSET
+//	gwi-cli [flags] favorites export USER [FILE]
+//	gwi-cli [flags] favorites import [-update] USER FILE
+//	gwi-cli [flags] users list
+//	gwi-cli [flags] users show USER
+//
+// Asset files hold a JSON asset like those in the json directory, or a JSON array of assets for import.
+// A FILE of "-" reads from the standard input or writes to the standard output.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+)
+
+// Exit codes of the command
+const (
+	exitOK          = 0
+	exitError       = 1 // exitError is used for unexpected errors and partially failed imports
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitInvalid     = 4 // exitInvalid is used for invalid asset files and requests the API rejects
+	exitUnavailable = 5 // exitUnavailable is used when the API cannot be reached or fails
+)
+
+const usage = `Usage: gwi-cli [flags] <command> <subcommand> [args]
+
+Commands:
+  favorites list [-type TYPE] USER       List the favourites of a user
+  favorites add USER FILE                Add the asset in FILE to the favourites of a user
+  favorites edit USER ASSET FILE         Replace a favourite with the asset in FILE
+  favorites rm USER ASSET                Delete a favourite
+  favorites export USER [FILE]           Write the favourites of a user as a JSON array
+  favorites import [-update] USER FILE   Add the assets of a JSON array, editing existing ones with -update
+  users list                             List the IDs of all users
+  users show USER                        Show how many favourites of each type a user has
+
+Flags:
+`
+
+// usageError is returned for invalid command lines
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// inputError is returned for asset files that cannot be decoded
+type inputError struct {
+	name string
+	err  error
+}
+
+func (e *inputError) Error() string {
+	return e.name + ": " + e.err.Error()
+}
+
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// app holds what the commands need to run
+type app struct {
+	client *client.Client
+	format string
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gwi-cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	server := flags.String("server", envOr("GWI_SERVER", "http://localhost:8080"), "base URL of the API (env GWI_SERVER)")
+	apiKey := flags.String("api-key", os.Getenv("GWI_API_KEY"), "API key identifying the caller (env GWI_API_KEY)")
+	format := flags.String("o", "table", "output format: table, json or yaml")
+	timeout := flags.Duration("timeout", client.DefaultTimeout, "timeout of every attempt of a request")
+	retries := flags.Int("retries", client.DefaultMaxRetries, "how many times failed requests are retried when safe")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if *format != "table" && *format != "json" && *format != "yaml" {
+		fmt.Fprintf(stderr, "gwi-cli: invalid output format %q\n", *format)
+		return exitUsage
+	}
+
+	c := client.NewClient(*server)
+	c.APIKey = *apiKey
+	c.Timeout = *timeout
+	c.MaxRetries = *retries
+
+	a := &app{client: c, format: *format, stdin: stdin, stdout: stdout}
+	err := a.dispatch(context.Background(), flags.Args())
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "gwi-cli: %v\n", err)
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		flags.Usage()
+	}
+	return exitCode(err)
+}
+
+// dispatch runs the command named by the first arguments
+func (a *app) dispatch(ctx context.Context, args []string) error {
+	if len(args) < 2 {
+		return &usageError{"missing command"}
+	}
+
+	command, subcommand, args := args[0], args[1], args[2:]
+	switch command + " " + subcommand {
+	case "favorites list":
+		return a.listFavorites(ctx, args)
+	case "favorites add":
+		return a.addFavorite(ctx, args)
+	case "favorites edit":
+		return a.editFavorite(ctx, args)
+	case "favorites rm":
+		return a.deleteFavorite(ctx, args)
+	case "favorites export":
+		return a.exportFavorites(ctx, args)
+	case "favorites import":
+		return a.importFavorites(ctx, args)
+	case "users list":
+		return a.listUsers(ctx, args)
+	case "users show":
+		return a.showUser(ctx, args)
+	}
+	return &usageError{fmt.Sprintf("unknown command %q", command+" "+subcommand)}
+}
+
+// exitCode returns the exit code reporting an error
+func exitCode(err error) int {
+	var usageErr *usageError
+	var inputErr *inputError
+	var apiErr *client.Error
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &inputErr):
+		return exitInvalid
+	case errors.As(err, &apiErr):
+		switch {
+		case client.IsNotFound(err):
+			return exitNotFound
+		case apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500:
+			return exitUnavailable
+		case apiErr.StatusCode >= 400:
+			return exitInvalid
+		}
+	case errors.As(err, &urlErr):
+		// The HTTP client returns url.Errors for requests that got no response
+		return exitUnavailable
+	}
+	return exitError
+}
+
+// parseArgs parses the flags of a subcommand and checks it got between min and max positional arguments.
+// The flag set is named after the subcommand, which is used in the errors.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return nil, &usageError{err.Error()}
+	}
+	if flags.NArg() < min || flags.NArg() > max {
+		return nil, &usageError{"wrong number of arguments for " + flags.Name()}
+	}
+	return flags.Args(), nil
+}
+
+// parseID parses a user or asset ID argument
+func parseID(name, value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		return 0, &usageError{fmt.Sprintf("invalid %s %q", name, value)}
+	}
+	return id, nil
+}
+
+// envOr returns the value of an environment variable, or fallback when it is not set
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
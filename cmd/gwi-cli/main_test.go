@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupServer starts the API with the given number of users and assets each
+func setupServer(t *testing.T, users, assets int) *httptest.Server {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(users, assets)
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(service.NewUserService(repo)).RegisterRoutes(r)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runCLI runs the command against a server and returns its exit code, output and errors
+func runCLI(server *httptest.Server, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-server", server.URL}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestFavoritesCommands(t *testing.T) {
+	server := setupServer(t, 1, 0)
+
+	code, stdout, _ := runCLI(server, "", "favorites", "add", "1", "../../json/chart.json")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "Chart (2 points)")
+
+	code, _, _ = runCLI(server, "", "favorites", "add", "1", "../../json/insight.json")
+	assert.Equal(t, exitOK, code)
+
+	code, stdout, _ = runCLI(server, "", "-o", "json", "favorites", "list", "1")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, `"title": "Chart"`)
+	assert.Contains(t, stdout, `"text": "This is an insight"`)
+
+	code, stdout, _ = runCLI(server, "", "-o", "yaml", "favorites", "list", "-type", "Insight", "1")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "- id: 1\n  type: Insight\n  description: This is an insight\n  text: This is an insight\n", stdout)
+
+	edited := `{"id": 1, "type": "Insight", "description": "Edited", "text": "Edited insight"}`
+	code, stdout, _ = runCLI(server, edited, "favorites", "edit", "1", "1", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "Edited insight")
+
+	code, stdout, _ = runCLI(server, "", "favorites", "rm", "1", "3")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+
+	code, stdout, _ = runCLI(server, "", "favorites", "list", "1")
+	assert.Equal(t, exitOK, code)
+	assert.NotContains(t, stdout, "Chart")
+}
+
+func TestExportImport(t *testing.T) {
+	server := setupServer(t, 2, 3)
+	file := filepath.Join(t.TempDir(), "favorites.json")
+
+	code, _, _ := runCLI(server, "", "favorites", "export", "1", file)
+	assert.Equal(t, exitOK, code)
+
+	// The second user has favourites with the same IDs, so they can only be imported with -update
+	code, stdout, stderr := runCLI(server, "", "favorites", "import", "2", file)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stdout, "failed: asset already exists")
+	assert.Contains(t, stderr, "3 of 3 assets could not be imported")
+
+	code, stdout, _ = runCLI(server, "", "-o", "json", "favorites", "import", "-update", "2", file)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, `"updated": [`)
+
+	exported, _ := os.ReadFile(file)
+	code, stdout, _ = runCLI(server, "", "favorites", "export", "2")
+	assert.Equal(t, exitOK, code)
+	assert.JSONEq(t, string(exported), stdout)
+}
+
+func TestUsersCommands(t *testing.T) {
+	server := setupServer(t, 2, 3)
+
+	code, stdout, _ := runCLI(server, "", "-o", "json", "users", "list")
+	assert.Equal(t, exitOK, code)
+	assert.JSONEq(t, "[1, 2]", stdout)
+
+	code, stdout, _ = runCLI(server, "", "users", "show", "1")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "ID  FAVORITES  CHARTS  INSIGHTS  AUDIENCES\n1   3          1       1         1")
+}
+
+func TestExitCodes(t *testing.T) {
+	server := setupServer(t, 1, 3)
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	os.WriteFile(invalid, []byte(`{"id": 9, "type": "Unknown"}`), 0o644)
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"MissingCommand", []string{"favorites"}, exitUsage},
+		{"UnknownCommand", []string{"favorites", "move", "1"}, exitUsage},
+		{"InvalidUserID", []string{"favorites", "list", "abc"}, exitUsage},
+		{"WrongNumberOfArguments", []string{"favorites", "rm", "1"}, exitUsage},
+		{"InvalidOutputFormat", []string{"-o", "xml", "users", "list"}, exitUsage},
+		{"UserNotFound", []string{"favorites", "list", "999"}, exitNotFound},
+		{"AssetNotFound", []string{"favorites", "edit", "1", "999", "../../json/chart.json"}, exitNotFound},
+		{"AssetAlreadyExists", []string{"favorites", "add", "1", "../../json/chart.json"}, exitInvalid},
+		{"InvalidAssetFile", []string{"favorites", "add", "1", invalid}, exitInvalid},
+		{"MissingAssetFile", []string{"favorites", "add", "1", "missing.json"}, exitError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, _, _ := runCLI(server, "", tc.args...)
+			assert.Equal(t, tc.code, code)
+		})
+	}
+
+	t.Run("Unavailable", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-server", "http://127.0.0.1:1", "users", "list"}, nil, &stdout, &stderr)
+		assert.Equal(t, exitUnavailable, code)
+	})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+)
+
+// maxColumnWidth is the longest text shown in a table column before it is truncated
+const maxColumnWidth = 50
+
+// print writes a value in the JSON or YAML output format
+func (a *app) print(v interface{}) error {
+	if a.format == "yaml" {
+		return writeYAML(a.stdout, v)
+	}
+	return writeJSON(a.stdout, v)
+}
+
+// printAssets writes assets in the output format, as a table with the main fields of each type by default
+func (a *app) printAssets(assets []client.Asset) error {
+	if a.format != "table" {
+		return a.print(assets)
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tDESCRIPTION\tDETAILS")
+	for _, asset := range assets {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", asset.GetID(), asset.GetType(), truncate(asset.GetDescription()), truncate(details(asset)))
+	}
+	return tw.Flush()
+}
+
+// printImportResult writes the result of an import in the output format
+func (a *app) printImportResult(result importResult) error {
+	if a.format != "table" {
+		return a.print(result)
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tRESULT")
+	for _, id := range result.Added {
+		fmt.Fprintf(tw, "%d\tadded\n", id)
+	}
+	for _, id := range result.Updated {
+		fmt.Fprintf(tw, "%d\tupdated\n", id)
+	}
+	for _, failure := range result.Failed {
+		fmt.Fprintf(tw, "%d\tfailed: %s\n", failure.ID, failure.Error)
+	}
+	return tw.Flush()
+}
+
+// details summarises the fields specific to the type of an asset
+func details(asset client.Asset) string {
+	switch a := asset.(type) {
+	case *client.Chart:
+		return fmt.Sprintf("%s (%d points)", a.Title, len(a.DataPoints))
+	case *client.Insight:
+		return a.Text
+	case *client.Audience:
+		return fmt.Sprintf("%s, %d (%s), %s", a.Gender, a.Age, a.AgeGroup, a.BirthCountry)
+	}
+	return ""
+}
+
+// truncate shortens text to fit a table column, keeping it on a single line
+func truncate(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxColumnWidth {
+		return string(runes[:maxColumnWidth-3]) + "..."
+	}
+	return text
+}
+
+// writeJSON writes a value as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeYAML writes a value as YAML with the same field names and order as its JSON
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so decoding it keeps the field names and order, and clearing its flow
+	// and quoting styles makes the encoder write it back as block YAML
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearStyle resets the style of a YAML node and its children to the default block style
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
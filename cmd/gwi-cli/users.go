@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+)
+
+// userSummary counts the favourites of a user by type
+type userSummary struct {
+	ID        int `json:"id"`
+	Favorites int `json:"favorites"`
+	Charts    int `json:"charts"`
+	Insights  int `json:"insights"`
+	Audiences int `json:"audiences"`
+}
+
+// listUsers prints the IDs of all users
+func (a *app) listUsers(ctx context.Context, args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("users list", flag.ContinueOnError), args, 0, 0); err != nil {
+		return err
+	}
+
+	ids, err := a.client.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.format != "table" {
+		return a.print(ids)
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID")
+	for _, id := range ids {
+		fmt.Fprintln(tw, id)
+	}
+	return tw.Flush()
+}
+
+// showUser prints how many favourites of each type a user has
+func (a *app) showUser(ctx context.Context, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("users show", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+
+	favorites, err := a.client.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	summary := userSummary{ID: userID, Favorites: len(favorites)}
+	for _, asset := range favorites {
+		switch asset.GetType() {
+		case client.ChartType:
+			summary.Charts++
+		case client.InsightType:
+			summary.Insights++
+		case client.AudienceType:
+			summary.Audiences++
+		}
+	}
+
+	if a.format != "table" {
+		return a.print(summary)
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tFAVORITES\tCHARTS\tINSIGHTS\tAUDIENCES")
+	fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\n", summary.ID, summary.Favorites, summary.Charts, summary.Insights, summary.Audiences)
+	return tw.Flush()
+}
@@ -0,0 +1,123 @@
+// Command loadgen drives a running server over HTTP with a mixed workload of favourite listings, additions,
+// edits and deletions, and reports the throughput and latency percentiles of every operation.
+//
+// Usage:
+//
+//	loadgen [flags]
+//
+// Every worker sends one request after the other over its own connection. The workers start evenly spread
+// over the ramp-up, then all of them run for the duration. Users are picked uniformly, or following a Zipf
+// distribution with -zipf so a few users get most of the requests. Workers only edit and delete the assets
+// they added, and the assets left when the run ends are deleted, so the data set is the same after a run.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+)
+
+// Exit codes of the command
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	server := flags.String("server", envOr("GWI_SERVER", "http://localhost:8080"), "base URL of the API (env GWI_SERVER)")
+	apiKey := flags.String("api-key", os.Getenv("GWI_API_KEY"), "API key identifying the caller (env GWI_API_KEY)")
+	mix := flags.String("mix", "list=80,add=10,edit=5,delete=5", "weights of the operations: list, add, edit and delete")
+	concurrency := flags.Int("c", 16, "number of concurrent workers")
+	duration := flags.Duration("d", 30*time.Second, "how long to run once all workers started")
+	rampUp := flags.Duration("ramp-up", 5*time.Second, "time over which the workers start")
+	zipf := flags.Float64("zipf", 0, "skew of the users' popularity, above 1 (e.g. 1.1), 0 picks users uniformly")
+	seed := flags.Int64("seed", 1, "seed of the operations, users and assets picked")
+	timeout := flags.Duration("timeout", client.DefaultTimeout, "timeout of every request")
+	format := flags.String("o", "text", "report format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "loadgen: unexpected arguments %q\n", flags.Args())
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "loadgen: invalid report format %q\n", *format)
+		return exitUsage
+	}
+	operationMix, err := parseMix(*mix)
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return exitUsage
+	}
+
+	// Failed requests are not retried, so the latencies are the ones of single requests
+	c := client.NewClient(*server)
+	c.APIKey = *apiKey
+	c.Timeout = *timeout
+	c.MaxRetries = 0
+	c.HTTPClient = &http.Client{Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        *concurrency,
+		MaxIdleConnsPerHost: *concurrency,
+		IdleConnTimeout:     90 * time.Second,
+	}}
+
+	workload := &Workload{
+		Client:      c,
+		Mix:         operationMix,
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		RampUp:      *rampUp,
+		Zipf:        *zipf,
+		Seed:        *seed,
+	}
+	fmt.Fprintf(stderr, "loadgen: running %d workers against %s for %s after a %s ramp-up\n", *concurrency, *server, *duration, *rampUp)
+	report, err := workload.Run(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return exitError
+	}
+
+	if *format == "json" {
+		err = report.WriteJSON(stdout)
+	} else {
+		err = report.WriteText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// envOr returns the value of an environment variable, or fallback if it is not set
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
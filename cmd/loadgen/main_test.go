@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupServer starts the API with the given number of users and assets each
+func setupServer(t *testing.T, users, assets int) (*httptest.Server, *repository.InMemoryUserRepository) {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(users, assets)
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(service.NewUserService(repo)).RegisterRoutes(r)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, repo
+}
+
+// countFavorites returns the number of favourites of every user
+func countFavorites(repo *repository.InMemoryUserRepository) map[int]int {
+	counts := make(map[int]int)
+	ids, _ := repo.GetUserIDs(context.Background())
+	for _, id := range ids {
+		favorites, _ := repo.GetUserFavorites(context.Background(), id)
+		counts[id] = len(favorites)
+	}
+	return counts
+}
+
+func TestLoadgen(t *testing.T) {
+	server, repo := setupServer(t, 10, 5)
+	before := countFavorites(repo)
+
+	// Test a run reports every operation of the mix without errors
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-server", server.URL, "-c", "4", "-d", "300ms", "-ramp-up", "100ms",
+		"-zipf", "1.2", "-mix", "list=2,add=2,edit=1,delete=1", "-o", "json"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(t, 4, report.Concurrency)
+	assert.Greater(t, report.Requests, 0)
+	assert.Zero(t, report.Errors, report.Statuses)
+	assert.InDelta(t, 0.4, report.Duration, 0.2)
+	for _, op := range operations {
+		stats := report.Operations[op]
+		assert.Greater(t, stats.Requests, 0, op)
+		assert.Equal(t, stats.Requests, report.Statuses[op]["ok"], op)
+		assert.LessOrEqual(t, stats.P50, stats.P95, op)
+		assert.LessOrEqual(t, stats.P95, stats.P99, op)
+		assert.LessOrEqual(t, stats.P99, stats.Max, op)
+	}
+
+	// Test the added assets were deleted at the end
+	assert.Equal(t, before, countFavorites(repo))
+
+	// Test the text report
+	stdout.Reset()
+	code = run(context.Background(), []string{"-server", server.URL, "-c", "2", "-d", "50ms", "-ramp-up", "0", "-mix", "list=1"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "operation  requests  errors")
+	assert.Contains(t, stdout.String(), "list: ok=")
+	assert.NotContains(t, stdout.String(), "add")
+}
+
+func TestLoadgenErrors(t *testing.T) {
+	server, _ := setupServer(t, 0, 0)
+	var stdout, stderr bytes.Buffer
+
+	for _, args := range [][]string{
+		{"-mix", "list=1,upload=1"},
+		{"-mix", "list"},
+		{"-mix", "list=0"},
+		{"-o", "xml"},
+		{"extra"},
+	} {
+		assert.Equal(t, exitUsage, run(context.Background(), args, &stdout, &stderr), args)
+	}
+
+	// Test a server without users, an invalid skew and an unreachable server
+	assert.Equal(t, exitError, run(context.Background(), []string{"-server", server.URL, "-d", "10ms"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "the server has no users")
+	assert.Equal(t, exitError, run(context.Background(), []string{"-server", server.URL, "-zipf", "0.5"}, &stdout, &stderr))
+	server.Close()
+	assert.Equal(t, exitError, run(context.Background(), []string{"-server", server.URL, "-timeout", "1s"}, &stdout, &stderr))
+}
+
+func TestZipfSkew(t *testing.T) {
+	users := make([]int, 100)
+	for i := range users {
+		users[i] = i + 1
+	}
+	r := rand.New(rand.NewSource(1))
+	uniform := &worker{users: users, rand: r}
+	skewed := &worker{users: users, rand: r, zipf: rand.NewZipf(r, 1.5, 1, uint64(len(users)-1))}
+
+	// Test the most popular user gets a large share of the requests only with a Zipf distribution
+	counts := func(wk *worker) int {
+		picks := make(map[int]int)
+		for i := 0; i < 10000; i++ {
+			picks[wk.user()]++
+		}
+		return picks[users[0]]
+	}
+	assert.Less(t, counts(uniform), 300)
+	assert.Greater(t, counts(skewed), 3000)
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+	s := stats(latencies, 1, time.Second)
+	assert.Equal(t, 100, s.Requests)
+	assert.Equal(t, 1, s.Errors)
+	assert.Equal(t, 100.0, s.Throughput)
+	assert.Equal(t, 50.0, s.P50)
+	assert.Equal(t, 95.0, s.P95)
+	assert.Equal(t, 99.0, s.P99)
+	assert.Equal(t, 100.0, s.Max)
+	assert.Equal(t, 50.5, s.Mean)
+}
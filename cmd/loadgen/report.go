@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Recorder records the latencies and outcomes of the requests of each operation
+type Recorder struct {
+	latencies map[string][]time.Duration
+	statuses  map[string]map[string]int
+}
+
+// NewRecorder creates a new, empty Recorder. A Recorder is used by a single worker, and merged with the others at the end.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		latencies: make(map[string][]time.Duration),
+		statuses:  make(map[string]map[string]int),
+	}
+}
+
+// Record records a request of an operation that took latency and ended with err
+func (r *Recorder) Record(op string, latency time.Duration, err error) {
+	r.latencies[op] = append(r.latencies[op], latency)
+	if r.statuses[op] == nil {
+		r.statuses[op] = make(map[string]int)
+	}
+	r.statuses[op][statusOf(err)]++
+}
+
+// Merge adds the requests recorded by other
+func (r *Recorder) Merge(other *Recorder) {
+	for op, latencies := range other.latencies {
+		r.latencies[op] = append(r.latencies[op], latencies...)
+	}
+	for op, statuses := range other.statuses {
+		if r.statuses[op] == nil {
+			r.statuses[op] = make(map[string]int)
+		}
+		for status, n := range statuses {
+			r.statuses[op][status] += n
+		}
+	}
+}
+
+// Report summarises a run
+type Report struct {
+	Duration    float64                   `json:"durationSeconds"`
+	Concurrency int                       `json:"concurrency"`
+	Requests    int                       `json:"requests"`
+	Errors      int                       `json:"errors"`
+	Throughput  float64                   `json:"throughput"` // requests per second
+	Total       Stats                     `json:"total"`
+	Operations  map[string]Stats          `json:"operations"`
+	Statuses    map[string]map[string]int `json:"statuses"` // requests by operation and outcome: "ok", an HTTP status code or "network"
+}
+
+// Stats summarises the latencies of requests, in milliseconds
+type Stats struct {
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	Mean       float64 `json:"meanMs"`
+	P50        float64 `json:"p50Ms"`
+	P95        float64 `json:"p95Ms"`
+	P99        float64 `json:"p99Ms"`
+	Max        float64 `json:"maxMs"`
+}
+
+// Report summarises the recorded requests of a workload that ran for elapsed
+func (r *Recorder) Report(w *Workload, elapsed time.Duration) *Report {
+	report := &Report{
+		Duration:    elapsed.Seconds(),
+		Concurrency: w.Concurrency,
+		Operations:  make(map[string]Stats),
+		Statuses:    r.statuses,
+	}
+
+	var all []time.Duration
+	totalErrors := 0
+	for op, latencies := range r.latencies {
+		failed := len(latencies) - r.statuses[op]["ok"]
+		report.Operations[op] = stats(latencies, failed, elapsed)
+		all = append(all, latencies...)
+		totalErrors += failed
+	}
+	report.Total = stats(all, totalErrors, elapsed)
+	report.Requests = report.Total.Requests
+	report.Errors = report.Total.Errors
+	report.Throughput = report.Total.Throughput
+	return report
+}
+
+// stats summarises latencies, sorting them in place
+func stats(latencies []time.Duration, failed int, elapsed time.Duration) Stats {
+	s := Stats{Requests: len(latencies), Errors: failed}
+	if len(latencies) == 0 {
+		return s
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var sum time.Duration
+	for _, latency := range latencies {
+		sum += latency
+	}
+	if elapsed > 0 {
+		s.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+	s.Mean = milliseconds(sum / time.Duration(len(latencies)))
+	s.P50 = milliseconds(percentile(latencies, 50))
+	s.P95 = milliseconds(percentile(latencies, 95))
+	s.P99 = milliseconds(percentile(latencies, 99))
+	s.Max = milliseconds(latencies[len(latencies)-1])
+	return s
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(float64(len(sorted))*p/100+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// WriteJSON writes the report as indented JSON
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteText writes the report as a table of the operations followed by the outcomes of the requests
+func (report *Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%d requests in %.1fs with %d workers, %.1f req/s, %d errors\n\n",
+		report.Requests, report.Duration, report.Concurrency, report.Throughput, report.Errors)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "operation\trequests\terrors\treq/s\tmean\tp50\tp95\tp99\tmax\t")
+	row := func(name string, s Stats) {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.1f\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t\n",
+			name, s.Requests, s.Errors, s.Throughput, s.Mean, s.P50, s.P95, s.P99, s.Max)
+	}
+	for _, op := range operations {
+		if s, ok := report.Operations[op]; ok {
+			row(op, s)
+		}
+	}
+	row("total", report.Total)
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	for _, op := range operations {
+		statuses, ok := report.Statuses[op]
+		if !ok {
+			continue
+		}
+		outcomes := make([]string, 0, len(statuses))
+		for _, status := range sortedKeys(statuses) {
+			outcomes = append(outcomes, fmt.Sprintf("%s=%d", status, statuses[status]))
+		}
+		if _, err := fmt.Fprintf(w, "\n%s: %s", op, strings.Join(outcomes, " ")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// sortedKeys returns the keys of a map in ascending order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
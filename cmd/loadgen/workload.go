@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/client"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+)
+
+// Operations of a workload
+const (
+	opList   = "list"   // GET /users/{id}/favorites
+	opAdd    = "add"    // POST /users/{id}/favorites
+	opEdit   = "edit"   // PUT /users/{id}/favorites/{assetID}
+	opDelete = "delete" // DELETE /users/{id}/favorites/{assetID}
+)
+
+var operations = []string{opList, opAdd, opEdit, opDelete}
+
+// firstAssetID is the first ID of the assets added by the load generator, far above the IDs of the sample data.
+// Every worker adds assets from its own range of assetsPerWorker IDs, so workers never conflict.
+const (
+	firstAssetID    = 1_000_000_000
+	assetsPerWorker = 1_000_000
+)
+
+// Mix weighs the operations of a workload, e.g. {"list": 80, "add": 10, "edit": 5, "delete": 5}
+type Mix map[string]float64
+
+// parseMix parses weights of operations such as "list=80,add=10,edit=5,delete=5", operations left out weigh 0
+func parseMix(value string) (Mix, error) {
+	mix := Mix{}
+	total := 0.0
+	for _, part := range strings.Split(value, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid operation weight %q, expected OPERATION=WEIGHT", part)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight of %s: %q", name, weight)
+		}
+		name = strings.ToLower(name)
+		if !contains(operations, name) {
+			return nil, fmt.Errorf("unknown operation %q, expected one of %s", name, strings.Join(operations, ", "))
+		}
+		mix[name] = w
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("the mix needs at least one positive weight")
+	}
+	return mix, nil
+}
+
+// pick returns an operation picked by its weight
+func (m Mix) pick(r *rand.Rand) string {
+	total := 0.0
+	for _, op := range operations {
+		total += m[op]
+	}
+	weight := r.Float64() * total
+	for _, op := range operations {
+		if weight < m[op] {
+			return op
+		}
+		weight -= m[op]
+	}
+	return opList
+}
+
+// Workload describes the load to drive a server with
+type Workload struct {
+	Client      *client.Client
+	Mix         Mix
+	Concurrency int
+	Duration    time.Duration // how long to run once all workers started
+	RampUp      time.Duration // workers start evenly spread over RampUp
+	Zipf        float64       // skew of the users' popularity, above 1, or 0 to pick users uniformly
+	Seed        int64
+}
+
+// added is an asset added by a worker, which it can edit or delete later
+type added struct {
+	userID int
+	asset  models.Asset
+}
+
+// worker sends the requests of one connection, recording their latencies
+type worker struct {
+	workload  *Workload
+	users     []int
+	rand      *rand.Rand
+	zipf      *rand.Zipf
+	generator *mock_data.Generator
+	added     []added
+	uncertain []added // additions and deletions that failed without a response, which may or may not have been applied
+	nextID    int
+	recorder  *Recorder
+}
+
+// Run drives the server until ctx is done or the duration elapsed, and returns what was recorded.
+// The assets still added when the run ends are deleted, without recording it.
+func (w *Workload) Run(ctx context.Context) (*Report, error) {
+	if w.Concurrency < 1 {
+		return nil, errors.New("the concurrency must be at least 1")
+	}
+	if w.Zipf != 0 && w.Zipf <= 1 {
+		return nil, errors.New("the zipf skew must be above 1, or 0 for uniform users")
+	}
+	users, err := w.Client.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the users: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, errors.New("the server has no users")
+	}
+
+	// The most popular users are not the ones with the lowest IDs
+	rand.New(rand.NewSource(w.Seed)).Shuffle(len(users), func(i, j int) { users[i], users[j] = users[j], users[i] })
+
+	ctx, cancel := context.WithTimeout(ctx, w.RampUp+w.Duration)
+	defer cancel()
+
+	workers := make([]*worker, w.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		seed := w.Seed + int64(i)
+		generator := mock_data.DefaultGeneratorConfig(0, 0)
+		generator.Seed = seed
+		workers[i] = &worker{
+			workload:  w,
+			users:     users,
+			rand:      rand.New(rand.NewSource(seed)),
+			generator: mock_data.NewGenerator(generator),
+			nextID:    firstAssetID + i*assetsPerWorker,
+			recorder:  NewRecorder(),
+		}
+		if w.Zipf > 1 && len(users) > 1 {
+			workers[i].zipf = rand.NewZipf(workers[i].rand, w.Zipf, 1, uint64(len(users)-1))
+		}
+
+		delay := time.Duration(0)
+		if w.Concurrency > 1 {
+			delay = w.RampUp * time.Duration(i) / time.Duration(w.Concurrency)
+		}
+		wg.Add(1)
+		go func(wk *worker) {
+			defer wg.Done()
+			select {
+			case <-time.After(delay):
+				wk.run(ctx)
+			case <-ctx.Done():
+			}
+		}(workers[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	recorder := NewRecorder()
+	for _, wk := range workers {
+		recorder.Merge(wk.recorder)
+		wk.cleanup()
+	}
+	return recorder.Report(w, elapsed), nil
+}
+
+// run sends requests one after the other until ctx is done
+func (wk *worker) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		op := wk.workload.Mix.pick(wk.rand)
+		// Edits and deletes need an asset added before
+		if (op == opEdit || op == opDelete) && len(wk.added) == 0 {
+			op = opAdd
+		}
+
+		start := time.Now()
+		err := wk.do(ctx, op)
+		latency := time.Since(start)
+
+		// Requests cut by the end of the run are not recorded
+		if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		wk.recorder.Record(op, latency, err)
+	}
+}
+
+// do sends the request of an operation
+func (wk *worker) do(ctx context.Context, op string) error {
+	c := wk.workload.Client
+	switch op {
+	case opAdd:
+		userID := wk.user()
+		asset := wk.generator.Asset(wk.nextID)
+		wk.nextID++
+		if _, err := c.AddUserFavorite(ctx, userID, asset); err != nil {
+			if !isAPIError(err) {
+				wk.uncertain = append(wk.uncertain, added{userID: userID, asset: asset})
+			}
+			return err
+		}
+		wk.added = append(wk.added, added{userID: userID, asset: asset})
+		return nil
+	case opEdit:
+		a := wk.added[wk.rand.Intn(len(wk.added))]
+		_, err := c.EditUserFavorite(ctx, a.userID, a.asset.GetID(), edit(a.asset, wk.rand.Int()))
+		return err
+	case opDelete:
+		i := wk.rand.Intn(len(wk.added))
+		a := wk.added[i]
+		wk.added[i] = wk.added[len(wk.added)-1]
+		wk.added = wk.added[:len(wk.added)-1]
+		err := c.DeleteUserFavorite(ctx, a.userID, a.asset.GetID())
+		if err != nil && !isAPIError(err) {
+			wk.uncertain = append(wk.uncertain, a)
+		}
+		return err
+	default:
+		_, err := c.GetUserFavorites(ctx, wk.user())
+		return err
+	}
+}
+
+// user picks a user, skewed towards the most popular ones if a Zipf distribution is set
+func (wk *worker) user() int {
+	if wk.zipf != nil {
+		return wk.users[wk.zipf.Uint64()]
+	}
+	return wk.users[wk.rand.Intn(len(wk.users))]
+}
+
+// cleanup deletes the assets the worker added, or may have added, so runs do not grow the data set
+func (wk *worker) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, a := range append(wk.added, wk.uncertain...) {
+		if err := wk.workload.Client.DeleteUserFavorite(ctx, a.userID, a.asset.GetID()); err != nil && ctx.Err() != nil {
+			return
+		}
+	}
+	wk.added, wk.uncertain = nil, nil
+}
+
+// edit returns a copy of an asset with a new description
+func edit(asset models.Asset, n int) models.Asset {
+	description := fmt.Sprintf("Edited by loadgen %d", n)
+	switch a := asset.(type) {
+	case *models.Chart:
+		edited := *a
+		edited.Description = description
+		return &edited
+	case *models.Insight:
+		edited := *a
+		edited.Description = description
+		return &edited
+	case *models.Audience:
+		edited := *a
+		edited.Description = description
+		return &edited
+	}
+	return asset
+}
+
+// statusOf returns how a request ended: "ok", the HTTP status code of an API error, or "network" for requests without response
+func statusOf(err error) string {
+	var apiErr *client.Error
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.As(err, &urlErr), errors.Is(err, context.DeadlineExceeded):
+		return "network"
+	}
+	return "error"
+}
+
+// isAPIError reports whether err is a response of the API, rather than a request that may not have reached it
+func isAPIError(err error) bool {
+	var apiErr *client.Error
+	return errors.As(err, &apiErr)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Command seed generates reproducible sample users and writes them as a JSON fixture or loads them into a database.
+//
+// Usage:
+//
+//	seed [flags]                     Write the generated users to the standard output
+//	seed -out users.json [flags]     Write the generated users to a fixture file
+//	seed -load [flags]               Replace the users of the database configured in GWI_CONFIG
+//	seed -in users.json -load        Load the users of a fixture file instead of generating them
+//
+// The same flags always generate the same users. The in-memory database reads a fixture at startup
+// from the "fixture" setting of the configuration, since it only lives in the server process.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/ceciivanov/platform-go-challenge/internal/config"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+)
+
+// Exit codes of the command
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	defaults := mock_data.DefaultGeneratorConfig(100, 20)
+	generator := defaults
+	flags.Int64Var(&generator.Seed, "seed", defaults.Seed, "seed of the generated users")
+	flags.IntVar(&generator.Users, "users", defaults.Users, "number of users")
+	flags.IntVar(&generator.AssetsPerUser, "assets", defaults.AssetsPerUser, "number of favourites of every user")
+	mix := flags.String("mix", "chart=1,insight=1,audience=1", "weights of the asset types")
+	flags.Float64Var(&generator.SharedRatio, "shared", defaults.SharedRatio, "share of the favourites picked from the shared assets")
+	flags.IntVar(&generator.SharedAssets, "shared-assets", defaults.SharedAssets, "number of shared assets, 0 uses -assets")
+	flags.IntVar(&generator.MinPoints, "min-points", defaults.MinPoints, "minimum data points of the charts")
+	flags.IntVar(&generator.MaxPoints, "max-points", defaults.MaxPoints, "maximum data points of the charts")
+	in := flags.String("in", "", "fixture file to load instead of generating the users")
+	out := flags.String("out", "", `fixture file to write, "-" for the standard output (the default without -load)`)
+	load := flags.Bool("load", false, "replace the users of the configured database")
+	configPath := flags.String("config", os.Getenv("GWI_CONFIG"), "configuration file of the database (env GWI_CONFIG)")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "seed: unexpected arguments %q\n", flags.Args())
+		return exitUsage
+	}
+	typeMix, err := parseMix(*mix)
+	if err != nil {
+		fmt.Fprintf(stderr, "seed: %v\n", err)
+		return exitUsage
+	}
+	generator.TypeMix = typeMix
+	if *out == "" && !*load {
+		*out = "-"
+	}
+
+	users, err := readUsers(*in, generator)
+	if err == nil && *out != "" {
+		err = writeUsers(*out, users, stdout)
+	}
+	if err == nil && *load {
+		err = loadUsers(context.Background(), *configPath, users)
+		if err == nil {
+			fmt.Fprintf(stderr, "seed: loaded %d users\n", len(users))
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "seed: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// parseMix parses weights of asset types such as "chart=2,insight=1,audience=1", types left out weigh 0
+func parseMix(value string) (mock_data.TypeMix, error) {
+	var mix mock_data.TypeMix
+	for _, part := range strings.Split(value, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mix, fmt.Errorf("invalid type weight %q, expected TYPE=WEIGHT", part)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return mix, fmt.Errorf("invalid weight of %s: %q", name, weight)
+		}
+		switch strings.ToLower(name) {
+		case "chart":
+			mix.Chart = w
+		case "insight":
+			mix.Insight = w
+		case "audience":
+			mix.Audience = w
+		default:
+			return mix, fmt.Errorf("unknown asset type %q", name)
+		}
+	}
+	return mix, nil
+}
+
+// readUsers reads the users of a fixture file, or generates them if there is no file
+func readUsers(path string, generator mock_data.GeneratorConfig) (map[int]models.User, error) {
+	if path == "" {
+		return mock_data.NewGenerator(generator).Generate()
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return mock_data.ReadFixture(file)
+}
+
+// writeUsers writes the users to a fixture file, or stdout for "-"
+func writeUsers(path string, users map[int]models.User, stdout io.Writer) error {
+	if path == "-" {
+		return mock_data.WriteFixture(stdout, users)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := mock_data.WriteFixture(file, users); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// loadUsers replaces the users of the configured database. The favourites cached on the shared Redis are invalidated
+// for the replaced and new users, so the servers do not serve the replaced ones until they expire.
+func loadUsers(ctx context.Context, configPath string, users map[int]models.User) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	var repo repository.UserRepository
+	switch cfg.Database.Driver {
+	case "memory":
+		return errors.New(`the memory database lives in the server process: write a fixture with -out and set it as "fixture" in the configuration`)
+	case "postgres":
+		pool, err := repository.OpenPostgres(ctx, cfg.Database.URL, repository.PostgresOptions{MaxConns: cfg.Database.MaxConns})
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+		if err := repository.MigratePostgres(ctx, pool); err != nil {
+			return err
+		}
+		repo = repository.NewPostgresUserRepository(pool)
+	default:
+		return fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+	}
+
+	if cfg.Cache.RedisAddr != "" {
+		client := cache.NewRESPClient(cfg.Cache.RedisAddr)
+		client.Password = cfg.Cache.RedisPassword
+		client.DB = cfg.Cache.RedisDB
+		defer client.Close()
+		repo = repository.NewCachedUserRepository(repo, nil, client)
+	}
+	return repo.(repository.UserReplacer).ReplaceUsers(ctx, users)
+}
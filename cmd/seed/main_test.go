@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/stretchr/testify/assert"
+)
+
+// runSeed runs the command and returns its exit code, output and errors
+func runSeed(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSeedWritesFixtures(t *testing.T) {
+	// Test the same flags write the same fixture to the standard output and to a file
+	code, stdout, _ := runSeed("-users", "3", "-assets", "4", "-mix", "chart=1,audience=2", "-seed", "7")
+	assert.Equal(t, exitOK, code)
+
+	path := filepath.Join(t.TempDir(), "users.json")
+	code, _, _ = runSeed("-users", "3", "-assets", "4", "-mix", "chart=1,audience=2", "-seed", "7", "-out", path)
+	assert.Equal(t, exitOK, code)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, stdout, string(data))
+
+	users, err := mock_data.ReadFixture(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+	for _, user := range users {
+		assert.Len(t, user.Favourites, 4)
+		for _, asset := range user.Favourites {
+			assert.NotEqual(t, models.InsightType, asset.GetType())
+		}
+	}
+
+	// Test a fixture is read back unchanged
+	code, copied, _ := runSeed("-in", path)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, stdout, copied)
+}
+
+func TestSeedErrors(t *testing.T) {
+	code, _, stderr := runSeed("-mix", "chart=1,video=1")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown asset type "video"`)
+
+	code, _, _ = runSeed("-mix", "chart")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runSeed("extra")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr = runSeed("-shared", "2")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "shared ratio")
+
+	code, _, _ = runSeed("-in", filepath.Join(t.TempDir(), "missing.json"))
+	assert.Equal(t, exitError, code)
+
+	// Test the in-memory database cannot be loaded from another process
+	code, _, stderr = runSeed("-load", "-config", "")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "fixture")
+}
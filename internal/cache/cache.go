@@ -0,0 +1,18 @@
+// Package cache holds the caches kept in front of the repositories: a size-bounded in-process LRU
+// and a client of a remote cache speaking the Redis protocol.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a remote cache of encoded values shared between the instances of the application
+type Cache interface {
+	// Get returns the value saved under a key, and false if there is none or it expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set saves a value under a key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys
+	Delete(ctx context.Context, keys ...string) error
+}
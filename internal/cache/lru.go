@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process cache holding at most Capacity values, evicting the least recently used one to make room.
+// Values are returned as they were saved, so callers must not change them.
+type LRU struct {
+	Capacity int
+	Now      func() time.Time // Now returns the current time, replaced in tests
+
+	entries map[string]*list.Element
+	order   *list.List // order holds the entries from the most to the least recently used
+	mu      sync.Mutex // mu protects the entries and their order from concurrent access
+}
+
+// lruEntry is a value saved in an LRU
+type lruEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewLRU creates a new LRU holding at most capacity values
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		Capacity: capacity,
+		Now:      time.Now,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value saved under a key, and false if there is none or it expired
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.After(c.Now()) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set saves a value under a key for ttl, evicting the least recently used values beyond the capacity
+func (c *LRU) Set(key string, value interface{}, ttl time.Duration) {
+	if c.Capacity <= 0 || ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.Capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes the value saved under a key
+func (c *LRU) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of values saved, including the expired ones not evicted yet
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
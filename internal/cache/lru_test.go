@@ -0,0 +1,70 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	c := cache.NewLRU(2)
+
+	// Test a missing key
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	// Test the least recently used value is evicted
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	c.Set("c", 3, time.Minute)
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+
+	// Test replacing a value
+	c.Set("c", 4, time.Minute)
+	value, _ = c.Get("c")
+	assert.Equal(t, 4, value)
+	assert.Equal(t, 2, c.Len())
+
+	// Test deleting a value
+	c.Delete("c")
+	c.Delete("missing")
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRUExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := cache.NewLRU(10)
+	c.Now = func() time.Time { return now }
+
+	c.Set("a", 1, time.Minute)
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUDisabled(t *testing.T) {
+	// Test a zero capacity or TTL saves nothing
+	c := cache.NewLRU(0)
+	c.Set("a", 1, time.Minute)
+	assert.Equal(t, 0, c.Len())
+
+	c = cache.NewLRU(1)
+	c.Set("a", 1, 0)
+	assert.Equal(t, 0, c.Len())
+}
@@ -0,0 +1,152 @@
+// Package resp reads and writes the values of RESP, the protocol spoken by Redis and its compatible servers.
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Limits of the values read, so a broken or hostile peer cannot make the reader allocate without bound
+const (
+	MaxBulkLength  = 64 << 20
+	MaxArrayLength = 1 << 20
+	maxDepth       = 8
+)
+
+// ErrProtocol is returned for data that is not valid RESP
+var ErrProtocol = errors.New("resp: protocol error")
+
+// Error is an error reply, e.g. "ERR unknown command"
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Read reads one value: a string for simple strings, an Error for errors, an int64 for integers,
+// a []byte for bulk strings (nil for the null bulk string) and a []interface{} for arrays (nil for the null array)
+func Read(r *bufio.Reader) (interface{}, error) {
+	return read(r, 0)
+}
+
+func read(r *bufio.Reader, depth int) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, ErrProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, ErrProtocol
+		}
+		return n, nil
+	case '$':
+		n, err := readLength(line, MaxBulkLength)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return []byte(nil), nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		if data[n] != '\r' || data[n+1] != '\n' {
+			return nil, ErrProtocol
+		}
+		return data[:n], nil
+	case '*':
+		if depth >= maxDepth {
+			return nil, ErrProtocol
+		}
+		n, err := readLength(line, MaxArrayLength)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return []interface{}(nil), nil
+		}
+		values := make([]interface{}, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			value, err := read(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+	return nil, ErrProtocol
+}
+
+// readLine reads a line ending in CRLF, without the CRLF
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, ErrProtocol
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, ErrProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// readLength parses the length of a bulk string or an array, -1 being the null value
+func readLength(line []byte, max int) (int, error) {
+	n, err := strconv.Atoi(string(line[1:]))
+	if err != nil || n < -1 || n > max {
+		return 0, ErrProtocol
+	}
+	return n, nil
+}
+
+// WriteCommand writes a command as an array of bulk strings, the form servers expect from clients
+func WriteCommand(w *bufio.Writer, args ...[]byte) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		WriteBulk(w, arg)
+	}
+	return w.Flush()
+}
+
+// WriteSimple writes a simple string, e.g. "OK"
+func WriteSimple(w *bufio.Writer, s string) {
+	w.WriteString("+" + s + "\r\n")
+}
+
+// WriteError writes an error reply
+func WriteError(w *bufio.Writer, message string) {
+	w.WriteString("-" + message + "\r\n")
+}
+
+// WriteInt writes an integer
+func WriteInt(w *bufio.Writer, n int64) {
+	w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+// WriteBulk writes a bulk string, or the null bulk string for nil
+func WriteBulk(w *bufio.Writer, data []byte) {
+	if data == nil {
+		w.WriteString("$-1\r\n")
+		return
+	}
+	w.WriteString("$" + strconv.Itoa(len(data)) + "\r\n")
+	w.Write(data)
+	w.WriteString("\r\n")
+}
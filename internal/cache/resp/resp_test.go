@@ -0,0 +1,83 @@
+package resp_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected interface{}
+	}{
+		{"SimpleString", "+OK\r\n", "OK"},
+		{"Error", "-ERR unknown command\r\n", resp.Error("ERR unknown command")},
+		{"Integer", ":-42\r\n", int64(-42)},
+		{"BulkString", "$5\r\nhe\r\no\r\n", []byte("he\r\no")},
+		{"EmptyBulkString", "$0\r\n\r\n", []byte{}},
+		{"NullBulkString", "$-1\r\n", []byte(nil)},
+		{"Array", "*2\r\n$3\r\nGET\r\n:1\r\n", []interface{}{[]byte("GET"), int64(1)}},
+		{"NullArray", "*-1\r\n", []interface{}(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := resp.Read(bufio.NewReader(strings.NewReader(tt.data)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"UnknownType", "?OK\r\n"},
+		{"MissingCR", "+OK\n"},
+		{"InvalidInteger", ":abc\r\n"},
+		{"InvalidLength", "$-2\r\n"},
+		{"BulkTooLong", "$999999999999\r\n"},
+		{"ArrayTooLong", "*999999999\r\n"},
+		{"BulkWithoutCRLF", "$2\r\nabcd"},
+		{"TooDeep", strings.Repeat("*1\r\n", 20) + ":1\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resp.Read(bufio.NewReader(strings.NewReader(tt.data)))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	assert.NoError(t, resp.WriteCommand(w, []byte("SET"), []byte("key"), []byte("")))
+	assert.Equal(t, "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$0\r\n\r\n", buf.String())
+
+	buf.Reset()
+	resp.WriteSimple(w, "OK")
+	resp.WriteError(w, "ERR wrong")
+	resp.WriteInt(w, 7)
+	resp.WriteBulk(w, nil)
+	w.Flush()
+	assert.Equal(t, "+OK\r\n-ERR wrong\r\n:7\r\n$-1\r\n", buf.String())
+
+	// Every written value reads back
+	r := bufio.NewReader(&buf)
+	for _, expected := range []interface{}{"OK", resp.Error("ERR wrong"), int64(7), []byte(nil)} {
+		value, err := resp.Read(r)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+}
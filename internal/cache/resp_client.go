@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resp"
+)
+
+// DefaultRESPTimeout bounds the commands sent by a RESPClient whose context has no deadline
+const DefaultRESPTimeout = time.Second
+
+// RESPClient is a Cache stored on a server speaking the Redis protocol (Redis, Valkey, KeyDB, ...).
+// Connections are opened on demand and up to MaxIdle of them are kept for the next commands.
+type RESPClient struct {
+	Addr     string
+	Password string // sent with AUTH on every new connection unless empty
+	DB       int    // selected with SELECT on every new connection unless zero
+	Timeout  time.Duration
+	MaxIdle  int
+
+	idle   []*respConn
+	closed bool
+	mu     sync.Mutex // mu protects the idle connections from concurrent access
+}
+
+// respConn is a connection to the server
+type respConn struct {
+	net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// NewRESPClient creates a new RESPClient of the server listening on addr
+func NewRESPClient(addr string) *RESPClient {
+	return &RESPClient{
+		Addr:    addr,
+		Timeout: DefaultRESPTimeout,
+		MaxIdle: 8,
+	}
+}
+
+// Get returns the value saved under a key with GET
+func (c *RESPClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", []byte(key))
+	if err != nil {
+		return nil, false, err
+	}
+	if value, ok := reply.([]byte); ok {
+		return value, value != nil, nil
+	}
+	return nil, false, fmt.Errorf("resp: unexpected reply %v to GET", reply)
+}
+
+// Set saves a value under a key with SET, expiring it after ttl with millisecond precision
+func (c *RESPClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < time.Millisecond {
+		return errors.New("resp: ttl shorter than a millisecond")
+	}
+	_, err := c.do(ctx, "SET", []byte(key), value, []byte("PX"), []byte(strconv.FormatInt(ttl.Milliseconds(), 10)))
+	return err
+}
+
+// Delete removes keys with DEL
+func (c *RESPClient) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([][]byte, len(keys))
+	for i, key := range keys {
+		args[i] = []byte(key)
+	}
+	_, err := c.do(ctx, "DEL", args...)
+	return err
+}
+
+// Ping checks the server can be reached
+func (c *RESPClient) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections, the client cannot be used afterwards
+func (c *RESPClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, conn := range c.idle {
+		conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+// do sends a command and returns its reply, an error reply of the server being returned as a resp.Error.
+// Connections that failed are closed instead of being reused, since a reply may still be pending on them.
+func (c *RESPClient) do(ctx context.Context, command string, args ...[]byte) (interface{}, error) {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := roundTrip(ctx, conn, c.Timeout, append([][]byte{[]byte(command)}, args...))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.release(conn)
+
+	if replyErr, ok := reply.(resp.Error); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// conn returns an idle connection, or a new one authenticated and with the database selected
+func (c *RESPClient) conn(ctx context.Context) (*respConn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("resp: client closed")
+	}
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, nil
+	}
+	c.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: c.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &respConn{Conn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+
+	var setup [][][]byte
+	if c.Password != "" {
+		setup = append(setup, [][]byte{[]byte("AUTH"), []byte(c.Password)})
+	}
+	if c.DB != 0 {
+		setup = append(setup, [][]byte{[]byte("SELECT"), []byte(strconv.Itoa(c.DB))})
+	}
+	for _, command := range setup {
+		reply, err := roundTrip(ctx, conn, c.Timeout, command)
+		if err == nil {
+			if replyErr, ok := reply.(resp.Error); ok {
+				err = replyErr
+			}
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// release keeps a connection for the next commands, or closes it if enough are idle
+func (c *RESPClient) release(conn *respConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(c.idle) >= c.MaxIdle {
+		conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+// roundTrip writes a command and reads its reply before the deadline of the context, or timeout from now
+func roundTrip(ctx context.Context, conn *respConn, timeout time.Duration, command [][]byte) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok && timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	conn.SetDeadline(deadline)
+
+	if err := resp.WriteCommand(conn.writer, command...); err != nil {
+		return nil, err
+	}
+	return resp.Read(conn.reader)
+}
@@ -0,0 +1,127 @@
+package cache_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resp"
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRESPClient(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	client := cache.NewRESPClient(server.Addr)
+	defer client.Close()
+	ctx := context.Background()
+
+	assert.NoError(t, client.Ping(ctx))
+
+	// Test a missing key
+	_, ok, err := client.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Test saving and reading values, including binary and empty ones
+	assert.NoError(t, client.Set(ctx, "key", []byte("a\r\nb\x00"), time.Minute))
+	assert.NoError(t, client.Set(ctx, "empty", []byte{}, time.Minute))
+	value, ok, err := client.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a\r\nb\x00"), value)
+	value, ok, err = client.Get(ctx, "empty")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, value)
+
+	// Test deleting keys
+	assert.NoError(t, client.Delete(ctx, "key", "empty", "missing"))
+	_, ok = server.Get("key")
+	assert.False(t, ok)
+	assert.NoError(t, client.Delete(ctx))
+
+	// Test the values expire after their TTL
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), 1500*time.Millisecond))
+	server.FastForward(time.Second)
+	_, ok, _ = client.Get(ctx, "key")
+	assert.True(t, ok)
+	server.FastForward(time.Second)
+	_, ok, _ = client.Get(ctx, "key")
+	assert.False(t, ok)
+	assert.Error(t, client.Set(ctx, "key", []byte("value"), time.Microsecond))
+}
+
+func TestRESPClientAuth(t *testing.T) {
+	server := resptest.NewServer()
+	server.SetPassword("secret")
+	defer server.Close()
+	ctx := context.Background()
+
+	// Test commands fail without the password
+	client := cache.NewRESPClient(server.Addr)
+	defer client.Close()
+	err := client.Ping(ctx)
+	assert.Equal(t, resp.Error("NOAUTH Authentication required."), err)
+
+	// Test a wrong password fails the connection
+	client.Password = "wrong"
+	assert.Error(t, client.Ping(ctx))
+
+	// Test the password and database are sent on every new connection
+	client = cache.NewRESPClient(server.Addr)
+	client.Password = "secret"
+	client.DB = 2
+	defer client.Close()
+	assert.NoError(t, client.Ping(ctx))
+	assert.Equal(t, 1, server.Calls("SELECT"))
+}
+
+func TestRESPClientReconnect(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	client := cache.NewRESPClient(server.Addr)
+	defer client.Close()
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), time.Minute))
+
+	// Test a broken idle connection fails one command, the next one reconnects
+	server.CloseConnections()
+	if _, _, err := client.Get(ctx, "key"); err == nil {
+		t.Log("the closed connection was detected before the command was sent")
+	}
+	value, ok, err := client.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	// Test a closed client
+	client.Close()
+	assert.Error(t, client.Ping(ctx))
+}
+
+func TestRESPClientTimeout(t *testing.T) {
+	// Test a server that never replies fails the command after the timeout
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	client := cache.NewRESPClient(listener.Addr().String())
+	client.Timeout = 50 * time.Millisecond
+	defer client.Close()
+
+	start := time.Now()
+	assert.Error(t, client.Ping(context.Background()))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,286 @@
+// Package resptest provides an in-process server speaking the Redis protocol, for testing the clients of a remote cache
+// without running Redis.
+package resptest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resp"
+)
+
+// Server is a fake Redis server listening on a local port. It supports the commands used by the caches:
+// PING, AUTH, SELECT, GET, SET (with EX and PX), DEL, EXISTS, DBSIZE and FLUSHALL.
+type Server struct {
+	Addr string
+
+	password string // password is required with AUTH before any other command unless empty
+	listener net.Listener
+	values   map[string]value
+	calls    map[string]int
+	offset   time.Duration // offset is added to the current time by FastForward
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+	mu       sync.Mutex // mu protects the password, values, calls, offset, conns and closed from concurrent access
+}
+
+// value is a string saved on the server, with an optional expiry
+type value struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewServer starts a new Server on a random local port
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("resptest: failed to listen: " + err.Error())
+	}
+
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		listener: listener,
+		values:   make(map[string]value),
+		calls:    make(map[string]int),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close stops the server and closes the open connections
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.listener.Close()
+	s.CloseConnections()
+	s.wg.Wait()
+}
+
+// CloseConnections closes the open connections, like a server restart would, keeping the values
+func (s *Server) CloseConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// SetPassword requires the new connections to send AUTH with password before any other command
+func (s *Server) SetPassword(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = password
+}
+
+// Calls returns how many times a command was received, e.g. Calls("GET")
+func (s *Server) Calls(command string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[strings.ToUpper(command)]
+}
+
+// Get returns the value saved under a key, and false if there is none or it expired
+func (s *Server) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.lookup(key)
+	return v.data, ok
+}
+
+// FastForward moves the clock of the server forward, expiring the values whose TTL elapsed
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// handle replies to the commands of a connection until it is closed
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	s.mu.Lock()
+	password := s.password
+	s.mu.Unlock()
+
+	authenticated := password == ""
+	for {
+		request, err := resp.Read(reader)
+		if err != nil {
+			return
+		}
+		args, ok := commandArgs(request)
+		if !ok {
+			resp.WriteError(writer, "ERR protocol error")
+			writer.Flush()
+			return
+		}
+
+		command := strings.ToUpper(string(args[0]))
+		switch {
+		case command == "AUTH":
+			if len(args) == 2 && string(args[1]) == password {
+				authenticated = true
+				resp.WriteSimple(writer, "OK")
+			} else {
+				resp.WriteError(writer, "WRONGPASS invalid username-password pair")
+			}
+		case !authenticated:
+			resp.WriteError(writer, "NOAUTH Authentication required.")
+		default:
+			s.execute(writer, command, args[1:])
+		}
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// commandArgs returns the arguments of a command sent as an array of bulk strings
+func commandArgs(request interface{}) ([][]byte, bool) {
+	values, ok := request.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, false
+	}
+	args := make([][]byte, len(values))
+	for i, v := range values {
+		if args[i], ok = v.([]byte); !ok || args[i] == nil {
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+// execute runs a command and writes its reply
+func (s *Server) execute(w *bufio.Writer, command string, args [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[command]++
+
+	switch command {
+	case "PING":
+		resp.WriteSimple(w, "PONG")
+	case "SELECT":
+		if len(args) != 1 {
+			resp.WriteError(w, "ERR wrong number of arguments for 'select' command")
+			return
+		}
+		resp.WriteSimple(w, "OK")
+	case "GET":
+		if len(args) != 1 {
+			resp.WriteError(w, "ERR wrong number of arguments for 'get' command")
+			return
+		}
+		v, _ := s.lookup(string(args[0]))
+		resp.WriteBulk(w, v.data)
+	case "SET":
+		s.set(w, args)
+	case "DEL", "EXISTS":
+		if len(args) == 0 {
+			resp.WriteError(w, "ERR wrong number of arguments for '"+strings.ToLower(command)+"' command")
+			return
+		}
+		var n int64
+		for _, key := range args {
+			if _, ok := s.lookup(string(key)); ok {
+				n++
+				if command == "DEL" {
+					delete(s.values, string(key))
+				}
+			}
+		}
+		resp.WriteInt(w, n)
+	case "DBSIZE":
+		var n int64
+		for key := range s.values {
+			if _, ok := s.lookup(key); ok {
+				n++
+			}
+		}
+		resp.WriteInt(w, n)
+	case "FLUSHALL":
+		s.values = make(map[string]value)
+		resp.WriteSimple(w, "OK")
+	default:
+		resp.WriteError(w, "ERR unknown command '"+command+"'")
+	}
+}
+
+// set runs SET key value [EX seconds | PX milliseconds]
+func (s *Server) set(w *bufio.Writer, args [][]byte) {
+	if len(args) != 2 && len(args) != 4 {
+		resp.WriteError(w, "ERR syntax error")
+		return
+	}
+
+	v := value{data: args[1]}
+	if len(args) == 4 {
+		n, err := strconv.ParseInt(string(args[3]), 10, 64)
+		if err != nil || n <= 0 {
+			resp.WriteError(w, "ERR invalid expire time in 'set' command")
+			return
+		}
+		switch strings.ToUpper(string(args[2])) {
+		case "EX":
+			v.expiresAt = s.now().Add(time.Duration(n) * time.Second)
+		case "PX":
+			v.expiresAt = s.now().Add(time.Duration(n) * time.Millisecond)
+		default:
+			resp.WriteError(w, "ERR syntax error")
+			return
+		}
+	}
+	s.values[string(args[0])] = v
+	resp.WriteSimple(w, "OK")
+}
+
+// lookup returns the value saved under a key, removing it if it expired
+func (s *Server) lookup(key string) (value, bool) {
+	v, ok := s.values[key]
+	if ok && !v.expiresAt.IsZero() && !v.expiresAt.After(s.now()) {
+		delete(s.values, key)
+		return value{}, false
+	}
+	return v, ok
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
@@ -0,0 +1,23 @@
+package codec
+
+import (
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// CBOR encodes values as CBOR, which names the fields after their JSON tags when they have no cbor tag
+type CBOR struct{}
+
+func (CBOR) MediaType() string {
+	return "application/cbor"
+}
+
+func (CBOR) Marshal(v interface{}) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+func (CBOR) DecodeAsset(data []byte) (models.Asset, error) {
+	return utils.DecodeAssetWith(data, cbor.Unmarshal)
+}
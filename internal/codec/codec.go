@@ -0,0 +1,126 @@
+// Package codec encodes the responses and decodes the assets of the REST API in the media types
+// negotiated with the Accept and Content-Type headers.
+package codec
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// ErrUnsupportedValue is returned by codecs that cannot encode a type of value, like protobuf for
+// the values without a protobuf message
+var ErrUnsupportedValue = errors.New("unsupported value")
+
+// Codec encodes values in a media type, and decodes assets from it into the correct asset type
+type Codec interface {
+	MediaType() string
+	Marshal(v interface{}) ([]byte, error)
+	DecodeAsset(data []byte) (models.Asset, error)
+}
+
+// Registry holds the codecs by media type. The first registered codec is the default one,
+// used when a request does not say which media type it accepts or sends.
+type Registry struct {
+	codecs     []Codec
+	mediaTypes map[string]Codec
+}
+
+// NewRegistry creates a new Registry with codecs, the first one being the default
+func NewRegistry(codecs ...Codec) *Registry {
+	registry := &Registry{mediaTypes: make(map[string]Codec)}
+	for _, codec := range codecs {
+		registry.Register(codec)
+	}
+	return registry
+}
+
+// NewDefaultRegistry creates a new Registry with the JSON (default), MessagePack, CBOR and protobuf codecs
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry(JSON{})
+	registry.Register(MessagePack{}, "application/x-msgpack", "application/vnd.msgpack")
+	registry.Register(CBOR{})
+	registry.Register(Protobuf{}, "application/protobuf", "application/vnd.google.protobuf")
+	return registry
+}
+
+// Register adds a codec for its media type and any aliases of it
+func (r *Registry) Register(codec Codec, aliases ...string) {
+	r.codecs = append(r.codecs, codec)
+	for _, mediaType := range append([]string{codec.MediaType()}, aliases...) {
+		r.mediaTypes[strings.ToLower(mediaType)] = codec
+	}
+}
+
+// Negotiate returns the codec of the media type preferred by an Accept header.
+// An empty header, or one accepting any media type, gets the default codec.
+func (r *Registry) Negotiate(accept string) (Codec, error) {
+	if strings.TrimSpace(accept) == "" {
+		return r.codecs[0], nil
+	}
+
+	var best Codec
+	bestQuality := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		// Media types of equal quality are preferred in the order of the header
+		if quality <= bestQuality {
+			continue
+		}
+		if codec := r.match(mediaType); codec != nil {
+			best, bestQuality = codec, quality
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("not acceptable")
+	}
+	return best, nil
+}
+
+// ForContentType returns the codec of the media type in a Content-Type header.
+// An empty header gets the default codec.
+func (r *Registry) ForContentType(contentType string) (Codec, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return r.codecs[0], nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errors.New("unsupported media type")
+	}
+	codec, ok := r.mediaTypes[mediaType]
+	if !ok {
+		return nil, errors.New("unsupported media type")
+	}
+	return codec, nil
+}
+
+// match returns the codec of a media type of an Accept header, which may be a */* or type/* range
+func (r *Registry) match(mediaType string) Codec {
+	if mediaType == "*/*" {
+		return r.codecs[0]
+	}
+	if prefix, ok := strings.CutSuffix(mediaType, "/*"); ok {
+		for _, codec := range r.codecs {
+			if strings.HasPrefix(codec.MediaType(), prefix+"/") {
+				return codec
+			}
+		}
+		return nil
+	}
+	return r.mediaTypes[mediaType]
+}
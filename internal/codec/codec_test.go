@@ -0,0 +1,166 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/codec"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var assets = []models.Asset{
+	&models.Chart{
+		ID:          1,
+		Type:        models.ChartType,
+		Description: "Sample Chart",
+		Title:       "Sample Chart Title",
+		XAxesTitle:  "X-Axis",
+		YAxesTitle:  "Y-Axis",
+		DataPoints:  []models.Point{{X: 1.5, Y: 2}, {X: 3, Y: -4.25}},
+	},
+	&models.Insight{
+		ID:          2,
+		Type:        models.InsightType,
+		Description: "Sample Insight",
+		Text:        "Sample Insight Text",
+	},
+	&models.Audience{
+		ID:                3,
+		Type:              models.AudienceType,
+		Description:       "Sample Audience",
+		Age:               25,
+		AgeGroup:          "18-25",
+		Gender:            "Male",
+		BirthCountry:      "GR",
+		HoursSpentOnMedia: 18,
+		NumberOfPurchases: 4,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	registry := codec.NewDefaultRegistry()
+
+	for _, mediaType := range []string{"application/json", "application/msgpack", "application/cbor", "application/x-protobuf"} {
+		t.Run(mediaType, func(t *testing.T) {
+			c, err := registry.ForContentType(mediaType)
+			assert.NoError(t, err)
+			assert.Equal(t, mediaType, c.MediaType())
+
+			for _, asset := range assets {
+				data, err := c.Marshal(asset)
+				assert.NoError(t, err)
+
+				decoded, err := c.DecodeAsset(data)
+				assert.NoError(t, err)
+				assert.Equal(t, asset, decoded)
+			}
+
+			_, err = c.Marshal(map[int]models.Asset{1: assets[0], 2: assets[1]})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeInvalidAsset(t *testing.T) {
+	registry := codec.NewDefaultRegistry()
+
+	for _, mediaType := range []string{"application/msgpack", "application/cbor"} {
+		t.Run(mediaType, func(t *testing.T) {
+			c, _ := registry.ForContentType(mediaType)
+
+			data, err := c.Marshal(map[string]interface{}{"id": 1, "type": "Unknown"})
+			assert.NoError(t, err)
+			_, err = c.DecodeAsset(data)
+			assert.EqualError(t, err, "invalid asset type")
+
+			_, err = c.DecodeAsset([]byte{0xc1})
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("application/x-protobuf", func(t *testing.T) {
+		c, _ := registry.ForContentType("application/x-protobuf")
+
+		_, err := c.DecodeAsset(nil)
+		assert.EqualError(t, err, "invalid asset type")
+	})
+}
+
+func TestProtobufUnsupportedValue(t *testing.T) {
+	_, err := codec.Protobuf{}.Marshal(map[string]string{"key": "value"})
+	assert.Equal(t, codec.ErrUnsupportedValue, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	registry := codec.NewDefaultRegistry()
+
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/*", "application/json"},
+		{"application/msgpack", "application/msgpack"},
+		{"application/x-msgpack", "application/msgpack"},
+		{"application/CBOR", "application/cbor"},
+		{"application/protobuf", "application/x-protobuf"},
+		{"text/html, application/cbor;q=0.9, */*;q=0.8", "application/cbor"},
+		{"application/json;q=0.5, application/msgpack", "application/msgpack"},
+		{"application/cbor, application/msgpack", "application/cbor"},
+		{"application/msgpack;q=0, */*;q=0.1", "application/json"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.accept, func(t *testing.T) {
+			c, err := registry.Negotiate(tc.accept)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, c.MediaType())
+		})
+	}
+
+	for _, accept := range []string{"text/html", "application/msgpack;q=0", "image/*"} {
+		t.Run(accept, func(t *testing.T) {
+			_, err := registry.Negotiate(accept)
+			assert.EqualError(t, err, "not acceptable")
+		})
+	}
+}
+
+func TestForContentType(t *testing.T) {
+	registry := codec.NewDefaultRegistry()
+
+	c, err := registry.ForContentType("")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", c.MediaType())
+
+	c, err = registry.ForContentType("application/json; charset=utf-8")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", c.MediaType())
+
+	_, err = registry.ForContentType("text/plain")
+	assert.EqualError(t, err, "unsupported media type")
+
+	_, err = registry.ForContentType("not a media type")
+	assert.EqualError(t, err, "unsupported media type")
+}
+
+// BenchmarkMarshalChart compares the codecs encoding a Chart with 10000 data points
+func BenchmarkMarshalChart(b *testing.B) {
+	chart := &models.Chart{ID: 1, Type: models.ChartType, Title: "Large Chart", DataPoints: make([]models.Point, 10000)}
+	for i := range chart.DataPoints {
+		chart.DataPoints[i] = models.Point{X: float32(i) / 3, Y: float32(i) / 7}
+	}
+
+	registry := codec.NewDefaultRegistry()
+	for _, mediaType := range []string{"application/json", "application/msgpack", "application/cbor", "application/x-protobuf"} {
+		c, _ := registry.ForContentType(mediaType)
+		b.Run(mediaType, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(chart); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
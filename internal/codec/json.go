@@ -0,0 +1,29 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// JSON encodes values as JSON, the default encoding of the API
+type JSON struct{}
+
+func (JSON) MediaType() string {
+	return "application/json"
+}
+
+// Marshal encodes a value as JSON followed by a newline, like a json.Encoder
+func (JSON) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (JSON) DecodeAsset(data []byte) (models.Asset, error) {
+	return utils.DecodeAsset(data)
+}
@@ -0,0 +1,38 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// MessagePack encodes values as MessagePack, with the same field names as JSON
+type MessagePack struct{}
+
+func (MessagePack) MediaType() string {
+	return "application/msgpack"
+}
+
+func (MessagePack) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MessagePack) DecodeAsset(data []byte) (models.Asset, error) {
+	return utils.DecodeAssetWith(data, unmarshalMessagePack)
+}
+
+// unmarshalMessagePack decodes MessagePack into v, naming the fields after their JSON tags
+func unmarshalMessagePack(data []byte, v interface{}) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
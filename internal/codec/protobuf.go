@@ -0,0 +1,60 @@
+package codec
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/pb"
+)
+
+// Protobuf encodes assets as the Asset message of api/proto/favorites.proto, and lists or maps of assets
+// as an AssetList ordered by asset ID. Other values cannot be encoded.
+type Protobuf struct{}
+
+func (Protobuf) MediaType() string {
+	return "application/x-protobuf"
+}
+
+func (Protobuf) Marshal(v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case models.Asset:
+		asset, err := pb.FromModel(value)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(asset)
+	case map[int]models.Asset:
+		assets := make([]models.Asset, 0, len(value))
+		for _, asset := range value {
+			assets = append(assets, asset)
+		}
+		sort.Slice(assets, func(i, j int) bool { return assets[i].GetID() < assets[j].GetID() })
+		return marshalAssetList(assets)
+	case []models.Asset:
+		return marshalAssetList(value)
+	default:
+		return nil, ErrUnsupportedValue
+	}
+}
+
+func (Protobuf) DecodeAsset(data []byte) (models.Asset, error) {
+	var asset pb.Asset
+	if err := proto.Unmarshal(data, &asset); err != nil {
+		return nil, err
+	}
+	return asset.ToModel()
+}
+
+// marshalAssetList encodes assets as an AssetList
+func marshalAssetList(assets []models.Asset) ([]byte, error) {
+	list := &pb.AssetList{Assets: make([]*pb.Asset, len(assets))}
+	for i, asset := range assets {
+		var err error
+		if list.Assets[i], err = pb.FromModel(asset); err != nil {
+			return nil, err
+		}
+	}
+	return proto.Marshal(list)
+}
@@ -0,0 +1,82 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/config"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := config.Load("")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+
+	// Test the audit log is appended to a JSON Lines file by default
+	assert.Equal(t, "file", cfg.Audit.Sink)
+	assert.Equal(t, "audit.jsonl", cfg.Audit.File)
+	assert.Equal(t, "audit-keys.json", cfg.Audit.KeyFile)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"addr": ":9090", "fixture": "users.json", "rateLimit": {"enabled": false}, "compression": {"minSize": 512}, "trashRetention": "48h", "maxBodySize": 4096, "idempotencyTTL": "1h", "assetLimits": {"maxDataPoints": 100}, "tracing": {"exporter": "file", "file": "spans.jsonl"}, "cache": {"size": 1000, "redisAddr": "localhost:6379"}, "database": {"driver": "postgres", "url": "postgres://localhost/gwi"}, "admin": {"keys": {"ops": "secret"}}, "audit": {"file": "/var/log/gwi/audit.jsonl"}}`), 0o644)
+	assert.NoError(t, err)
+
+	cfg, err := config.Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.Addr)
+	assert.Equal(t, "users.json", cfg.Fixture)
+	assert.False(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, config.Duration(48*time.Hour), cfg.TrashRetention)
+	assert.Equal(t, 512, cfg.Compression.MinSize)
+	assert.True(t, cfg.Compression.Enabled)
+	assert.Equal(t, int64(4096), cfg.MaxBodySize)
+	assert.Equal(t, config.Duration(time.Hour), cfg.IdempotencyTTL)
+	assert.Equal(t, 100, cfg.AssetLimits.MaxDataPoints)
+	assert.Equal(t, config.Default().AssetLimits.MaxTextLength, cfg.AssetLimits.MaxTextLength)
+	assert.Equal(t, "file", cfg.Tracing.Exporter)
+	assert.Equal(t, "spans.jsonl", cfg.Tracing.File)
+	assert.Equal(t, config.Default().Tracing.ServiceName, cfg.Tracing.ServiceName)
+	assert.Equal(t, 1000, cfg.Cache.Size)
+	assert.Equal(t, "localhost:6379", cfg.Cache.RedisAddr)
+	assert.Equal(t, config.Duration(time.Minute), cfg.Cache.TTL)
+	assert.Equal(t, "postgres", cfg.Database.Driver)
+	assert.Equal(t, "postgres://localhost/gwi", cfg.Database.URL)
+	assert.Equal(t, config.Default().Database.MaxConns, cfg.Database.MaxConns)
+	assert.Equal(t, map[string]string{"ops": "secret"}, cfg.Admin.Keys)
+	assert.Equal(t, config.Default().Admin.SnapshotDir, cfg.Admin.SnapshotDir)
+	assert.Equal(t, "erasures.jsonl", cfg.Admin.ErasureFile)
+	assert.Equal(t, "file", cfg.Audit.Sink)
+	assert.Equal(t, "/var/log/gwi/audit.jsonl", cfg.Audit.File)
+	assert.Equal(t, config.Default().Audit.MaxRecords, cfg.Audit.MaxRecords)
+
+	// Test settings missing from the file keep their defaults
+	assert.Equal(t, config.Default().NumberOfUsers, cfg.NumberOfUsers)
+	assert.Equal(t, config.Default().RateLimit.Default, cfg.RateLimit.Default)
+
+	// Test invalid duration
+	err = os.WriteFile(path, []byte(`{"trashRetention": "a month"}`), 0o644)
+	assert.NoError(t, err)
+	_, err = config.Load(path)
+	assert.Error(t, err)
+
+	// Test missing file
+	_, err = config.Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	a := config.Default()
+	assert.Empty(t, config.Diff(a, config.Default()))
+
+	b := config.Default()
+	b.Addr = ":9090"
+	b.RateLimit.Routes = map[string]middleware.Limit{"POST /users/{id}/favorites": {Rate: 1, Burst: 2}}
+	b.Admin.Keys = map[string]string{"alice": "secret"}
+	assert.Equal(t, []string{"addr", "rateLimit", "admin"}, config.Diff(a, b))
+}
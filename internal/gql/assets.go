@@ -0,0 +1,240 @@
+package gql
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// assetResolver resolves the Asset interface and its Chart, Insight and Audience implementations
+type assetResolver struct {
+	asset models.Asset
+}
+
+func (a *assetResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(a.asset.GetID()))
+}
+
+func (a *assetResolver) Type() string {
+	return assetType(a.asset)
+}
+
+func (a *assetResolver) Description() string {
+	return a.asset.GetDescription()
+}
+
+func (a *assetResolver) ToChart() (*chartResolver, bool) {
+	chart, ok := a.asset.(models.Chart)
+	return &chartResolver{assetResolver: a, chart: chart}, ok
+}
+
+func (a *assetResolver) ToInsight() (*insightResolver, bool) {
+	insight, ok := a.asset.(models.Insight)
+	return &insightResolver{assetResolver: a, insight: insight}, ok
+}
+
+func (a *assetResolver) ToAudience() (*audienceResolver, bool) {
+	audience, ok := a.asset.(models.Audience)
+	return &audienceResolver{assetResolver: a, audience: audience}, ok
+}
+
+// chartResolver resolves the fields of a Chart
+type chartResolver struct {
+	*assetResolver
+	chart models.Chart
+}
+
+func (c *chartResolver) Title() string {
+	return c.chart.Title
+}
+
+func (c *chartResolver) XAxesTitle() string {
+	return c.chart.XAxesTitle
+}
+
+func (c *chartResolver) YAxesTitle() string {
+	return c.chart.YAxesTitle
+}
+
+func (c *chartResolver) DataPoints() []*pointResolver {
+	points := make([]*pointResolver, len(c.chart.DataPoints))
+	for i, p := range c.chart.DataPoints {
+		points[i] = &pointResolver{point: p}
+	}
+	return points
+}
+
+// pointResolver resolves the fields of a Point
+type pointResolver struct {
+	point models.Point
+}
+
+func (p *pointResolver) X() float64 {
+	return float64(p.point.X)
+}
+
+func (p *pointResolver) Y() float64 {
+	return float64(p.point.Y)
+}
+
+// insightResolver resolves the fields of an Insight
+type insightResolver struct {
+	*assetResolver
+	insight models.Insight
+}
+
+func (i *insightResolver) Text() string {
+	return i.insight.Text
+}
+
+// audienceResolver resolves the fields of an Audience
+type audienceResolver struct {
+	*assetResolver
+	audience models.Audience
+}
+
+func (a *audienceResolver) Age() int32 {
+	return int32(a.audience.Age)
+}
+
+func (a *audienceResolver) AgeGroup() string {
+	return a.audience.AgeGroup
+}
+
+func (a *audienceResolver) Gender() string {
+	return a.audience.Gender
+}
+
+func (a *audienceResolver) BirthCountry() string {
+	return a.audience.BirthCountry
+}
+
+func (a *audienceResolver) HoursSpentOnMedia() int32 {
+	return int32(a.audience.HoursSpentOnMedia)
+}
+
+func (a *audienceResolver) NumberOfPurchases() int32 {
+	return int32(a.audience.NumberOfPurchases)
+}
+
+// assetType returns the AssetType enum value of an asset
+func assetType(asset models.Asset) string {
+	return strings.ToUpper(string(asset.GetType()))
+}
+
+// assetInput is the input of the add and edit mutations, of which exactly one field must be set
+type assetInput struct {
+	Chart    *chartInput
+	Insight  *insightInput
+	Audience *audienceInput
+}
+
+type chartInput struct {
+	ID          graphql.ID
+	Description *string
+	Title       *string
+	XAxesTitle  *string
+	YAxesTitle  *string
+	DataPoints  *[]pointInput
+}
+
+type pointInput struct {
+	X float64
+	Y float64
+}
+
+type insightInput struct {
+	ID          graphql.ID
+	Description *string
+	Text        *string
+}
+
+type audienceInput struct {
+	ID                graphql.ID
+	Description       *string
+	Age               *int32
+	Gender            *string
+	BirthCountry      *string
+	HoursSpentOnMedia *int32
+	NumberOfPurchases *int32
+}
+
+// toModel converts the input to a pointer to the matching asset type, the same form utils.DecodeAsset returns
+func (in assetInput) toModel() (models.Asset, error) {
+	set := 0
+	for _, ok := range []bool{in.Chart != nil, in.Insight != nil, in.Audience != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("exactly one of chart, insight or audience must be set")
+	}
+
+	switch {
+	case in.Chart != nil:
+		id, err := parseID(in.Chart.ID)
+		if err != nil {
+			return nil, err
+		}
+		chart := &models.Chart{
+			ID:          id,
+			Type:        models.ChartType,
+			Description: value(in.Chart.Description),
+			Title:       value(in.Chart.Title),
+			XAxesTitle:  value(in.Chart.XAxesTitle),
+			YAxesTitle:  value(in.Chart.YAxesTitle),
+			DataPoints:  []models.Point{},
+		}
+		if in.Chart.DataPoints != nil {
+			for _, p := range *in.Chart.DataPoints {
+				chart.DataPoints = append(chart.DataPoints, models.Point{X: float32(p.X), Y: float32(p.Y)})
+			}
+		}
+		return chart, nil
+	case in.Insight != nil:
+		id, err := parseID(in.Insight.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.Insight{
+			ID:          id,
+			Type:        models.InsightType,
+			Description: value(in.Insight.Description),
+			Text:        value(in.Insight.Text),
+		}, nil
+	default:
+		id, err := parseID(in.Audience.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range []*int32{in.Audience.Age, in.Audience.HoursSpentOnMedia, in.Audience.NumberOfPurchases} {
+			if n != nil && *n < 0 {
+				return nil, errors.New("audience numbers cannot be negative")
+			}
+		}
+		return &models.Audience{
+			ID:                id,
+			Type:              models.AudienceType,
+			Description:       value(in.Audience.Description),
+			Age:               uint(value(in.Audience.Age)),
+			Gender:            value(in.Audience.Gender),
+			BirthCountry:      value(in.Audience.BirthCountry),
+			HoursSpentOnMedia: uint(value(in.Audience.HoursSpentOnMedia)),
+			NumberOfPurchases: uint(value(in.Audience.NumberOfPurchases)),
+		}, nil
+	}
+}
+
+// value returns the value of an optional input field, or its zero value when it was not given
+func value[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
@@ -0,0 +1,272 @@
+package gql
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+)
+
+// maxPageSize is the largest page of favourites that can be requested at once
+const maxPageSize = 100
+
+// Resolver is the root resolver of the queries and mutations
+type Resolver struct {
+	UserService *service.UserService
+}
+
+// User resolves a user by id, returning null for unknown users
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	userID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := r.UserService.GetUserFavorites(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userResolver{id: userID, favorites: favorites}, nil
+}
+
+// AddFavorite adds an asset to a user's favourites
+func (r *Resolver) AddFavorite(ctx context.Context, args struct {
+	UserID graphql.ID
+	Asset  assetInput
+}) (*assetResolver, error) {
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := args.Asset.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.UserService.AddUserFavorite(ctx, userID, asset); err != nil {
+		return nil, err
+	}
+	return &assetResolver{asset: deref(asset)}, nil
+}
+
+// EditFavorite replaces an asset of a user's favourites
+func (r *Resolver) EditFavorite(ctx context.Context, args struct {
+	UserID  graphql.ID
+	AssetID graphql.ID
+	Asset   assetInput
+}) (*assetResolver, error) {
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseID(args.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := args.Asset.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.UserService.EditUserFavorite(ctx, userID, assetID, asset); err != nil {
+		return nil, err
+	}
+	return &assetResolver{asset: deref(asset)}, nil
+}
+
+// DeleteFavorite deletes an asset from a user's favourites
+func (r *Resolver) DeleteFavorite(ctx context.Context, args struct {
+	UserID  graphql.ID
+	AssetID graphql.ID
+}) (bool, error) {
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return false, err
+	}
+	assetID, err := parseID(args.AssetID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.UserService.DeleteUserFavorite(ctx, userID, assetID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// userResolver resolves the fields of a User
+type userResolver struct {
+	id        int
+	favorites map[int]models.Asset
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(u.id))
+}
+
+// Favorites returns a page of the user's favourites ordered by id, after applying the filters
+func (u *userResolver) Favorites(args struct {
+	Type   *string
+	Search *string
+	First  int32 // First defaults to 20 in the schema
+	After  *string
+}) (*connectionResolver, error) {
+	first := int(args.First)
+	if first < 0 || first > maxPageSize {
+		return nil, errors.New("first must be between 0 and " + strconv.Itoa(maxPageSize))
+	}
+
+	after := 0
+	if args.After != nil {
+		id, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		after = id
+	}
+
+	var matches []models.Asset
+	for _, asset := range u.favorites {
+		asset = deref(asset)
+		if args.Type != nil && assetType(asset) != *args.Type {
+			continue
+		}
+		if args.Search != nil && !contains(asset, *args.Search) {
+			continue
+		}
+		matches = append(matches, asset)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].GetID() < matches[j].GetID() })
+
+	connection := &connectionResolver{totalCount: len(matches)}
+	for _, asset := range matches {
+		if asset.GetID() <= after {
+			continue
+		}
+		if len(connection.edges) == first {
+			connection.hasNextPage = true
+			break
+		}
+		connection.edges = append(connection.edges, &edgeResolver{asset: asset})
+	}
+	return connection, nil
+}
+
+// connectionResolver resolves a page of favourites
+type connectionResolver struct {
+	totalCount  int
+	edges       []*edgeResolver
+	hasNextPage bool
+}
+
+func (c *connectionResolver) TotalCount() int32 {
+	return int32(c.totalCount)
+}
+
+func (c *connectionResolver) Edges() []*edgeResolver {
+	return c.edges
+}
+
+func (c *connectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: c.hasNextPage}
+	if len(c.edges) > 0 {
+		cursor := c.edges[len(c.edges)-1].Cursor()
+		info.endCursor = &cursor
+	}
+	return info
+}
+
+// edgeResolver resolves a favourite of a page with its cursor
+type edgeResolver struct {
+	asset models.Asset
+}
+
+func (e *edgeResolver) Cursor() string {
+	return encodeCursor(e.asset.GetID())
+}
+
+func (e *edgeResolver) Node() *assetResolver {
+	return &assetResolver{asset: e.asset}
+}
+
+// pageInfoResolver resolves the position of a page
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+// encodeCursor returns the opaque cursor pointing after an asset id
+func encodeCursor(id int) string {
+	return base64.StdEncoding.EncodeToString([]byte("asset:" + strconv.Itoa(id)))
+}
+
+// decodeCursor returns the asset id a cursor points after
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), "asset:") {
+		return 0, errors.New("invalid cursor")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(string(data), "asset:"))
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	return id, nil
+}
+
+// parseID converts a GraphQL ID to the integer ids used by the service
+func parseID(id graphql.ID) (int, error) {
+	value, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, errors.New("invalid id")
+	}
+	return value, nil
+}
+
+// contains reports whether the description, title or text of an asset contains search, ignoring case
+func contains(asset models.Asset, search string) bool {
+	search = strings.ToLower(search)
+	fields := []string{asset.GetDescription()}
+	switch a := asset.(type) {
+	case models.Chart:
+		fields = append(fields, a.Title)
+	case models.Insight:
+		fields = append(fields, a.Text)
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// deref returns assets stored by pointer as values, so the resolvers only handle one form
+func deref(asset models.Asset) models.Asset {
+	switch a := asset.(type) {
+	case *models.Chart:
+		return *a
+	case *models.Insight:
+		return *a
+	case *models.Audience:
+		return *a
+	}
+	return asset
+}
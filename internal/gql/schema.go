@@ -0,0 +1,140 @@
+package gql
+
+import (
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+)
+
+// Schema is the GraphQL schema of the favourites API
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	# A user by id, or null if the user does not exist
+	user(id: ID!): User
+}
+
+type Mutation {
+	# Adds an asset to the user's favourites
+	addFavorite(userId: ID!, asset: AssetInput!): Asset!
+	# Replaces an asset of the user's favourites
+	editFavorite(userId: ID!, assetId: ID!, asset: AssetInput!): Asset!
+	# Deletes an asset from the user's favourites, returning true on success
+	deleteFavorite(userId: ID!, assetId: ID!): Boolean!
+}
+
+type User {
+	id: ID!
+	# The user's favourites ordered by id, optionally filtered by type and by a case-insensitive search
+	# in their description, title or text
+	favorites(type: AssetType, search: String, first: Int = 20, after: String): AssetConnection!
+}
+
+enum AssetType {
+	CHART
+	INSIGHT
+	AUDIENCE
+}
+
+interface Asset {
+	id: ID!
+	type: AssetType!
+	description: String!
+}
+
+type Chart implements Asset {
+	id: ID!
+	type: AssetType!
+	description: String!
+	title: String!
+	xAxesTitle: String!
+	yAxesTitle: String!
+	dataPoints: [Point!]!
+}
+
+type Point {
+	x: Float!
+	y: Float!
+}
+
+type Insight implements Asset {
+	id: ID!
+	type: AssetType!
+	description: String!
+	text: String!
+}
+
+type Audience implements Asset {
+	id: ID!
+	type: AssetType!
+	description: String!
+	age: Int!
+	ageGroup: String!
+	gender: String!
+	birthCountry: String!
+	hoursSpentOnMedia: Int!
+	numberOfPurchases: Int!
+}
+
+type AssetConnection {
+	totalCount: Int!
+	edges: [AssetEdge!]!
+	pageInfo: PageInfo!
+}
+
+type AssetEdge {
+	cursor: String!
+	node: Asset!
+}
+
+type PageInfo {
+	hasNextPage: Boolean!
+	endCursor: String
+}
+
+# Exactly one of the fields must be set
+input AssetInput {
+	chart: ChartInput
+	insight: InsightInput
+	audience: AudienceInput
+}
+
+input ChartInput {
+	id: ID!
+	description: String
+	title: String
+	xAxesTitle: String
+	yAxesTitle: String
+	dataPoints: [PointInput!]
+}
+
+input PointInput {
+	x: Float!
+	y: Float!
+}
+
+input InsightInput {
+	id: ID!
+	description: String
+	text: String
+}
+
+input AudienceInput {
+	id: ID!
+	description: String
+	age: Int
+	gender: String
+	birthCountry: String
+	hoursSpentOnMedia: Int
+	numberOfPurchases: Int
+}
+`
+
+// NewSchema parses the GraphQL schema with resolvers delegating to the UserService
+func NewSchema(userService *service.UserService) (*graphql.Schema, error) {
+	return graphql.ParseSchema(Schema, &Resolver{UserService: userService}, graphql.MaxDepth(10))
+}
@@ -0,0 +1,148 @@
+package gql_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/gql"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup returns the schema over a user owning a Chart, two Insights and an Audience
+func setup(t *testing.T) *graphql.Schema {
+	repo := repository.NewInMemoryUserRepository()
+	repo.Users = map[int]models.User{
+		1: {
+			ID: 1,
+			Favourites: map[int]models.Asset{
+				1: &models.Chart{ID: 1, Type: models.ChartType, Title: "Sales", DataPoints: []models.Point{{X: 1, Y: 2}}},
+				2: &models.Insight{ID: 2, Type: models.InsightType, Text: "Sales grew"},
+				3: models.Insight{ID: 3, Type: models.InsightType, Text: "Churn dropped"},
+				4: &models.Audience{ID: 4, Type: models.AudienceType, Age: 30, AgeGroup: "26-40", Gender: "Male", BirthCountry: "GR"},
+			},
+		},
+	}
+
+	schema, err := gql.NewSchema(service.NewUserService(repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// exec runs a query and returns its data as JSON, failing the test on errors
+func exec(t *testing.T, schema *graphql.Schema, query string, variables map[string]interface{}) string {
+	resp := schema.Exec(context.Background(), query, "", variables)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	return string(resp.Data)
+}
+
+func TestQueryFavorites(t *testing.T) {
+	schema := setup(t)
+
+	// Test fetching only the fields needed from each asset type
+	data := exec(t, schema, `{
+		user(id: 1) {
+			favorites {
+				totalCount
+				edges { node { id ... on Chart { title } ... on Insight { text } ... on Audience { ageGroup } } }
+			}
+		}
+	}`, nil)
+	assert.JSONEq(t, `{"user": {"favorites": {"totalCount": 4, "edges": [
+		{"node": {"id": "1", "title": "Sales"}},
+		{"node": {"id": "2", "text": "Sales grew"}},
+		{"node": {"id": "3", "text": "Churn dropped"}},
+		{"node": {"id": "4", "ageGroup": "26-40"}}
+	]}}}`, data)
+
+	// Test filtering by type and search
+	data = exec(t, schema, `{ user(id: 1) { favorites(type: INSIGHT, search: "sales") { edges { node { id type } } } } }`, nil)
+	assert.JSONEq(t, `{"user": {"favorites": {"edges": [{"node": {"id": "2", "type": "INSIGHT"}}]}}}`, data)
+
+	// Test unknown users resolve to null
+	data = exec(t, schema, `{ user(id: 999) { id } }`, nil)
+	assert.JSONEq(t, `{"user": null}`, data)
+}
+
+func TestQueryFavoritesPagination(t *testing.T) {
+	schema := setup(t)
+	query := `query($after: String) {
+		user(id: 1) { favorites(first: 3, after: $after) { edges { node { id } } pageInfo { hasNextPage endCursor } } }
+	}`
+
+	var page struct {
+		User struct {
+			Favorites struct {
+				Edges []struct {
+					Node struct{ ID string }
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			}
+		}
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(exec(t, schema, query, nil)), &page))
+	assert.Len(t, page.User.Favorites.Edges, 3)
+	assert.True(t, page.User.Favorites.PageInfo.HasNextPage)
+
+	// Test the next page starts after the end cursor of the previous one
+	variables := map[string]interface{}{"after": page.User.Favorites.PageInfo.EndCursor}
+	assert.NoError(t, json.Unmarshal([]byte(exec(t, schema, query, variables)), &page))
+	assert.Len(t, page.User.Favorites.Edges, 1)
+	assert.Equal(t, "4", page.User.Favorites.Edges[0].Node.ID)
+	assert.False(t, page.User.Favorites.PageInfo.HasNextPage)
+
+	// Test invalid arguments
+	resp := schema.Exec(context.Background(), `{ user(id: 1) { favorites(after: "nope") { totalCount } } }`, "", nil)
+	assert.Equal(t, "invalid cursor", resp.Errors[0].Message)
+	resp = schema.Exec(context.Background(), `{ user(id: 1) { favorites(first: 1000) { totalCount } } }`, "", nil)
+	assert.NotEmpty(t, resp.Errors)
+}
+
+func TestFavoriteMutations(t *testing.T) {
+	schema := setup(t)
+
+	// Test adding an asset returns the normalised asset
+	data := exec(t, schema, `mutation {
+		addFavorite(userId: 1, asset: {audience: {id: 5, age: 19, gender: "f", birthCountry: "Greece"}}) {
+			id ... on Audience { ageGroup gender birthCountry }
+		}
+	}`, nil)
+	assert.JSONEq(t, `{"addFavorite": {"id": "5", "ageGroup": "18-25", "gender": "Female", "birthCountry": "GR"}}`, data)
+
+	// Test editing an asset
+	data = exec(t, schema, `mutation {
+		editFavorite(userId: 1, assetId: 2, asset: {insight: {id: 2, text: "Edited"}}) { ... on Insight { text } }
+	}`, nil)
+	assert.JSONEq(t, `{"editFavorite": {"text": "Edited"}}`, data)
+
+	// Test deleting an asset
+	data = exec(t, schema, `mutation { deleteFavorite(userId: 1, assetId: 1) }`, nil)
+	assert.JSONEq(t, `{"deleteFavorite": true}`, data)
+	data = exec(t, schema, `{ user(id: 1) { favorites { totalCount } } }`, nil)
+	assert.JSONEq(t, `{"user": {"favorites": {"totalCount": 4}}}`, data)
+
+	// Test the errors of the service are returned as GraphQL errors
+	resp := schema.Exec(context.Background(), `mutation { deleteFavorite(userId: 1, assetId: 1) }`, "", nil)
+	assert.Equal(t, "asset not found", resp.Errors[0].Message)
+	resp = schema.Exec(context.Background(), `mutation { addFavorite(userId: 1, asset: {}) { id } }`, "", nil)
+	assert.Equal(t, "exactly one of chart, insight or audience must be set", resp.Errors[0].Message)
+
+	// Test values overflowing the float32 of the data points are rejected rather than stored as infinite
+	resp = schema.Exec(context.Background(), `mutation {
+		addFavorite(userId: 1, asset: {chart: {id: 9, dataPoints: [{x: 1, y: 1e300}]}}) { id }
+	}`, "", nil)
+	assert.Equal(t, "invalid data point", resp.Errors[0].Message)
+}
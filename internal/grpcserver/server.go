@@ -0,0 +1,153 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"sort"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/pb"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// requestIDMetadata is the metadata key clients send the ID of their request in, the gRPC counterpart of middleware.RequestIDHeader
+const requestIDMetadata = "x-request-id"
+
+// Server implements the FavoritesService gRPC service on top of the UserService
+type Server struct {
+	pb.UnimplementedFavoritesServiceServer
+	UserService *service.UserService
+}
+
+// NewServer initializes and returns a new Server
+func NewServer(userService *service.UserService) *Server {
+	return &Server{
+		UserService: userService,
+	}
+}
+
+// Register registers the FavoritesService on a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterFavoritesServiceServer(g, s)
+}
+
+// GetUserFavorite returns a single asset from the user's favourites
+func (s *Server) GetUserFavorite(ctx context.Context, req *pb.GetUserFavoriteRequest) (*pb.Asset, error) {
+	asset, err := s.UserService.GetUserFavorite(ctx, int(req.GetUserId()), int(req.GetAssetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(asset)
+}
+
+// ListUserFavorites streams the user's favourites ordered by asset id
+func (s *Server) ListUserFavorites(req *pb.ListUserFavoritesRequest, stream pb.FavoritesService_ListUserFavoritesServer) error {
+	favorites, err := s.UserService.GetUserFavorites(stream.Context(), int(req.GetUserId()))
+	if err != nil {
+		return toStatus(err)
+	}
+
+	ids := make([]int, 0, len(favorites))
+	for id := range favorites {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		asset, err := toProto(favorites[id])
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(asset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddUserFavorite adds an asset to the user's favourites
+func (s *Server) AddUserFavorite(ctx context.Context, req *pb.AddUserFavoriteRequest) (*pb.Asset, error) {
+	asset, err := req.GetAsset().ToModel()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.UserService.AddUserFavorite(actorContext(ctx), int(req.GetUserId()), asset); err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(asset)
+}
+
+// EditUserFavorite edits an asset in the user's favourites
+func (s *Server) EditUserFavorite(ctx context.Context, req *pb.EditUserFavoriteRequest) (*pb.Asset, error) {
+	asset, err := req.GetAsset().ToModel()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.UserService.EditUserFavorite(actorContext(ctx), int(req.GetUserId()), int(req.GetAssetId()), asset); err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(asset)
+}
+
+// DeleteUserFavorite deletes an asset from the user's favourites
+func (s *Server) DeleteUserFavorite(ctx context.Context, req *pb.DeleteUserFavoriteRequest) (*emptypb.Empty, error) {
+	if err := s.UserService.DeleteUserFavorite(actorContext(ctx), int(req.GetUserId()), int(req.GetAssetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// toProto converts an asset to its protobuf message
+func toProto(asset models.Asset) (*pb.Asset, error) {
+	message, err := pb.FromModel(asset)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return message, nil
+}
+
+// toStatus maps the errors of the service to gRPC status codes, the same way the REST handlers map them to HTTP status codes
+func toStatus(err error) error {
+	switch err.Error() {
+	case "user not found", "asset not found":
+		return status.Error(codes.NotFound, err.Error())
+	case "asset already exists":
+		return status.Error(codes.AlreadyExists, err.Error())
+	case "edited asset ID does not match existing asset ID",
+		"edited asset type does not match existing asset type",
+		"invalid gender",
+		"invalid birth country",
+		"invalid asset id", "too many data points", "invalid data point", "description too long", "title too long", "x axes title too long", "y axes title too long", "text too long":
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// actorContext returns a copy of ctx carrying the caller of an RPC and the ID of the call,
+// identified like the callers and requests of the REST API
+func actorContext(ctx context.Context) context.Context {
+	var ip, requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDMetadata); len(ids) > 0 {
+			requestID = ids[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	ctx = service.WithRequestID(ctx, middleware.RequestIDFor(requestID))
+	return service.WithActor(ctx, middleware.ClientKeyFor(ctx, ip))
+}
@@ -0,0 +1,162 @@
+package grpcserver_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/grpcserver"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/pb"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setup starts a gRPC server over an in-memory connection, with a user owning an Insight and a Chart,
+// and returns a client connected to it
+func setup(t *testing.T) (pb.FavoritesServiceClient, *service.UserService) {
+	repo := repository.NewInMemoryUserRepository()
+	repo.Users = map[int]models.User{
+		1: {
+			ID: 1,
+			Favourites: map[int]models.Asset{
+				1: &models.Insight{ID: 1, Type: models.InsightType, Description: "Sample Insight", Text: "Sample Insight Text"},
+				2: &models.Chart{ID: 2, Type: models.ChartType, Title: "Sample Chart", DataPoints: []models.Point{{X: 1, Y: 2}}},
+			},
+		},
+	}
+	userService := service.NewUserService(repo)
+	userService.AuditSink = repository.NewInMemoryAuditSink(0)
+
+	listener := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	grpcserver.NewServer(userService).Register(g)
+	go g.Serve(listener)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewFavoritesServiceClient(conn), userService
+}
+
+func TestGetUserFavorite(t *testing.T) {
+	client, _ := setup(t)
+	ctx := context.Background()
+
+	asset, err := client.GetUserFavorite(ctx, &pb.GetUserFavoriteRequest{UserId: 1, AssetId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "Sample Insight Text", asset.GetInsight().GetText())
+
+	// Test non-existing user and asset
+	_, err = client.GetUserFavorite(ctx, &pb.GetUserFavoriteRequest{UserId: 999, AssetId: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetUserFavorite(ctx, &pb.GetUserFavoriteRequest{UserId: 1, AssetId: 999})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListUserFavorites(t *testing.T) {
+	client, _ := setup(t)
+	ctx := context.Background()
+
+	stream, err := client.ListUserFavorites(ctx, &pb.ListUserFavoritesRequest{UserId: 1})
+	assert.NoError(t, err)
+
+	var assets []*pb.Asset
+	for {
+		asset, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		assets = append(assets, asset)
+	}
+
+	// Test the favourites are streamed in order of their id
+	assert.Len(t, assets, 2)
+	assert.Equal(t, int64(1), assets[0].GetInsight().GetId())
+	assert.Equal(t, "Sample Chart", assets[1].GetChart().GetTitle())
+	assert.Equal(t, float32(2), assets[1].GetChart().GetDataPoints()[0].GetY())
+
+	// Test non-existing user
+	stream, _ = client.ListUserFavorites(ctx, &pb.ListUserFavoritesRequest{UserId: 999})
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAddUserFavorite(t *testing.T) {
+	client, userService := setup(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret", "x-request-id", "add-3")
+
+	audience := &pb.Asset{Asset: &pb.Asset_Audience{Audience: &pb.Audience{Id: 3, Age: 30, Gender: "f", BirthCountry: "Greece"}}}
+	asset, err := client.AddUserFavorite(ctx, &pb.AddUserFavoriteRequest{UserId: 1, Asset: audience})
+	assert.NoError(t, err)
+
+	// Test the returned asset is normalised like through the REST API
+	assert.Equal(t, "26-40", asset.GetAudience().GetAgeGroup())
+	assert.Equal(t, "Female", asset.GetAudience().GetGender())
+	assert.Equal(t, "GR", asset.GetAudience().GetBirthCountry())
+
+	// Test the caller is recorded in the history
+	history, _ := userService.GetUserFavoriteHistory(ctx, 1, 3)
+	assert.Contains(t, history[0].Actor, "ip:")
+
+	// Test the ID of the call is recorded in the audit log
+	page, _ := userService.AuditSink.GetAuditRecords(repository.AuditFilter{})
+	assert.Equal(t, "add-3", page.Records[0].RequestID)
+
+	// Test invalid requests
+	_, err = client.AddUserFavorite(ctx, &pb.AddUserFavoriteRequest{UserId: 1, Asset: audience})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.AddUserFavorite(ctx, &pb.AddUserFavoriteRequest{UserId: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.AddUserFavorite(ctx, &pb.AddUserFavoriteRequest{UserId: 1, Asset: &pb.Asset{Asset: &pb.Asset_Audience{Audience: &pb.Audience{Id: 4, BirthCountry: "Atlantis"}}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	nan := &pb.Asset{Asset: &pb.Asset_Chart{Chart: &pb.Chart{Id: 5, DataPoints: []*pb.Point{{X: 1, Y: float32(math.NaN())}}}}}
+	_, err = client.AddUserFavorite(ctx, &pb.AddUserFavoriteRequest{UserId: 1, Asset: nan})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "invalid data point")
+}
+
+func TestEditUserFavorite(t *testing.T) {
+	client, _ := setup(t)
+	ctx := context.Background()
+
+	insight := &pb.Asset{Asset: &pb.Asset_Insight{Insight: &pb.Insight{Id: 1, Text: "Edited"}}}
+	asset, err := client.EditUserFavorite(ctx, &pb.EditUserFavoriteRequest{UserId: 1, AssetId: 1, Asset: insight})
+	assert.NoError(t, err)
+	assert.Equal(t, "Edited", asset.GetInsight().GetText())
+
+	// Test editing an asset with a different type
+	_, err = client.EditUserFavorite(ctx, &pb.EditUserFavoriteRequest{UserId: 1, AssetId: 2, Asset: &pb.Asset{Asset: &pb.Asset_Insight{Insight: &pb.Insight{Id: 2}}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Test non-existing asset
+	_, err = client.EditUserFavorite(ctx, &pb.EditUserFavoriteRequest{UserId: 1, AssetId: 999, Asset: insight})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDeleteUserFavorite(t *testing.T) {
+	client, _ := setup(t)
+	ctx := context.Background()
+
+	_, err := client.DeleteUserFavorite(ctx, &pb.DeleteUserFavoriteRequest{UserId: 1, AssetId: 1})
+	assert.NoError(t, err)
+
+	_, err = client.DeleteUserFavorite(ctx, &pb.DeleteUserFavoriteRequest{UserId: 1, AssetId: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DefaultAuditLimit and MaxAuditLimit are the default and largest number of audit records returned by a query
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+// AdminHandler serves the operations on the running instance to the operators holding one of the admin keys
+type AdminHandler struct {
+	AdminService *service.AdminService
+	Keys         map[string]string // API keys by the name of their holder
+}
+
+// NewAdminHandler initializes and returns a new AdminHandler accepting the given API keys
+func NewAdminHandler(adminService *service.AdminService, keys map[string]string) *AdminHandler {
+	return &AdminHandler{
+		AdminService: adminService,
+		Keys:         keys,
+	}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the admin handler under /admin, behind the API keys
+func (handler *AdminHandler) RegisterRoutes(r *mux.Router) {
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireAPIKeys(handler.Keys))
+	admin.HandleFunc("/stats", handler.GetStats).Methods(http.MethodGet)
+	admin.HandleFunc("/snapshots", handler.CreateSnapshot).Methods(http.MethodPost)
+	admin.HandleFunc("/config/reload", handler.ReloadConfig).Methods(http.MethodPost)
+	admin.HandleFunc("/sample-data", handler.RegenerateSampleData).Methods(http.MethodPost)
+	admin.HandleFunc("/users/{id}", handler.PurgeUser).Methods(http.MethodDelete)
+	admin.HandleFunc("/users/{id}/export", handler.ExportUser).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{id}/erasure", handler.EraseUser).Methods(http.MethodPost)
+	admin.HandleFunc("/erasures", handler.GetErasureRecords).Methods(http.MethodGet)
+	admin.HandleFunc("/audit", handler.GetAuditRecords).Methods(http.MethodGet)
+}
+
+// sampleDataRequest is the body of a sample data regeneration
+type sampleDataRequest struct {
+	Users  *int  `json:"users"`
+	Assets *int  `json:"assets"`
+	Seed   int64 `json:"seed"` // zero generates the same users as at startup
+}
+
+// GetStats returns the number of users and favourites, with the memory they take
+func (h *AdminHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.AdminService.Stats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+// CreateSnapshot writes the users to a new fixture file the server can be started from
+func (h *AdminHandler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.AdminService.Snapshot(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// ReloadConfig reloads the configuration file, applying the settings that can change without a restart
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	result, err := h.AdminService.ReloadConfig(r.Context())
+	if err != nil {
+		switch err.Error() {
+		case "configuration reload not available":
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, result)
+}
+
+// RegenerateSampleData replaces all users with newly generated sample users
+func (h *AdminHandler) RegenerateSampleData(w http.ResponseWriter, r *http.Request) {
+	var body sampleDataRequest
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Users == nil || body.Assets == nil {
+		http.Error(w, "invalid number of users or assets", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.AdminService.RegenerateSampleData(requestContext(r), *body.Users, *body.Assets, body.Seed); err != nil {
+		switch err.Error() {
+		case "invalid number of users or assets":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case "repository cannot replace its users":
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeUser permanently deletes the favourites, history, trash and collections of a user
+func (h *AdminHandler) PurgeUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.AdminService.PurgeUser(requestContext(r), userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, result)
+}
+
+// ExportUser returns everything held about a user as a zip archive of JSON files, or as a single JSON document with ?format=json
+func (h *AdminHandler) ExportUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "zip" && format != "json" {
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.AdminService.ExportUser(r.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if format == "json" {
+		writeJSON(w, export)
+		return
+	}
+
+	// Build the archive before writing the headers, so a failure can still be reported with its status
+	var archive bytes.Buffer
+	if err := export.WriteArchive(&archive); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.zip"`, userID))
+	w.Write(archive.Bytes())
+}
+
+// EraseUser erases everything held about a user and returns the record of the erasure
+func (h *AdminHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	record, err := h.AdminService.EraseUser(requestContext(r), userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, record)
+}
+
+// GetErasureRecords returns the records of every erasure, oldest first
+func (h *AdminHandler) GetErasureRecords(w http.ResponseWriter, r *http.Request) {
+	records, err := h.AdminService.GetErasureRecords(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, records)
+}
+
+// GetAuditRecords returns a page of the records of the audit log, oldest first. The from and to query parameters select
+// the records in a time range (RFC 3339, from inclusive and to exclusive), actor and userId the records of a caller
+// or user, and limit the number of records returned. The next page is requested with the same parameters and
+// the next cursor of the page as after.
+func (h *AdminHandler) GetAuditRecords(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.AdminService.GetAuditRecords(r.Context(), filter)
+	if err != nil {
+		switch err.Error() {
+		case "invalid cursor":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case "audit log not available":
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, page)
+}
+
+// parseAuditFilter builds an AuditFilter from the query string
+func parseAuditFilter(values url.Values) (repository.AuditFilter, error) {
+	filter := repository.AuditFilter{After: values.Get("after"), Actor: values.Get("actor"), Limit: DefaultAuditLimit}
+
+	var err error
+	if from := values.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, errors.New("invalid from")
+		}
+	}
+	if to := values.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, errors.New("invalid to")
+		}
+	}
+	if userID := values.Get("userId"); userID != "" {
+		if filter.UserID, err = strconv.Atoi(userID); err != nil || filter.UserID <= 0 {
+			return filter, errors.New("invalid userId")
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 || filter.Limit > MaxAuditLimit {
+			return filter, errors.New("invalid limit")
+		}
+	}
+	return filter, nil
+}
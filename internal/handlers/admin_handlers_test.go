@@ -0,0 +1,310 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAdmin returns a router serving the admin API over the sample users, sending the admin key with every request
+func setupAdmin(t *testing.T) *mux.Router {
+	userService := setup()
+	userService.AuditSink = repository.NewInMemoryAuditSink(0)
+	adminService := service.NewAdminService(userService, service.NewCollectionService(userService.UserRepository, repository.NewInMemoryCollectionRepository()))
+	adminService.SnapshotDir = filepath.Join(t.TempDir(), "snapshots")
+	adminService.Reloader = func(ctx context.Context) (service.ReloadResult, error) {
+		return service.ReloadResult{Applied: []string{"rateLimit"}, RestartRequired: []string{}}, nil
+	}
+
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set(middleware.APIKeyHeader, "admin-secret")
+			next.ServeHTTP(w, r)
+		})
+	})
+	handlers.NewAdminHandler(adminService, map[string]string{"ops": "admin-secret"}).RegisterRoutes(r)
+	return r
+}
+
+// TestAdminHandlers tests the GetStats, CreateSnapshot, ReloadConfig, RegenerateSampleData, PurgeUser, ExportUser,
+// EraseUser, GetErasureRecords and GetAuditRecords handlers
+func TestAdminHandlers(t *testing.T) {
+	adminTests := []TestCase{
+		{
+			name:           "GetStats",
+			method:         "GET",
+			url:            "/admin/stats",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"users\":3,\"favorites\":9",
+		},
+		{
+			name:           "CreateSnapshot",
+			method:         "POST",
+			url:            "/admin/snapshots",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "\"users\":3,\"favorites\":9",
+		},
+		{
+			name:           "ReloadConfig",
+			method:         "POST",
+			url:            "/admin/config/reload",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"applied\":[\"rateLimit\"],\"restartRequired\":[]}",
+		},
+		{
+			name:           "PurgeUser",
+			method:         "DELETE",
+			url:            "/admin/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"userId\":1,\"favorites\":3",
+		},
+		{
+			name:           "PurgeUserNotFound",
+			method:         "DELETE",
+			url:            "/admin/users/999",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found",
+		},
+		{
+			name:           "PurgeUserInvalidID",
+			method:         "DELETE",
+			url:            "/admin/users/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid user id",
+		},
+		{
+			name:           "ExportUserJSON",
+			method:         "GET",
+			url:            "/admin/users/2/export?format=json",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"userId\":2",
+		},
+		{
+			name:           "ExportUserInvalidFormat",
+			method:         "GET",
+			url:            "/admin/users/2/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid format",
+		},
+		{
+			name:           "EraseUser",
+			method:         "POST",
+			url:            "/admin/users/3/erasure",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"userId\":3,\"actor\":\"user:ops\"",
+		},
+		{
+			name:           "EraseUserNotFound",
+			method:         "POST",
+			url:            "/admin/users/3/erasure",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found",
+		},
+		{
+			name:           "ExportErasedUser",
+			method:         "GET",
+			url:            "/admin/users/3/export",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found",
+		},
+		{
+			name:           "GetErasureRecords",
+			method:         "GET",
+			url:            "/admin/erasures",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[{\"id\":1,\"userId\":3",
+		},
+		{
+			name:           "GetAuditRecords",
+			method:         "GET",
+			url:            "/admin/audit?from=2024-01-01T00:00:00Z&to=2100-01-01T00:00:00Z&actor=user:ops&userId=1&limit=1000",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"records":[],"next":`,
+		},
+		{
+			name:           "GetAuditRecordsInvalidCursor",
+			method:         "GET",
+			url:            "/admin/audit?after=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid cursor",
+		},
+		{
+			name:           "GetAuditRecordsInvalidFrom",
+			method:         "GET",
+			url:            "/admin/audit?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid from",
+		},
+		{
+			name:           "GetAuditRecordsInvalidTo",
+			method:         "GET",
+			url:            "/admin/audit?to=2024-01-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid to",
+		},
+		{
+			name:           "GetAuditRecordsInvalidUserID",
+			method:         "GET",
+			url:            "/admin/audit?userId=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid userId",
+		},
+		{
+			name:           "GetAuditRecordsInvalidLimit",
+			method:         "GET",
+			url:            "/admin/audit?limit=1001",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid limit",
+		},
+		{
+			name:           "RegenerateSampleData",
+			method:         "POST",
+			url:            "/admin/sample-data",
+			payload:        map[string]interface{}{"users": 5, "assets": 2, "seed": 7},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "GetStatsAfterRegeneration",
+			method:         "GET",
+			url:            "/admin/stats",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"users\":5,\"favorites\":10",
+		},
+		{
+			name:           "RegenerateSampleDataMissingSize",
+			method:         "POST",
+			url:            "/admin/sample-data",
+			payload:        map[string]interface{}{"users": 5},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid number of users or assets",
+		},
+		{
+			name:           "RegenerateSampleDataInvalidSize",
+			method:         "POST",
+			url:            "/admin/sample-data",
+			payload:        map[string]interface{}{"users": -1, "assets": 2},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid number of users or assets",
+		},
+	}
+
+	r := setupAdmin(t)
+	for _, tc := range adminTests {
+		t.Run(tc.name, func(t *testing.T) {
+			RunTestCase(t, r, tc)
+		})
+	}
+}
+
+func TestAdminHandlersRequireKey(t *testing.T) {
+	userService := setup()
+	r := mux.NewRouter()
+	handlers.NewAdminHandler(service.NewAdminService(userService, nil), map[string]string{"ops": "admin-secret"}).RegisterRoutes(r)
+
+	for _, key := range []string{"", "wrong-secret"} {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/users/1", nil)
+		req.Header.Set(middleware.APIKeyHeader, key)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, key)
+	}
+
+	// Test the rejected requests changed nothing
+	favorites, _ := userService.GetUserFavorites(context.Background(), 1)
+	assert.Len(t, favorites, 3)
+}
+
+func TestAdminExportArchive(t *testing.T) {
+	r := setupAdmin(t)
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/1/export", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	// Test the export is a zip archive downloaded as a file
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="user-1-export.zip"`, rr.Header().Get("Content-Disposition"))
+	archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, "manifest.json", archive.File[0].Name)
+}
+
+func TestAdminAuditLog(t *testing.T) {
+	userService := setup()
+	r := mux.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.NewUserHandler(userService).RegisterRoutes(r)
+	handlers.NewAdminHandler(service.NewAdminService(userService, nil), map[string]string{"ops": "admin-secret"}).RegisterRoutes(r)
+
+	// Test the audit log is not available without a sink
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin-secret")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotImplemented, rr.Code)
+	userService.AuditSink = repository.NewInMemoryAuditSink(0)
+	userService.AuditKeys = repository.NewInMemoryAuditKeyStore()
+
+	// Edit and delete favourites of two users, sending the ID of the edit and an API key that is not verified
+	edit := httptest.NewRequest(http.MethodPut, "/users/1/favorites/1", strings.NewReader(`{"id":1,"type":"Insight","text":"Audited"}`))
+	edit.Header.Set("Content-Type", "application/json")
+	edit.Header.Set(middleware.RequestIDHeader, "edit-1")
+	edit.Header.Set(middleware.APIKeyHeader, "user:alice")
+	r.ServeHTTP(httptest.NewRecorder(), edit)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/users/2/favorites/2", nil))
+
+	// Test the changes are returned with the caller, request and changed fields, with their values
+	req = httptest.NewRequest(http.MethodGet, "/admin/audit?userId=1", nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin-secret")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var page models.AuditPage
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	records := page.Records
+	assert.Len(t, records, 1)
+	assert.Equal(t, "edit-1", records[0].RequestID)
+	// Test the actor is the IP of the caller, since the unverified API key cannot be trusted
+	assert.Equal(t, "ip:192.0.2.1", records[0].Actor)
+	assert.Equal(t, models.OperationEdit, records[0].Operation)
+	assert.Equal(t, []string{"description", "text"}, records[0].Fields)
+	assert.Len(t, records[0].Changes, 2)
+	assert.Equal(t, json.RawMessage(`"Audited"`), records[0].Changes[1].After)
+	assert.NotContains(t, rr.Body.String(), "encryptedChanges")
+
+	// Test the request ID is generated for the requests sent without one, paging with the cursors
+	req = httptest.NewRequest(http.MethodGet, "/admin/audit?limit=1", nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin-secret")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	assert.Len(t, page.Records, 1)
+	assert.Equal(t, "edit-1", page.Records[0].RequestID)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/audit?limit=1&after="+page.Next, nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin-secret")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	page = models.AuditPage{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	assert.Len(t, page.Records, 1)
+	assert.Equal(t, models.OperationDelete, page.Records[0].Operation)
+	assert.NotEmpty(t, page.Records[0].RequestID)
+	assert.NotEqual(t, "edit-1", page.Records[0].RequestID)
+}
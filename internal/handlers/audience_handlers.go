@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+)
+
+// AggregateUserAudiences aggregates the Audience favourites of a single user.
+// The groupBy query parameter lists the dimensions to group by, and any dimension
+// given as a query parameter filters the favourites by the listed values.
+func (h *UserHandler) AggregateUserAudiences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	query, err := parseAudienceQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	aggregation, err := h.UserService.AggregateUserAudiences(r.Context(), userID, query)
+	if err != nil {
+		http.Error(w, err.Error(), aggregationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aggregation)
+}
+
+// AggregateAudiences aggregates the Audience favourites of all users, accepting the same query parameters as AggregateUserAudiences
+func (h *UserHandler) AggregateAudiences(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAudienceQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	aggregation, err := h.UserService.AggregateAudiences(r.Context(), query)
+	if err != nil {
+		http.Error(w, err.Error(), aggregationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aggregation)
+}
+
+// aggregationErrorStatus returns the HTTP status of an aggregation error
+func aggregationErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found":
+		return http.StatusNotFound
+	case "invalid audience dimension", "invalid gender", "invalid birth country":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseAudienceQuery builds an AudienceQuery from the query string.
+// Multiple values can be given either by repeating a parameter or as a comma separated list.
+func parseAudienceQuery(values url.Values) (service.AudienceQuery, error) {
+	query := service.AudienceQuery{Filters: make(map[service.AudienceDimension][]string)}
+
+	for _, name := range splitValues(values["groupBy"]) {
+		d, err := service.ParseAudienceDimension(name)
+		if err != nil {
+			return query, err
+		}
+		query.GroupBy = append(query.GroupBy, d)
+	}
+
+	for _, d := range service.AudienceDimensions {
+		if filter := splitValues(values[string(d)]); len(filter) > 0 {
+			query.Filters[d] = filter
+		}
+	}
+
+	return query, nil
+}
+
+// splitValues splits comma separated values and drops the empty ones
+func splitValues(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
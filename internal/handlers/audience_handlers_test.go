@@ -0,0 +1,121 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// TestAudienceHandlers tests the AggregateUserAudiences and AggregateAudiences handlers
+func TestAudienceHandlers(t *testing.T) {
+	aggregateAudiencesTests := []TestCase{
+		{
+			name:           "ValidAggregateUserAudiences",
+			method:         "GET",
+			url:            "/users/1/audiences/aggregate?groupBy=gender",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"groups\":[{\"key\":{\"gender\":\"Male\"},\"count\":1",
+		},
+		{
+			name:           "ValidAggregateAllUsersWithFilter",
+			method:         "GET",
+			url:            "/audiences/aggregate?groupBy=gender,birthCountry&gender=Male",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"total\":{\"key\":{},\"count\":3",
+		},
+		{
+			name:           "AggregateFilteredOut",
+			method:         "GET",
+			url:            "/audiences/aggregate?gender=Female",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"groups\":[]",
+		},
+		{
+			name:           "AggregateFilterAliases",
+			method:         "GET",
+			url:            "/audiences/aggregate?gender=m",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"total\":{\"key\":{},\"count\":3",
+		},
+		{
+			name:           "AggregateInvalidGenderFilter",
+			method:         "GET",
+			url:            "/audiences/aggregate?gender=robot",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid gender",
+		},
+		{
+			name:           "AggregateInvalidCountryFilter",
+			method:         "GET",
+			url:            "/users/1/audiences/aggregate?birthCountry=Atlantis",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid birth country",
+		},
+		{
+			name:           "AggregateInvalidDimension",
+			method:         "GET",
+			url:            "/audiences/aggregate?groupBy=height",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid audience dimension",
+		},
+		{
+			name:           "AggregateUserNotFound",
+			method:         "GET",
+			url:            "/users/999999/audiences/aggregate",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found",
+		},
+	}
+
+	for _, tc := range aggregateAudiencesTests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup userService and userHandler
+			userService := setup()
+			userHandler := handlers.NewUserHandler(userService)
+
+			// Create a new Router and register the routes for the UserHandler
+			r := mux.NewRouter()
+			userHandler.RegisterRoutes(r)
+
+			RunTestCase(t, r, tc)
+		})
+	}
+}
+
+// unavailableRepository is a UserRepository whose reads fail with an unexpected error
+type unavailableRepository struct {
+	repository.UserRepository
+}
+
+func (unavailableRepository) GetUserIDs(ctx context.Context) ([]int, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (unavailableRepository) GetUserFavorites(ctx context.Context, userID int) (map[int]models.Asset, error) {
+	return nil, errors.New("connection refused")
+}
+
+// TestAudienceHandlersRepositoryError tests the aggregations failing with an unexpected error get 500 Internal Server Error,
+// while the invalid filters still get 400 Bad Request
+func TestAudienceHandlersRepositoryError(t *testing.T) {
+	userService := setup()
+	userService.UserRepository = unavailableRepository{userService.UserRepository}
+	r := mux.NewRouter()
+	handlers.NewUserHandler(userService).RegisterRoutes(r)
+
+	for _, tc := range []TestCase{
+		{name: "AggregateUserAudiences", method: "GET", url: "/users/1/audiences/aggregate", expectedStatus: http.StatusInternalServerError, expectedBody: "connection refused"},
+		{name: "AggregateAudiences", method: "GET", url: "/audiences/aggregate", expectedStatus: http.StatusInternalServerError, expectedBody: "connection refused"},
+		{name: "AggregateUserInvalidFilter", method: "GET", url: "/users/1/audiences/aggregate?gender=robot", expectedStatus: http.StatusBadRequest, expectedBody: "invalid gender"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			RunTestCase(t, r, tc)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
 	"github.com/ceciivanov/platform-go-challenge/internal/repository"
 	"github.com/ceciivanov/platform-go-challenge/internal/service"
 	"github.com/gorilla/mux"
@@ -226,3 +228,54 @@ func BenchmarkEditUserFavorite(b *testing.B) {
 		b.Logf("Request took %v", elapsed)
 	}
 }
+
+// discardResponseWriter is a ResponseWriter dropping the body, so the benchmarks only measure
+// the memory used to produce the response and not the memory used to record it
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardResponseWriter) WriteHeader(status int)      {}
+func (w *discardResponseWriter) Flush()                      {}
+
+// BenchmarkGetUserFavoritesLarge lists 20000 favourites, encoded at once as before or streamed one asset at a time,
+// with and without compression. Run it with -benchmem to compare the allocations.
+func BenchmarkGetUserFavoritesLarge(b *testing.B) {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(1, 20000)
+	userHandler := handlers.NewUserHandler(service.NewUserService(repo))
+	compressor := middleware.NewCompressor(middleware.CompressionConfig{Enabled: true, MinSize: 1024})
+
+	benchmarks := []struct {
+		name            string
+		streamThreshold int
+		acceptEncoding  string
+	}{
+		{"Buffered", math.MaxInt, ""},
+		{"Streamed", handlers.DefaultStreamThreshold, ""},
+		{"BufferedGzip", math.MaxInt, "gzip"},
+		{"StreamedGzip", handlers.DefaultStreamThreshold, "gzip"},
+		{"StreamedZstd", handlers.DefaultStreamThreshold, "zstd"},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			userHandler.StreamThreshold = bm.streamThreshold
+			r := mux.NewRouter()
+			userHandler.RegisterRoutes(r)
+			r.Use(compressor.Middleware)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1/favorites", nil)
+			if bm.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", bm.acceptEncoding)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				r.ServeHTTP(&discardResponseWriter{header: http.Header{}}, req)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// CollectionHandler serves the collections users share their favourites with.
+// The user in the path is the one acting, so the service checks the user's role in the collection.
+type CollectionHandler struct {
+	CollectionService *service.CollectionService
+}
+
+// NewCollectionHandler initializes and returns a new CollectionHandler
+func NewCollectionHandler(collectionService *service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		CollectionService: collectionService,
+	}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the collection handler
+func (handler *CollectionHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/users/{id}/collections", handler.GetUserCollections).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/collections", handler.CreateCollection).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/collections/{collectionID}", handler.GetCollection).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/collections/{collectionID}", handler.DeleteCollection).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{id}/collections/{collectionID}/members/{memberID}", handler.ShareCollection).Methods(http.MethodPut)
+	r.HandleFunc("/users/{id}/collections/{collectionID}/members/{memberID}", handler.UnshareCollection).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{id}/collections/{collectionID}/assets", handler.AddCollectionAsset).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/collections/{collectionID}/assets/{ownerID}/{assetID}", handler.RemoveCollectionAsset).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{id}/shared", handler.GetSharedCollections).Methods(http.MethodGet)
+}
+
+// CreateCollection creates a collection owned by the user
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	collection, err := h.CollectionService.CreateCollection(requestContext(r), userID, body.Name)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(collection)
+}
+
+// GetUserCollections returns the collections owned by the user
+func (h *CollectionHandler) GetUserCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	collections, err := h.CollectionService.GetUserCollections(r.Context(), userID)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, collections)
+}
+
+// GetSharedCollections returns the collections shared with the user
+func (h *CollectionHandler) GetSharedCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	collections, err := h.CollectionService.GetSharedCollections(r.Context(), userID)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, collections)
+}
+
+// GetCollection returns a collection with its assets
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+
+	collection, err := h.CollectionService.GetCollection(r.Context(), userID, collectionID)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, collection)
+}
+
+// DeleteCollection deletes a collection owned by the user
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+
+	if err := h.CollectionService.DeleteCollection(requestContext(r), userID, collectionID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ShareCollection gives a member a role in a collection owned by the user
+func (h *CollectionHandler) ShareCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+	memberID, ok := pathID(w, r, "memberID")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Role models.Role `json:"role"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	collection, err := h.CollectionService.ShareCollection(requestContext(r), userID, collectionID, memberID, body.Role)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, collection)
+}
+
+// UnshareCollection removes a member from a collection
+func (h *CollectionHandler) UnshareCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+	memberID, ok := pathID(w, r, "memberID")
+	if !ok {
+		return
+	}
+
+	if err := h.CollectionService.UnshareCollection(requestContext(r), userID, collectionID, memberID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddCollectionAsset adds one of the user's favourites to a collection
+func (h *CollectionHandler) AddCollectionAsset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		AssetID int `json:"assetId"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	collection, err := h.CollectionService.AddCollectionAsset(requestContext(r), userID, collectionID, body.AssetID)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, collection)
+}
+
+// RemoveCollectionAsset removes an asset from a collection
+func (h *CollectionHandler) RemoveCollectionAsset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	collectionID, ok := pathID(w, r, "collectionID")
+	if !ok {
+		return
+	}
+	ownerID, ok := pathID(w, r, "ownerID")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+
+	if err := h.CollectionService.RemoveCollectionAsset(requestContext(r), userID, collectionID, ownerID, assetID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCollectionError maps the errors of the collection service to their HTTP status
+func writeCollectionError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "user not found", "collection not found", "member not found", "asset not found", "asset not in collection":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case "permission denied":
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case "collection modified concurrently":
+		http.Error(w, err.Error(), http.StatusConflict)
+	case "invalid collection name", "invalid role", "cannot share a collection with its owner", "asset already in collection":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// TestCollectionHandlers tests the collection handlers.
+// The steps of each scenario run in order against the same router, since they depend on the previous changes.
+func TestCollectionHandlers(t *testing.T) {
+	scenarios := map[string][]TestCase{
+		"ShareWithViewer": {
+			{name: "Create", method: "POST", url: "/users/1/collections", payload: map[string]string{"name": "Team picks"}, expectedStatus: http.StatusCreated, expectedBody: "\"id\":1,\"ownerId\":1,\"name\":\"Team picks\""},
+			{name: "AddAsset", method: "POST", url: "/users/1/collections/1/assets", payload: map[string]int{"assetId": 1}, expectedStatus: http.StatusOK, expectedBody: "\"assets\":[{\"userId\":1,\"assetId\":1}]"},
+			{name: "Share", method: "PUT", url: "/users/1/collections/1/members/2", payload: map[string]string{"role": "viewer"}, expectedStatus: http.StatusOK, expectedBody: "\"members\":{\"2\":\"viewer\"}"},
+			{name: "GetShared", method: "GET", url: "/users/2/shared", expectedStatus: http.StatusOK, expectedBody: "\"name\":\"Team picks\""},
+			{name: "GetAsViewer", method: "GET", url: "/users/2/collections/1", expectedStatus: http.StatusOK, expectedBody: "\"items\":[{\"userId\":1,\"asset\":{\"id\":1,\"type\":\"Insight\""},
+			{name: "AddAssetAsViewer", method: "POST", url: "/users/2/collections/1/assets", payload: map[string]int{"assetId": 1}, expectedStatus: http.StatusForbidden, expectedBody: "permission denied"},
+			{name: "GetAsStranger", method: "GET", url: "/users/3/collections/1", expectedStatus: http.StatusNotFound, expectedBody: "collection not found"},
+			{name: "Unshare", method: "DELETE", url: "/users/1/collections/1/members/2", expectedStatus: http.StatusNoContent},
+			{name: "GetSharedEmpty", method: "GET", url: "/users/2/shared", expectedStatus: http.StatusOK, expectedBody: "[]"},
+		},
+		"ShareWithEditor": {
+			{name: "Create", method: "POST", url: "/users/1/collections", payload: map[string]string{"name": "Team picks"}, expectedStatus: http.StatusCreated},
+			{name: "Share", method: "PUT", url: "/users/1/collections/1/members/2", payload: map[string]string{"role": "editor"}, expectedStatus: http.StatusOK},
+			{name: "AddAssetAsEditor", method: "POST", url: "/users/2/collections/1/assets", payload: map[string]int{"assetId": 2}, expectedStatus: http.StatusOK, expectedBody: "{\"userId\":2,\"assetId\":2}"},
+			{name: "AddDuplicateAsset", method: "POST", url: "/users/2/collections/1/assets", payload: map[string]int{"assetId": 2}, expectedStatus: http.StatusBadRequest, expectedBody: "asset already in collection"},
+			{name: "RemoveAsset", method: "DELETE", url: "/users/2/collections/1/assets/2/2", expectedStatus: http.StatusNoContent},
+			{name: "DeleteAsEditor", method: "DELETE", url: "/users/2/collections/1", expectedStatus: http.StatusForbidden, expectedBody: "permission denied"},
+			{name: "Delete", method: "DELETE", url: "/users/1/collections/1", expectedStatus: http.StatusNoContent},
+			{name: "GetUserCollectionsEmpty", method: "GET", url: "/users/1/collections", expectedStatus: http.StatusOK, expectedBody: "[]"},
+		},
+		"InvalidRequests": {
+			{name: "CreateWithoutName", method: "POST", url: "/users/1/collections", payload: map[string]string{}, expectedStatus: http.StatusBadRequest, expectedBody: "invalid collection name"},
+			{name: "CreateForUnknownUser", method: "POST", url: "/users/999999/collections", payload: map[string]string{"name": "Team picks"}, expectedStatus: http.StatusNotFound, expectedBody: "user not found"},
+			{name: "Create", method: "POST", url: "/users/1/collections", payload: map[string]string{"name": "Team picks"}, expectedStatus: http.StatusCreated},
+			{name: "ShareInvalidRole", method: "PUT", url: "/users/1/collections/1/members/2", payload: map[string]string{"role": "admin"}, expectedStatus: http.StatusBadRequest, expectedBody: "invalid role"},
+			{name: "ShareUnknownMember", method: "PUT", url: "/users/1/collections/1/members/999999", payload: map[string]string{"role": "viewer"}, expectedStatus: http.StatusNotFound, expectedBody: "member not found"},
+			{name: "AddUnknownAsset", method: "POST", url: "/users/1/collections/1/assets", payload: map[string]int{"assetId": 999999}, expectedStatus: http.StatusNotFound, expectedBody: "asset not found"},
+			{name: "GetUnknownCollection", method: "GET", url: "/users/1/collections/999999", expectedStatus: http.StatusNotFound, expectedBody: "collection not found"},
+		},
+	}
+
+	for name, steps := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			// setup the collectionService on the same users as the userService
+			userService := setup()
+			collectionService := service.NewCollectionService(userService.UserRepository, repository.NewInMemoryCollectionRepository())
+
+			// Create a new Router and register the routes for the CollectionHandler
+			r := mux.NewRouter()
+			handlers.NewCollectionHandler(collectionService).RegisterRoutes(r)
+
+			for _, tc := range steps {
+				t.Run(tc.name, func(t *testing.T) {
+					RunTestCase(t, r, tc)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+)
+
+// requestContext returns the context passed to the service for changes made by a request,
+// identifying the caller as the actor of the changes and the request that made them
+func requestContext(r *http.Request) context.Context {
+	ctx := service.WithActor(r.Context(), middleware.ClientKey(r))
+	if requestID, ok := middleware.RequestIDFromContext(r.Context()); ok {
+		ctx = service.WithRequestID(ctx, requestID)
+	}
+	return ctx
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DefaultHeartbeatInterval is how often an idle event stream sends a heartbeat comment by default
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// EventStreamHandler streams the changes of a user's favourites as Server-Sent Events
+type EventStreamHandler struct {
+	UserService       *service.UserService
+	EventStream       *service.EventStream
+	HeartbeatInterval time.Duration
+}
+
+// NewEventStreamHandler initializes and returns a new EventStreamHandler
+func NewEventStreamHandler(userService *service.UserService, eventStream *service.EventStream) *EventStreamHandler {
+	return &EventStreamHandler{
+		UserService:       userService,
+		EventStream:       eventStream,
+		HeartbeatInterval: DefaultHeartbeatInterval,
+	}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the event stream handler
+func (handler *EventStreamHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/users/{id}/favorites/events", handler.StreamUserFavoriteEvents).Methods(http.MethodGet)
+}
+
+// StreamUserFavoriteEvents sends every change of the user's favourites as a text/event-stream.
+// Clients resuming with the Last-Event-ID header first receive the events they missed that are still in the log.
+func (h *EventStreamHandler) StreamUserFavoriteEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.UserService.GetUserFavorites(r.Context(), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastEventID = id
+	}
+
+	missed, events, unsubscribe := h.EventStream.Subscribe(userID, lastEventID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The client fell behind and was dropped, so end the stream and let it resume
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a favourite event in the Server-Sent Events format
+func writeEvent(w http.ResponseWriter, event service.StreamedEvent) error {
+	data, err := json.Marshal(event.Event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event.Type, data)
+	return err
+}
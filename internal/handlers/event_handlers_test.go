@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupEventStream returns a server with the user and event stream routes, publishing the changes of userService
+func setupEventStream(t *testing.T, heartbeat time.Duration) (*service.UserService, *httptest.Server) {
+	userService := setup()
+	eventStream := service.NewEventStream(service.DefaultEventLogSize)
+	userService.AddListener(eventStream.Publish)
+
+	eventHandler := handlers.NewEventStreamHandler(userService, eventStream)
+	eventHandler.HeartbeatInterval = heartbeat
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(userService).RegisterRoutes(r)
+	eventHandler.RegisterRoutes(r)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return userService, server
+}
+
+// openStream connects to the event stream of a user and returns the response and a reader over its lines
+func openStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Scanner) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewScanner(resp.Body)
+}
+
+// readEvent reads the lines of the next event or comment from the stream
+func readEvent(lines *bufio.Scanner) []string {
+	var event []string
+	for lines.Scan() {
+		if lines.Text() == "" {
+			return event
+		}
+		event = append(event, lines.Text())
+	}
+	return event
+}
+
+func TestStreamUserFavoriteEvents(t *testing.T) {
+	userService, server := setupEventStream(t, time.Hour)
+
+	resp, lines := openStream(t, server.URL+"/users/1/favorites/events", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Test the changes made through the REST API are streamed
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/users/1/favorites/1", nil)
+	http.DefaultClient.Do(req)
+	userService.EditUserFavorite(context.Background(), 1, 2, &models.Chart{ID: 2, Type: models.ChartType, Title: "Edited"})
+
+	event := readEvent(lines)
+	assert.Equal(t, "id: 1", event[0])
+	assert.Equal(t, "event: favorite.deleted", event[1])
+	assert.True(t, strings.HasPrefix(event[2], "data: {\"type\":\"favorite.deleted\",\"userId\":1,\"assetId\":1,"))
+
+	event = readEvent(lines)
+	assert.Equal(t, "id: 2", event[0])
+	assert.Equal(t, "event: favorite.edited", event[1])
+
+	// Test resuming from the Last-Event-ID replays the missed events
+	_, resumed := openStream(t, server.URL+"/users/1/favorites/events", "1")
+	event = readEvent(resumed)
+	assert.Equal(t, "id: 2", event[0])
+}
+
+func TestStreamUserFavoriteEventsHeartbeat(t *testing.T) {
+	_, server := setupEventStream(t, 10*time.Millisecond)
+
+	_, lines := openStream(t, server.URL+"/users/1/favorites/events", "")
+	assert.Equal(t, []string{": heartbeat"}, readEvent(lines))
+}
+
+func TestStreamUserFavoriteEventsErrors(t *testing.T) {
+	_, server := setupEventStream(t, time.Hour)
+
+	// Test non-existing user
+	resp, _ := openStream(t, server.URL+"/users/999999/favorites/events", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Test invalid Last-Event-ID
+	resp, _ = openStream(t, server.URL+"/users/1/favorites/events", "abc")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// GraphQLHandler serves the GraphQL API
+type GraphQLHandler struct {
+	Schema *graphql.Schema
+}
+
+// NewGraphQLHandler initializes and returns a new GraphQLHandler
+func NewGraphQLHandler(schema *graphql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{
+		Schema: schema,
+	}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the GraphQL handler
+func (handler *GraphQLHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/graphql", handler.Query).Methods(http.MethodPost)
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query executes a GraphQL query or mutation.
+// Errors of the query itself are returned in the response body with status 200, as usual for GraphQL.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, h.Schema.Exec(requestContext(r), req.Query, req.OperationName, req.Variables))
+}
@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/gql"
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/gorilla/mux"
+)
+
+// TestGraphQLHandler tests the Query handler
+func TestGraphQLHandler(t *testing.T) {
+	graphQLTests := []TestCase{
+		{
+			name:           "Query",
+			method:         "POST",
+			url:            "/graphql",
+			payload:        map[string]interface{}{"query": "{ user(id: 1) { favorites(type: INSIGHT) { edges { node { ... on Insight { text } } } } } }"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"data\":{\"user\":{\"favorites\":{\"edges\":[{\"node\":{\"text\":\"Sample Insight Text\"}}]}}}}",
+		},
+		{
+			name:           "MutationWithVariables",
+			method:         "POST",
+			url:            "/graphql",
+			payload:        map[string]interface{}{"query": "mutation($id: ID!) { deleteFavorite(userId: $id, assetId: 1) }", "variables": map[string]interface{}{"id": "1"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"data\":{\"deleteFavorite\":true}}",
+		},
+		{
+			name:           "QueryError",
+			method:         "POST",
+			url:            "/graphql",
+			payload:        map[string]interface{}{"query": "{ user(id: 1) { name } }"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Cannot query field \\\"name\\\" on type \\\"User\\\".",
+		},
+		{
+			name:           "MissingQuery",
+			method:         "POST",
+			url:            "/graphql",
+			payload:        map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "missing query",
+		},
+	}
+
+	for _, tc := range graphQLTests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := gql.NewSchema(setup())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := mux.NewRouter()
+			handlers.NewGraphQLHandler(schema).RegisterRoutes(r)
+
+			RunTestCase(t, r, tc)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetUserFavoriteHistory returns every recorded version of a user's favourite
+func (h *UserHandler) GetUserFavoriteHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+
+	history, err := h.UserService.GetUserFavoriteHistory(r.Context(), userID, assetID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// RestoreUserFavoriteVersion brings a user's favourite back to a previous version
+func (h *UserHandler) RestoreUserFavoriteVersion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+	version, ok := pathID(w, r, "version")
+	if !ok {
+		return
+	}
+
+	c, ok := responseCodec(w, r, h.Codecs)
+	if !ok {
+		return
+	}
+
+	asset, err := h.UserService.RestoreUserFavoriteVersion(requestContext(r), userID, assetID, version)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "version not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case "cannot restore a deleted version", "edited asset type does not match existing asset type":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeEncoded(w, c, http.StatusOK, asset)
+}
+
+// GetUserTrash returns the deleted favourites of a user that can still be restored
+func (h *UserHandler) GetUserTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	trash, err := h.UserService.GetUserTrash(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trash)
+}
+
+// RestoreUserFavoriteFromTrash adds a deleted favourite back to the user's favourites
+func (h *UserHandler) RestoreUserFavoriteFromTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+
+	c, ok := responseCodec(w, r, h.Codecs)
+	if !ok {
+		return
+	}
+
+	asset, err := h.UserService.RestoreUserFavoriteFromTrash(requestContext(r), userID, assetID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "asset not in trash":
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case "asset already exists":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeEncoded(w, c, http.StatusOK, asset)
+}
+
+// DeleteUserFavoriteFromTrash permanently removes a deleted favourite from the user's trash
+func (h *UserHandler) DeleteUserFavoriteFromTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+
+	if err := h.UserService.DeleteUserFavoriteFromTrash(requestContext(r), userID, assetID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
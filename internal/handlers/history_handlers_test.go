@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// TestHistoryHandlers tests the history and trash handlers.
+// The steps of each scenario run in order against the same router, since they depend on the previous changes.
+func TestHistoryHandlers(t *testing.T) {
+	editedInsight := &models.Insight{
+		ID:          1,
+		Type:        models.InsightType,
+		Description: "Updated Insight",
+		Text:        "Updated Insight Text",
+	}
+
+	scenarios := map[string][]TestCase{
+		"EditAndRestoreVersion": {
+			{name: "Edit", method: "PUT", url: "/users/1/favorites/1", payload: editedInsight, expectedStatus: http.StatusOK},
+			{
+				name:           "GetHistory",
+				method:         "GET",
+				url:            "/users/1/favorites/1/history",
+				expectedStatus: http.StatusOK,
+				expectedBody:   "\"version\":1,\"userId\":1,\"assetId\":1,\"operation\":\"edit\",\"actor\":\"ip:",
+			},
+			{
+				name:           "RestoreVersion",
+				method:         "POST",
+				url:            "/users/1/favorites/1/history/1/restore",
+				expectedStatus: http.StatusOK,
+				expectedBody:   "\"text\":\"Updated Insight Text\"",
+			},
+			{
+				name:           "RestoreVersionNotFound",
+				method:         "POST",
+				url:            "/users/1/favorites/1/history/99/restore",
+				expectedStatus: http.StatusNotFound,
+				expectedBody:   "version not found",
+			},
+		},
+		"DeleteAndRestoreFromTrash": {
+			{name: "Delete", method: "DELETE", url: "/users/2/favorites/2", expectedStatus: http.StatusNoContent},
+			{
+				name:           "GetTrash",
+				method:         "GET",
+				url:            "/users/2/trash",
+				expectedStatus: http.StatusOK,
+				expectedBody:   "\"asset\":{\"id\":2,\"type\":\"Chart\"",
+			},
+			{
+				name:           "RestoreDeletedVersion",
+				method:         "POST",
+				url:            "/users/2/favorites/2/history/1/restore",
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   "cannot restore a deleted version",
+			},
+			{
+				name:           "RestoreFromTrash",
+				method:         "POST",
+				url:            "/users/2/trash/2/restore",
+				expectedStatus: http.StatusOK,
+				expectedBody:   "\"id\":2,\"type\":\"Chart\"",
+			},
+			{
+				name:           "RestoreFromTrashNotFound",
+				method:         "POST",
+				url:            "/users/2/trash/2/restore",
+				expectedStatus: http.StatusNotFound,
+				expectedBody:   "asset not in trash",
+			},
+		},
+		"DeleteFromTrash": {
+			{name: "Delete", method: "DELETE", url: "/users/3/favorites/3", expectedStatus: http.StatusNoContent},
+			{name: "DeleteFromTrash", method: "DELETE", url: "/users/3/trash/3", expectedStatus: http.StatusNoContent},
+			{name: "GetEmptyTrash", method: "GET", url: "/users/3/trash", expectedStatus: http.StatusOK, expectedBody: "[]"},
+		},
+		"UserNotFound": {
+			{name: "GetHistory", method: "GET", url: "/users/999999/favorites/1/history", expectedStatus: http.StatusNotFound, expectedBody: "user not found"},
+			{name: "GetTrash", method: "GET", url: "/users/999999/trash", expectedStatus: http.StatusNotFound, expectedBody: "user not found"},
+		},
+	}
+
+	for name, steps := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			// setup userService and userHandler
+			userService := setup()
+			userHandler := handlers.NewUserHandler(userService)
+
+			// Create a new Router and register the routes for the UserHandler
+			r := mux.NewRouter()
+			userHandler.RegisterRoutes(r)
+
+			for _, tc := range steps {
+				t.Run(tc.name, func(t *testing.T) {
+					RunTestCase(t, r, tc)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/codec"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// responseCodec returns the codec of the media type a request accepts, writing 406 Not Acceptable if there is none.
+// Handlers call it before making any change, so unacceptable requests have no effect.
+func responseCodec(w http.ResponseWriter, r *http.Request, codecs *codec.Registry) (codec.Codec, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	c, err := codecs.Negotiate(r.Header.Get("Accept"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return nil, false
+	}
+	return c, true
+}
+
+// writeEncoded writes a value encoded with a codec as the response
+func writeEncoded(w http.ResponseWriter, c codec.Codec, status int, v interface{}) {
+	data, err := c.Marshal(v)
+	if err != nil {
+		if err == codec.ErrUnsupportedValue {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", c.MediaType())
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// readAsset decodes the asset in the body of a request with the codec of its Content-Type,
+// writing the error response if it cannot. Requests without a Content-Type are rejected.
+func readAsset(w http.ResponseWriter, r *http.Request, codecs *codec.Registry) (models.Asset, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		http.Error(w, "no request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	c, err := codecs.ForContentType(contentType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	data, ok := readBody(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	asset, err := c.DecodeAsset(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return asset, true
+}
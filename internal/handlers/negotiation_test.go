@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/codec"
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/pb"
+	"github.com/gorilla/mux"
+)
+
+// serve sends a request with the given headers to a router of the UserHandler
+func serve(r *mux.Router, method, url string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, bytes.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestContentNegotiation(t *testing.T) {
+	newRouter := func() *mux.Router {
+		r := mux.NewRouter()
+		handlers.NewUserHandler(setup()).RegisterRoutes(r)
+		return r
+	}
+
+	t.Run("GetUserFavoritesMessagePack", func(t *testing.T) {
+		rr := serve(newRouter(), http.MethodGet, "/users/1/favorites", nil, map[string]string{"Accept": "application/msgpack"})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/msgpack", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Values("Vary"), "Accept")
+
+		var favorites map[int]map[string]interface{}
+		assert.NoError(t, msgpack.Unmarshal(rr.Body.Bytes(), &favorites))
+		assert.Len(t, favorites, 3)
+		assert.Equal(t, "Sample Chart Title", favorites[2]["title"])
+	})
+
+	t.Run("GetUserFavoritesProtobuf", func(t *testing.T) {
+		rr := serve(newRouter(), http.MethodGet, "/users/1/favorites", nil, map[string]string{"Accept": "application/x-protobuf"})
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var list pb.AssetList
+		assert.NoError(t, proto.Unmarshal(rr.Body.Bytes(), &list))
+		if assert.Len(t, list.GetAssets(), 3) {
+			assert.Equal(t, "Sample Insight Text", list.GetAssets()[0].GetInsight().GetText())
+			assert.Equal(t, "Sample Chart Title", list.GetAssets()[1].GetChart().GetTitle())
+		}
+	})
+
+	t.Run("AddUserFavoriteCBOR", func(t *testing.T) {
+		body, _ := cbor.Marshal(models.Insight{ID: 100, Type: models.InsightType, Description: "CBOR Insight", Text: "Text"})
+		rr := serve(newRouter(), http.MethodPost, "/users/1/favorites", body, map[string]string{
+			"Content-Type": "application/cbor",
+			"Accept":       "application/cbor",
+		})
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "application/cbor", rr.Header().Get("Content-Type"))
+
+		asset, err := codec.CBOR{}.DecodeAsset(rr.Body.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, &models.Insight{ID: 100, Type: models.InsightType, Description: "CBOR Insight", Text: "Text"}, asset)
+	})
+
+	t.Run("EditUserFavoriteProtobuf", func(t *testing.T) {
+		chart := models.Chart{ID: 2, Type: models.ChartType, Title: "Edited", DataPoints: []models.Point{{X: 1, Y: 1}}}
+		message, _ := pb.FromModel(chart)
+		body, _ := proto.Marshal(message)
+
+		rr := serve(newRouter(), http.MethodPut, "/users/1/favorites/2", body, map[string]string{"Content-Type": "application/x-protobuf"})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"title":"Edited"`)
+	})
+
+	t.Run("NotAcceptable", func(t *testing.T) {
+		r := newRouter()
+		body := []byte(`{"id": 100, "type": "Insight", "text": "Text"}`)
+		rr := serve(r, http.MethodPost, "/users/1/favorites", body, map[string]string{"Accept": "text/html", "Content-Type": "application/json"})
+		assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+
+		// The request was rejected before adding the asset
+		rr = serve(r, http.MethodPost, "/users/1/favorites", body, map[string]string{"Content-Type": "application/json"})
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+
+	t.Run("UnsupportedMediaType", func(t *testing.T) {
+		rr := serve(newRouter(), http.MethodPost, "/users/1/favorites", []byte("id=100"), map[string]string{"Content-Type": "text/plain"})
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("NonFiniteDataPoints", func(t *testing.T) {
+		nan, inf := float32(math.NaN()), float32(math.Inf(1))
+		chart := models.Chart{ID: 100, Type: models.ChartType, DataPoints: []models.Point{{X: 1, Y: nan}, {X: inf, Y: 1}}}
+		cborBody, _ := cbor.Marshal(chart)
+		msgpackBody, _ := codec.MessagePack{}.Marshal(chart)
+		message, _ := pb.FromModel(chart)
+		protobufBody, _ := proto.Marshal(message)
+
+		for contentType, body := range map[string][]byte{
+			"application/cbor":       cborBody,
+			"application/msgpack":    msgpackBody,
+			"application/x-protobuf": protobufBody,
+		} {
+			r := newRouter()
+			rr := serve(r, http.MethodPost, "/users/1/favorites", body, map[string]string{"Content-Type": contentType})
+			assert.Equal(t, http.StatusBadRequest, rr.Code, contentType)
+			assert.Contains(t, rr.Body.String(), "invalid data point", contentType)
+
+			// Test the listing is still encodable as JSON
+			rr = serve(r, http.MethodGet, "/users/1/favorites", nil, nil)
+			assert.Equal(t, http.StatusOK, rr.Code, contentType)
+		}
+	})
+
+	t.Run("InvalidMessagePackAsset", func(t *testing.T) {
+		body, _ := msgpack.Marshal(map[string]interface{}{"id": 100, "type": "Unknown"})
+		rr := serve(newRouter(), http.MethodPost, "/users/1/favorites", body, map[string]string{"Content-Type": "application/msgpack"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "invalid asset type")
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// ReferenceHandler serves the reference data used to validate asset fields
+type ReferenceHandler struct{}
+
+// NewReferenceHandler initializes and returns a new ReferenceHandler
+func NewReferenceHandler() *ReferenceHandler {
+	return &ReferenceHandler{}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the reference handler
+func (handler *ReferenceHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/reference/countries", handler.GetCountries).Methods(http.MethodGet)
+	r.HandleFunc("/reference/age-groups", handler.GetAgeGroups).Methods(http.MethodGet)
+	r.HandleFunc("/reference/genders", handler.GetGenders).Methods(http.MethodGet)
+}
+
+// GetCountries returns the allowed birth countries with their ISO 3166-1 alpha-2 codes
+func (h *ReferenceHandler) GetCountries(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, models.Countries)
+}
+
+// GetAgeGroups returns the age buckets the age group of an audience is derived from
+func (h *ReferenceHandler) GetAgeGroups(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, models.AgeGroups)
+}
+
+// GetGenders returns the allowed genders
+func (h *ReferenceHandler) GetGenders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, models.Genders)
+}
+
+// writeJSON writes a value as a JSON response with status 200
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,45 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/gorilla/mux"
+)
+
+// TestReferenceHandlers tests the GetCountries, GetAgeGroups and GetGenders handlers
+func TestReferenceHandlers(t *testing.T) {
+	referenceTests := []TestCase{
+		{
+			name:           "GetCountries",
+			method:         "GET",
+			url:            "/reference/countries",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"code\":\"GR\",\"name\":\"Greece\"}",
+		},
+		{
+			name:           "GetAgeGroups",
+			method:         "GET",
+			url:            "/reference/age-groups",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"name\":\"66+\",\"minAge\":66,\"maxAge\":null}",
+		},
+		{
+			name:           "GetGenders",
+			method:         "GET",
+			url:            "/reference/genders",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[\"Male\",\"Female\",\"Non-Binary\"]",
+		},
+	}
+
+	for _, tc := range referenceTests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := mux.NewRouter()
+			handlers.NewReferenceHandler().RegisterRoutes(r)
+
+			RunTestCase(t, r, tc)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/render"
+)
+
+// renderFunc is the signature shared by the SVG and PNG chart renderers
+type renderFunc func(w io.Writer, chart models.Chart, opts render.Options) error
+
+// RenderUserFavoriteSVG renders a user's favorite chart as an SVG image
+func (h *UserHandler) RenderUserFavoriteSVG(w http.ResponseWriter, r *http.Request) {
+	h.renderUserFavorite(w, r, "image/svg+xml", render.SVG)
+}
+
+// RenderUserFavoritePNG renders a user's favorite chart as a PNG image
+func (h *UserHandler) RenderUserFavoritePNG(w http.ResponseWriter, r *http.Request) {
+	h.renderUserFavorite(w, r, "image/png", render.PNG)
+}
+
+// renderUserFavorite looks up the chart and renders it with the given renderer.
+// The optional query parameters style, width and height control the output.
+func (h *UserHandler) renderUserFavorite(w http.ResponseWriter, r *http.Request, contentType string, renderer renderFunc) {
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
+
+	opts, err := parseRenderOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	asset, err := h.UserService.GetUserFavorite(r.Context(), userID, assetID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var chart models.Chart
+	switch a := asset.(type) {
+	case *models.Chart:
+		chart = *a
+	case models.Chart:
+		chart = a
+	default:
+		http.Error(w, "asset is not a chart", http.StatusBadRequest)
+		return
+	}
+
+	// Render into a buffer first, so a rendering error can still be reported with a proper status code
+	var buf bytes.Buffer
+	if err := renderer(&buf, chart, opts); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+// parseRenderOptions reads the render options from the query string, using the defaults for missing values
+func parseRenderOptions(r *http.Request) (render.Options, error) {
+	opts := render.DefaultOptions()
+	query := r.URL.Query()
+
+	style, err := render.ParseStyle(query.Get("style"))
+	if err != nil {
+		return opts, err
+	}
+	opts.Style = style
+
+	if v := query.Get("width"); v != "" {
+		if opts.Width, err = strconv.Atoi(v); err != nil {
+			return opts, errors.New("invalid chart size")
+		}
+	}
+	if v := query.Get("height"); v != "" {
+		if opts.Height, err = strconv.Atoi(v); err != nil {
+			return opts, errors.New("invalid chart size")
+		}
+	}
+
+	return opts, opts.Validate()
+}
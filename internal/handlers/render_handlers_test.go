@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/gorilla/mux"
+)
+
+// TestRenderHandlers tests the RenderUserFavoriteSVG and RenderUserFavoritePNG handlers
+func TestRenderHandlers(t *testing.T) {
+	renderUserFavoriteTests := []TestCase{
+		{
+			name:           "ValidRenderSVG",
+			method:         "GET",
+			url:            "/users/1/favorites/2/render.svg",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Sample Chart Title",
+		},
+		{
+			name:           "ValidRenderSVGBarStyle",
+			method:         "GET",
+			url:            "/users/1/favorites/2/render.svg?style=bar&width=800&height=600",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"800\" height=\"600\"",
+		},
+		{
+			name:           "ValidRenderPNG",
+			method:         "GET",
+			url:            "/users/1/favorites/2/render.png?style=scatter",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\x89PNG",
+		},
+		{
+			name:           "RenderAssetNotChart",
+			method:         "GET",
+			url:            "/users/1/favorites/1/render.svg",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "asset is not a chart",
+		},
+		{
+			name:           "RenderInvalidStyle",
+			method:         "GET",
+			url:            "/users/1/favorites/2/render.png?style=pie",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid chart style",
+		},
+		{
+			name:           "RenderInvalidSize",
+			method:         "GET",
+			url:            "/users/1/favorites/2/render.svg?width=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid chart size",
+		},
+		{
+			name:           "RenderAssetNotFound",
+			method:         "GET",
+			url:            "/users/1/favorites/999999/render.svg",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "asset not found",
+		},
+		{
+			name:           "RenderUserNotFound",
+			method:         "GET",
+			url:            "/users/999999/favorites/2/render.png",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found",
+		},
+	}
+
+	for _, tc := range renderUserFavoriteTests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup userService and userHandler
+			userService := setup()
+			userHandler := handlers.NewUserHandler(userService)
+
+			// Create a new Router and register the routes for the UserHandler
+			r := mux.NewRouter()
+			userHandler.RegisterRoutes(r)
+
+			RunTestCase(t, r, tc)
+		})
+	}
+}
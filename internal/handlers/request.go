@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// pathParamNames are the names used in the error responses for the path parameters holding IDs
+var pathParamNames = map[string]string{
+	"id":           "user id",
+	"assetID":      "asset id",
+	"version":      "version",
+	"collectionID": "collection id",
+	"memberID":     "member id",
+	"ownerID":      "owner id",
+}
+
+// pathID parses a path parameter holding an ID, writing 400 Bad Request if it is not a positive integer in its plain
+// decimal form, so "007", "+7" and "7e0" are rejected instead of naming another resource than the client meant
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value := mux.Vars(r)[name]
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 || strconv.Itoa(id) != value {
+		http.Error(w, "invalid "+pathParamNames[name], http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// readBody reads the body of a request, writing 413 Request Entity Too Large if it exceeds the limit
+// set by the LimitBody middleware
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		http.Error(w, "no request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBodyError(w, err)
+		return nil, false
+	}
+	return data, true
+}
+
+// decodeJSON decodes the JSON body of a request into v, writing 415 Unsupported Media Type
+// if the request is not sent as application/json
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		return false
+	}
+
+	data, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeBodyError writes the response for a request body that could not be read
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "Error reading request body", http.StatusInternalServerError)
+}
@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+func TestRequestBodyLimits(t *testing.T) {
+	newRouter := func() *mux.Router {
+		userService := setup()
+		r := mux.NewRouter()
+		handlers.NewUserHandler(userService).RegisterRoutes(r)
+		handlers.NewCollectionHandler(service.NewCollectionService(userService.UserRepository, repository.NewInMemoryCollectionRepository())).RegisterRoutes(r)
+		r.Use(middleware.LimitBody(256))
+		return r
+	}
+	insight := `{"id": 100, "type": "Insight", "text": "` + strings.Repeat("a", 300) + `"}`
+
+	t.Run("DeclaredTooLarge", func(t *testing.T) {
+		rr := serve(newRouter(), http.MethodPost, "/users/1/favorites", []byte(insight), map[string]string{"Content-Type": "application/json"})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("StreamedTooLarge", func(t *testing.T) {
+		// Without a Content-Length the limit is only hit while the handler reads the body
+		req := httptest.NewRequest(http.MethodPost, "/users/1/favorites", io.MultiReader(strings.NewReader(insight)))
+		req.ContentLength = -1
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		newRouter().ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Contains(t, rr.Body.String(), "request body too large")
+	})
+
+	t.Run("StreamedTooLargeJSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users/1/collections", io.MultiReader(strings.NewReader(`{"name": "`+strings.Repeat("a", 300)+`"}`)))
+		req.ContentLength = -1
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		newRouter().ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("UnderLimit", func(t *testing.T) {
+		rr := serve(newRouter(), http.MethodPost, "/users/1/favorites", []byte(`{"id": 100, "type": "Insight", "text": "Text"}`), map[string]string{"Content-Type": "application/json"})
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+}
+
+func TestRequestContentType(t *testing.T) {
+	newRouter := func() *mux.Router {
+		userService := setup()
+		r := mux.NewRouter()
+		handlers.NewUserHandler(userService).RegisterRoutes(r)
+		handlers.NewCollectionHandler(service.NewCollectionService(userService.UserRepository, repository.NewInMemoryCollectionRepository())).RegisterRoutes(r)
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		body        string
+		contentType string
+		expected    int
+	}{
+		{"AssetWithoutContentType", http.MethodPost, "/users/1/favorites", `{"id": 100, "type": "Insight"}`, "", http.StatusUnsupportedMediaType},
+		{"EditWithoutContentType", http.MethodPut, "/users/1/favorites/1", `{"id": 1, "type": "Insight"}`, "", http.StatusUnsupportedMediaType},
+		{"JSONWithoutContentType", http.MethodPost, "/users/1/collections", `{"name": "Team picks"}`, "", http.StatusUnsupportedMediaType},
+		{"JSONAsText", http.MethodPost, "/users/1/collections", `{"name": "Team picks"}`, "text/plain", http.StatusUnsupportedMediaType},
+		{"JSONWithCharset", http.MethodPost, "/users/1/collections", `{"name": "Team picks"}`, "application/json; charset=utf-8", http.StatusCreated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tc.contentType != "" {
+				headers["Content-Type"] = tc.contentType
+			}
+			rr := serve(newRouter(), tc.method, tc.url, []byte(tc.body), headers)
+			assert.Equal(t, tc.expected, rr.Code)
+		})
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	userService := setup()
+	r := mux.NewRouter()
+	handlers.NewUserHandler(userService).RegisterRoutes(r)
+	handlers.NewCollectionHandler(service.NewCollectionService(userService.UserRepository, repository.NewInMemoryCollectionRepository())).RegisterRoutes(r)
+
+	tests := []struct {
+		method   string
+		url      string
+		expected string
+	}{
+		{http.MethodGet, "/users/0/favorites", "invalid user id"},
+		{http.MethodGet, "/users/+1/favorites", "invalid user id"},
+		{http.MethodGet, "/users/99999999999999999999/favorites", "invalid user id"},
+		{http.MethodPut, "/users/1/favorites/x", "invalid asset id"},
+		{http.MethodGet, "/users/1/favorites/1/history", ""},
+		{http.MethodPost, "/users/1/favorites/1/history/1e0/restore", "invalid version"},
+		{http.MethodGet, "/users/1/favorites/2/render.svg", ""},
+		{http.MethodGet, "/users/1/favorites/two/render.svg", "invalid asset id"},
+		{http.MethodGet, "/users/1/collections/abc", "invalid collection id"},
+		{http.MethodDelete, "/users/1/collections/1/members/me", "invalid member id"},
+		{http.MethodDelete, "/users/1/collections/1/assets/1/00", "invalid asset id"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			rr := serve(r, tc.method, tc.url, nil, nil)
+			if tc.expected == "" {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, tc.expected+"\n", rr.Body.String())
+		})
+	}
+}
+
+func TestAddUserFavoriteIdempotent(t *testing.T) {
+	r := mux.NewRouter()
+	handlers.NewUserHandler(setup()).RegisterRoutes(r)
+	r.Use(middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour).Middleware)
+
+	body := []byte(`{"id": 100, "type": "Insight", "text": "Text"}`)
+	headers := map[string]string{"Content-Type": "application/json", "Idempotency-Key": "add-100"}
+
+	// Test a retry gets the response of the first request instead of "asset already exists"
+	first := serve(r, http.MethodPost, "/users/1/favorites", body, headers)
+	retry := serve(r, http.MethodPost, "/users/1/favorites", body, headers)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	// Test a genuine conflict without a key is still reported
+	rr := serve(r, http.MethodPost, "/users/1/favorites", body, map[string]string{"Content-Type": "application/json"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "asset already exists")
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// DefaultStreamThreshold is how many favourites a listing must exceed to be streamed by default
+const DefaultStreamThreshold = 1000
+
+const (
+	// streamChunkSize is how many bytes of a streamed listing are encoded before being written
+	streamChunkSize = 32 * 1024
+	// streamFlushInterval is how many assets of a streamed listing are written between flushes
+	streamFlushInterval = 500
+)
+
+// streamFavorites writes favourites as the same JSON object keyed by asset ID as encoding them at once,
+// but encodes them one at a time in ascending ID order and flushes periodically,
+// so only a small chunk of the encoded response is held in memory besides the favourites and their sorted IDs
+func streamFavorites(w http.ResponseWriter, favorites map[int]models.Asset) {
+	ids := make([]int, 0, len(favorites))
+	for id := range favorites {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	var buf bytes.Buffer
+	buf.Grow(streamChunkSize)
+	encoder := json.NewEncoder(&buf)
+	var key [24]byte
+
+	buf.WriteByte('{')
+	for i, id := range ids {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('"')
+		buf.Write(strconv.AppendInt(key[:0], int64(id), 10))
+		buf.WriteString(`":`)
+		if err := encoder.Encode(favorites[id]); err != nil {
+			// The status was already sent, so the client is left with a truncated body
+			return
+		}
+
+		if buf.Len() >= streamChunkSize || (i+1)%streamFlushInterval == 0 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return
+			}
+			buf.Reset()
+		}
+		if (i+1)%streamFlushInterval == 0 && flusher != nil {
+			flusher.Flush()
+		}
+	}
+	buf.WriteString("}\n")
+	w.Write(buf.Bytes())
+}
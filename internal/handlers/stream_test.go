@@ -0,0 +1,46 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+func TestStreamUserFavorites(t *testing.T) {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(1, 1200)
+	userHandler := handlers.NewUserHandler(service.NewUserService(repo))
+
+	get := func(threshold int) *httptest.ResponseRecorder {
+		userHandler.StreamThreshold = threshold
+		r := mux.NewRouter()
+		userHandler.RegisterRoutes(r)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/favorites", nil))
+		return rr
+	}
+
+	buffered := get(handlers.DefaultStreamThreshold * 10)
+	streamed := get(handlers.DefaultStreamThreshold)
+
+	assert.Equal(t, http.StatusOK, streamed.Code)
+	assert.Equal(t, "application/json", streamed.Header().Get("Content-Type"))
+	assert.True(t, streamed.Flushed)
+	assert.False(t, buffered.Flushed)
+
+	// Both responses hold the same JSON object, only the order of the keys differs
+	assert.JSONEq(t, buffered.Body.String(), streamed.Body.String())
+
+	var favorites map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(streamed.Body.Bytes(), &favorites))
+	assert.Len(t, favorites, 1200)
+}
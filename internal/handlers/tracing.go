@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+// traced wraps a handler in a span named after it, recording the IDs in the path and the status of the response
+func traced(name string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		var attrs []attribute.KeyValue
+		if userID, err := strconv.Atoi(vars["id"]); err == nil {
+			attrs = append(attrs, telemetry.UserIDKey.Int(userID))
+		}
+		if assetID, err := strconv.Atoi(vars["assetID"]); err == nil {
+			attrs = append(attrs, telemetry.AssetIDKey.Int(assetID))
+		}
+
+		ctx, span := telemetry.Start(r.Context(), name, attrs...)
+		defer span.End()
+
+		sw := &middleware.StatusWriter{ResponseWriter: w}
+		handler(sw, r.WithContext(ctx))
+
+		status := sw.StatusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status), telemetry.OutcomeKey.String(outcome(status)))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// outcome returns the outcome recorded on the span of a request answered with the status
+func outcome(status int) string {
+	if status >= http.StatusBadRequest {
+		return "error"
+	}
+	return "ok"
+}
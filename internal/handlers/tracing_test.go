@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+// recordSpans installs a TracerProvider recording the ended spans for the duration of a test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	return recorder
+}
+
+// spanAttributes returns the attributes of a span as a map
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracing(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(setup()).RegisterRoutes(r)
+	r.Use(middleware.Tracing)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	rr := serve(r, http.MethodPost, "/users/1/favorites", []byte(`{"id": 100, "type": "Insight", "text": "Text"}`), map[string]string{
+		"Content-Type": "application/json",
+		"traceparent":  "00-" + traceID + "-00f067aa0ba902b7-01",
+	})
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// The spans end from the innermost layer to the router
+	spans := recorder.Ended()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	}
+	assert.Equal(t, []string{
+		"InMemoryUserRepository.AddUserFavorite",
+		"UserService.AddUserFavorite",
+		"UserHandler.AddUserFavorite",
+		"POST /users/{id}/favorites",
+	}, names)
+
+	// Every span is a child of the next layer, and the router span of the caller's span
+	for i := 0; i < len(spans)-1; i++ {
+		assert.Equal(t, spans[i+1].SpanContext().SpanID(), spans[i].Parent().SpanID())
+	}
+	assert.Equal(t, "00f067aa0ba902b7", spans[3].Parent().SpanID().String())
+	assert.True(t, spans[3].Parent().IsRemote())
+
+	router := spanAttributes(spans[3])
+	assert.Equal(t, "/users/{id}/favorites", router["http.route"].AsString())
+	assert.Equal(t, int64(201), router["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(1), router["user.id"].AsInt64())
+
+	service := spanAttributes(spans[1])
+	assert.Equal(t, "Insight", service["asset.type"].AsString())
+	assert.Equal(t, int64(100), service["asset.id"].AsInt64())
+	assert.Equal(t, "ok", service["outcome"].AsString())
+}
+
+func TestTracingErrors(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := mux.NewRouter()
+	handlers.NewUserHandler(setup()).RegisterRoutes(r)
+	r.Use(middleware.Tracing)
+
+	rr := serve(r, http.MethodDelete, "/users/1/favorites/999", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	// The service reports its error, while the HTTP spans only fail on server errors
+	service := spans["UserService.DeleteUserFavorite"]
+	if assert.NotNil(t, service) {
+		assert.Equal(t, "error", spanAttributes(service)["outcome"].AsString())
+		assert.Equal(t, codes.Error, service.Status().Code)
+		assert.Equal(t, "asset not found", service.Status().Description)
+	}
+	for _, name := range []string{"UserHandler.DeleteUserFavorite", "DELETE /users/{id}/favorites/{assetID}"} {
+		span := spans[name]
+		if assert.NotNil(t, span, name) {
+			assert.Equal(t, "error", spanAttributes(span)["outcome"].AsString())
+			assert.Equal(t, int64(404), spanAttributes(span)["http.response.status_code"].AsInt64())
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		}
+	}
+}
@@ -2,73 +2,102 @@ package handlers
 
 import (
 	"encoding/json"
-	"io"
 	"net/http"
-	"strconv"
 
+	"github.com/ceciivanov/platform-go-challenge/internal/codec"
 	"github.com/ceciivanov/platform-go-challenge/internal/service"
-	"github.com/ceciivanov/platform-go-challenge/internal/utils"
 	"github.com/gorilla/mux"
 )
 
 // Handler struct
 type UserHandler struct {
-	UserService *service.UserService
+	UserService     *service.UserService
+	Codecs          *codec.Registry // Codecs encode the favourites in the media types negotiated with the client
+	StreamThreshold int             // JSON listings of more favourites are streamed one asset at a time
 }
 
 // NewUserHandler initializes and returns a new Handler
 func NewUserHandler(userService *service.UserService) *UserHandler {
 	return &UserHandler{
-		UserService: userService,
+		UserService:     userService,
+		Codecs:          codec.NewDefaultRegistry(),
+		StreamThreshold: DefaultStreamThreshold,
 	}
 }
 
 // RegisterRoutes registers the routes (endpoints) for the user handler
 func (handler *UserHandler) RegisterRoutes(r *mux.Router) {
-	r.HandleFunc("/users/{id}/favorites", handler.GetUserFavorites).Methods(http.MethodGet)
-	r.HandleFunc("/users/{id}/favorites", handler.AddUserFavorite).Methods(http.MethodPost)
-	r.HandleFunc("/users/{id}/favorites/{assetID}", handler.DeleteUserFavorite).Methods(http.MethodDelete)
-	r.HandleFunc("/users/{id}/favorites/{assetID}", handler.EditUserFavorite).Methods(http.MethodPut)
+	r.HandleFunc("/users", traced("UserHandler.GetUsers", handler.GetUsers)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites", traced("UserHandler.GetUserFavorites", handler.GetUserFavorites)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites", traced("UserHandler.AddUserFavorite", handler.AddUserFavorite)).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/favorites/{assetID}", traced("UserHandler.DeleteUserFavorite", handler.DeleteUserFavorite)).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{id}/favorites/{assetID}", traced("UserHandler.EditUserFavorite", handler.EditUserFavorite)).Methods(http.MethodPut)
+	r.HandleFunc("/users/{id}/favorites/{assetID}/render.svg", traced("UserHandler.RenderUserFavoriteSVG", handler.RenderUserFavoriteSVG)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites/{assetID}/render.png", traced("UserHandler.RenderUserFavoritePNG", handler.RenderUserFavoritePNG)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites/{assetID}/history", traced("UserHandler.GetUserFavoriteHistory", handler.GetUserFavoriteHistory)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites/{assetID}/history/{version}/restore", traced("UserHandler.RestoreUserFavoriteVersion", handler.RestoreUserFavoriteVersion)).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/trash", traced("UserHandler.GetUserTrash", handler.GetUserTrash)).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/trash/{assetID}/restore", traced("UserHandler.RestoreUserFavoriteFromTrash", handler.RestoreUserFavoriteFromTrash)).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/trash/{assetID}", traced("UserHandler.DeleteUserFavoriteFromTrash", handler.DeleteUserFavoriteFromTrash)).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{id}/audiences/aggregate", traced("UserHandler.AggregateUserAudiences", handler.AggregateUserAudiences)).Methods(http.MethodGet)
+	r.HandleFunc("/audiences/aggregate", traced("UserHandler.AggregateAudiences", handler.AggregateAudiences)).Methods(http.MethodGet)
+}
+
+// GetUsers returns the IDs of all users in ascending order
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	ids, err := h.UserService.GetUserIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ids)
 }
 
 // GetUserFavorites returns a map of user's favorite assets
 func (h *UserHandler) GetUserFavorites(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID, _ := strconv.Atoi(vars["id"])
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
 
-	favorites, err := h.UserService.GetUserFavorites(userID)
+	c, ok := responseCodec(w, r, h.Codecs)
+	if !ok {
+		return
+	}
+
+	favorites, err := h.UserService.GetUserFavorites(r.Context(), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(favorites)
+	if _, ok := c.(codec.JSON); ok && len(favorites) > h.StreamThreshold {
+		streamFavorites(w, favorites)
+		return
+	}
+	writeEncoded(w, c, http.StatusOK, favorites)
 }
 
 // AddUserFavorite adds an asset to the user's favorites
 func (h *UserHandler) AddUserFavorite(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID, _ := strconv.Atoi(vars["id"])
-
-	if r.Body == nil {
-		http.Error(w, "no request body", http.StatusBadRequest)
+	userID, ok := pathID(w, r, "id")
+	if !ok {
 		return
 	}
 
-	newAssetData, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+	c, ok := responseCodec(w, r, h.Codecs)
+	if !ok {
 		return
 	}
 
-	newAsset, err := utils.DecodeAsset(newAssetData)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	newAsset, ok := readAsset(w, r, h.Codecs)
+	if !ok {
 		return
 	}
 
-	err = h.UserService.AddUserFavorite(userID, newAsset)
+	err := h.UserService.AddUserFavorite(requestContext(r), userID, newAsset)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
@@ -77,20 +106,31 @@ func (h *UserHandler) AddUserFavorite(w http.ResponseWriter, r *http.Request) {
 		case "asset already exists":
 			http.Error(w, "asset already exists", http.StatusBadRequest)
 			return
+		case "invalid gender", "invalid birth country",
+			"invalid asset id", "too many data points", "invalid data point", "description too long", "title too long", "x axes title too long", "y axes title too long", "text too long":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(newAsset)
+	writeEncoded(w, c, http.StatusCreated, newAsset)
 }
 
 // DeleteUserFavorite deletes an asset from the user's favorites
 func (h *UserHandler) DeleteUserFavorite(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID, _ := strconv.Atoi(vars["id"])
-	assetID, _ := strconv.Atoi(vars["assetID"])
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
 
-	err := h.UserService.DeleteUserFavorite(userID, assetID)
+	err := h.UserService.DeleteUserFavorite(requestContext(r), userID, assetID)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
@@ -99,6 +139,9 @@ func (h *UserHandler) DeleteUserFavorite(w http.ResponseWriter, r *http.Request)
 		case "asset not found":
 			http.Error(w, "asset not found", http.StatusNotFound)
 			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
 
@@ -107,23 +150,26 @@ func (h *UserHandler) DeleteUserFavorite(w http.ResponseWriter, r *http.Request)
 
 // EditUserFavorite edits an asset in the user's favorites
 func (h *UserHandler) EditUserFavorite(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID, _ := strconv.Atoi(vars["id"])
-	assetID, _ := strconv.Atoi(vars["assetID"])
+	userID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	assetID, ok := pathID(w, r, "assetID")
+	if !ok {
+		return
+	}
 
-	updatedAssetData, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+	c, ok := responseCodec(w, r, h.Codecs)
+	if !ok {
 		return
 	}
 
-	updatedAsset, err := utils.DecodeAsset(updatedAssetData)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	updatedAsset, ok := readAsset(w, r, h.Codecs)
+	if !ok {
 		return
 	}
 
-	err = h.UserService.EditUserFavorite(userID, assetID, updatedAsset)
+	err := h.UserService.EditUserFavorite(requestContext(r), userID, assetID, updatedAsset)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
@@ -138,8 +184,14 @@ func (h *UserHandler) EditUserFavorite(w http.ResponseWriter, r *http.Request) {
 		case "edited asset type does not match existing asset type":
 			http.Error(w, "edited asset type does not match existing asset type", http.StatusBadRequest)
 			return
+		case "invalid gender", "invalid birth country",
+			"invalid asset id", "too many data points", "invalid data point", "description too long", "title too long", "x axes title too long", "y axes title too long", "text too long":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(updatedAsset)
+	writeEncoded(w, c, http.StatusOK, updatedAsset)
 }
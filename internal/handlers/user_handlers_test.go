@@ -177,6 +177,13 @@ func RunTestCase(t *testing.T, r *mux.Router, tc TestCase) {
 // TestHandlers tests the GetUserFavorites, AddUserFavorite, DeleteUserFavorite, and EditUserFavorite handlers
 func TestHandlers(t *testing.T) {
 	getUserFavoritesTests := []TestCase{
+		{
+			name:           "ValidUsers",
+			method:         "GET",
+			url:            "/users",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[1,2,3]",
+		},
 		{
 			name:           "ValidUserFavorites",
 			method:         "GET",
@@ -190,6 +197,20 @@ func TestHandlers(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "user not found",
 		},
+		{
+			name:           "InvalidUserID",
+			method:         "GET",
+			url:            "/users/abc/favorites",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid user id",
+		},
+		{
+			name:           "NonCanonicalUserID",
+			method:         "GET",
+			url:            "/users/01/favorites",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid user id",
+		},
 	}
 
 	t.Run("GetUserFavorites", func(t *testing.T) {
@@ -257,7 +278,23 @@ func TestHandlers(t *testing.T) {
 				NumberOfPurchases: 8,
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   "{\"id\":300,\"type\":\"Audience\",\"description\":\"Sample Audience for testing to add as favorite\",\"age\":15,\"ageGroup\":\"10-20\",\"gender\":\"Male\",\"birthCountry\":\"USA\",\"hoursSpentOnMedia\":26,\"numberOfPurchases\":8}",
+			// The age group is derived from the age and the country alias is normalised to its ISO code
+			expectedBody: "{\"id\":300,\"type\":\"Audience\",\"description\":\"Sample Audience for testing to add as favorite\",\"age\":15,\"ageGroup\":\"0-17\",\"gender\":\"Male\",\"birthCountry\":\"US\",\"hoursSpentOnMedia\":26,\"numberOfPurchases\":8}",
+		},
+		{
+			name:   "AddUserFavoriteInvalidBirthCountry",
+			method: "POST",
+			url:    "/users/3/favorites",
+			payload: &models.Audience{
+				ID:           301,
+				Type:         models.AudienceType,
+				Description:  "Sample Audience with an unknown country",
+				Age:          15,
+				Gender:       "Male",
+				BirthCountry: "Atlantis",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid birth country",
 		},
 		{
 			name:   "AddUserFavoriteAssetExists",
@@ -306,6 +343,39 @@ func TestHandlers(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			// Skip checking the response body as it is not predictable
 		},
+		{
+			name:   "AddUserFavoriteTooManyDataPoints",
+			method: "POST",
+			url:    "/users/1/favorites",
+			payload: &models.Chart{
+				ID:         600,
+				Type:       models.ChartType,
+				Title:      "Too large chart",
+				DataPoints: make([]models.Point, service.DefaultAssetLimits.MaxDataPoints+1),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "too many data points",
+		},
+		{
+			name:   "AddUserFavoriteTextTooLong",
+			method: "POST",
+			url:    "/users/1/favorites",
+			payload: &models.Insight{
+				ID:   700,
+				Type: models.InsightType,
+				Text: strings.Repeat("a", service.DefaultAssetLimits.MaxTextLength+1),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "text too long",
+		},
+		{
+			name:           "AddUserFavoriteInvalidID",
+			method:         "POST",
+			url:            "/users/1/favorites",
+			payload:        &models.Insight{ID: 0, Type: models.InsightType, Text: "Unreachable"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid asset id",
+		},
 		{
 			name:           "AddUserFavoriteNoPayload",
 			method:         "POST",
@@ -353,6 +423,13 @@ func TestHandlers(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "user not found",
 		},
+		{
+			name:           "DeleteUserFavoriteInvalidAssetID",
+			method:         "DELETE",
+			url:            "/users/1/favorites/-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid asset id",
+		},
 	}
 
 	t.Run("DeleteUserFavorite", func(t *testing.T) {
@@ -420,7 +497,23 @@ func TestHandlers(t *testing.T) {
 				NumberOfPurchases: 20, // update the number of purchases
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "{\"id\":3,\"type\":\"Audience\",\"description\":\"Updated Audience\",\"age\":15,\"ageGroup\":\"10-20\",\"gender\":\"Male\",\"birthCountry\":\"USA\",\"hoursSpentOnMedia\":40,\"numberOfPurchases\":20}",
+			// The age group is derived from the age and the country alias is normalised to its ISO code
+			expectedBody: "{\"id\":3,\"type\":\"Audience\",\"description\":\"Updated Audience\",\"age\":15,\"ageGroup\":\"0-17\",\"gender\":\"Male\",\"birthCountry\":\"US\",\"hoursSpentOnMedia\":40,\"numberOfPurchases\":20}",
+		},
+		{
+			name:   "EditUserFavoriteInvalidGender",
+			method: "PUT",
+			url:    "/users/3/favorites/3",
+			payload: &models.Audience{
+				ID:           3,
+				Type:         models.AudienceType,
+				Description:  "Updated Audience",
+				Age:          15,
+				Gender:       "Unknown",
+				BirthCountry: "USA",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid gender",
 		},
 		{
 			name:   "EditUserFavoriteNoIDMatch",
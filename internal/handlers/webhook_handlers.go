@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// WebhookHandler manages the webhook subscriptions and exposes their deliveries to the holders of one of its keys
+type WebhookHandler struct {
+	WebhookService *service.WebhookService
+	Keys           map[string]string // API keys by the name of their holder
+}
+
+// NewWebhookHandler initializes and returns a new WebhookHandler accepting the given API keys
+func NewWebhookHandler(webhookService *service.WebhookService, keys map[string]string) *WebhookHandler {
+	return &WebhookHandler{
+		WebhookService: webhookService,
+		Keys:           keys,
+	}
+}
+
+// RegisterRoutes registers the routes (endpoints) for the webhook handler under /webhooks, behind the API keys,
+// since the subscriptions receive the favourites of every user
+func (handler *WebhookHandler) RegisterRoutes(r *mux.Router) {
+	webhooks := r.PathPrefix("/webhooks").Subrouter()
+	webhooks.Use(middleware.RequireAPIKeys(handler.Keys))
+	webhooks.HandleFunc("", handler.GetSubscriptions).Methods(http.MethodGet)
+	webhooks.HandleFunc("", handler.Subscribe).Methods(http.MethodPost)
+	webhooks.HandleFunc("/dead-letters", handler.GetDeadLetters).Methods(http.MethodGet)
+	webhooks.HandleFunc("/{id}", handler.DeleteSubscription).Methods(http.MethodDelete)
+	webhooks.HandleFunc("/{id}/deliveries", handler.GetDeliveries).Methods(http.MethodGet)
+}
+
+// subscribeRequest is the body of a new webhook subscription
+type subscribeRequest struct {
+	URL    string             `json:"url"`
+	Secret string             `json:"secret"`
+	Events []models.EventType `json:"events"`
+}
+
+// subscribeResponse is the created subscription, the only response that includes its secret
+type subscribeResponse struct {
+	models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// Subscribe creates a webhook subscription
+func (h *WebhookHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var body subscribeRequest
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	subscription, err := h.WebhookService.Subscribe(body.URL, body.Secret, body.Events)
+	if err != nil {
+		switch err.Error() {
+		case "invalid webhook url", "invalid event type", "webhook url not allowed", "webhook host cannot be resolved":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscribeResponse{WebhookSubscription: subscription, Secret: subscription.Secret})
+}
+
+// GetSubscriptions returns every webhook subscription
+func (h *WebhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.WebhookService.GetSubscriptions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, subscriptions)
+}
+
+// DeleteSubscription removes a webhook subscription
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.WebhookService.DeleteSubscription(mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries returns the delivery log of a webhook subscription
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.WebhookService.GetDeliveries(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, deliveries)
+}
+
+// GetDeadLetters returns the deliveries that failed all their attempts
+func (h *WebhookHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := h.WebhookService.GetDeadLetters()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, deadLetters)
+}
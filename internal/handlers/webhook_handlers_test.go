@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/handlers"
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// newWebhookRouter returns a router serving the webhook API, sending the webhook key with every request
+func newWebhookRouter(webhookService *service.WebhookService) *mux.Router {
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set(middleware.APIKeyHeader, "webhook-secret")
+			next.ServeHTTP(w, r)
+		})
+	})
+	handlers.NewWebhookHandler(webhookService, map[string]string{"ops": "webhook-secret"}).RegisterRoutes(r)
+	return r
+}
+
+// TestWebhookHandlers tests the Subscribe, GetSubscriptions, DeleteSubscription, GetDeliveries and GetDeadLetters handlers
+func TestWebhookHandlers(t *testing.T) {
+	webhookTests := []TestCase{
+		{
+			name:           "Subscribe",
+			method:         "POST",
+			url:            "/webhooks",
+			payload:        map[string]interface{}{"url": "https://203.0.113.10/hook", "secret": "s3cret", "events": []string{"favorite.added"}},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "\"url\":\"https://203.0.113.10/hook\",\"events\":[\"favorite.added\"]",
+		},
+		{
+			name:           "SubscribeReturnsSecret",
+			method:         "POST",
+			url:            "/webhooks",
+			payload:        map[string]interface{}{"url": "https://203.0.113.10/hook", "secret": "s3cret"},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "\"secret\":\"s3cret\"",
+		},
+		{
+			name:           "SubscribeInvalidURL",
+			method:         "POST",
+			url:            "/webhooks",
+			payload:        map[string]interface{}{"url": "localhost"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid webhook url",
+		},
+		{
+			name:           "SubscribeInternalURL",
+			method:         "POST",
+			url:            "/webhooks",
+			payload:        map[string]interface{}{"url": "http://169.254.169.254/latest/meta-data"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "webhook url not allowed",
+		},
+		{
+			name:           "SubscribeInvalidEvent",
+			method:         "POST",
+			url:            "/webhooks",
+			payload:        map[string]interface{}{"url": "https://203.0.113.10/hook", "events": []string{"favorite.viewed"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid event type",
+		},
+		{
+			name:           "GetSubscriptions",
+			method:         "GET",
+			url:            "/webhooks",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[]",
+		},
+		{
+			name:           "GetDeadLetters",
+			method:         "GET",
+			url:            "/webhooks/dead-letters",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[]",
+		},
+		{
+			name:           "GetDeliveriesNotFound",
+			method:         "GET",
+			url:            "/webhooks/unknown/deliveries",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "subscription not found",
+		},
+		{
+			name:           "DeleteSubscriptionNotFound",
+			method:         "DELETE",
+			url:            "/webhooks/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "subscription not found",
+		},
+	}
+
+	for _, tc := range webhookTests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newWebhookRouter(service.NewWebhookService(repository.NewInMemoryWebhookRepository()))
+
+			RunTestCase(t, r, tc)
+		})
+	}
+}
+
+// TestWebhookSubscriptionLifecycle tests the handlers of an existing subscription
+func TestWebhookSubscriptionLifecycle(t *testing.T) {
+	webhookService := service.NewWebhookService(repository.NewInMemoryWebhookRepository())
+	subscription, err := webhookService.Subscribe("https://203.0.113.10/hook", "s3cret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newWebhookRouter(webhookService)
+
+	steps := []TestCase{
+		{name: "GetSubscriptionsHidesSecret", method: "GET", url: "/webhooks", expectedStatus: http.StatusOK, expectedBody: "\"id\":\"" + subscription.ID + "\",\"url\":\"https://203.0.113.10/hook\",\"events\":[],"},
+		{name: "GetDeliveries", method: "GET", url: "/webhooks/" + subscription.ID + "/deliveries", expectedStatus: http.StatusOK, expectedBody: "[]"},
+		{name: "DeleteSubscription", method: "DELETE", url: "/webhooks/" + subscription.ID, expectedStatus: http.StatusNoContent},
+		{name: "GetSubscriptionsEmpty", method: "GET", url: "/webhooks", expectedStatus: http.StatusOK, expectedBody: "[]"},
+	}
+	for _, tc := range steps {
+		t.Run(tc.name, func(t *testing.T) {
+			RunTestCase(t, r, tc)
+		})
+	}
+}
+
+func TestWebhookHandlersRequireKey(t *testing.T) {
+	webhookService := service.NewWebhookService(repository.NewInMemoryWebhookRepository())
+	subscription, _ := webhookService.Subscribe("https://203.0.113.10/hook", "s3cret", nil)
+	r := mux.NewRouter()
+	handlers.NewWebhookHandler(webhookService, map[string]string{"ops": "webhook-secret"}).RegisterRoutes(r)
+
+	for _, tc := range []TestCase{
+		{name: "Subscribe", method: "POST", url: "/webhooks", payload: map[string]interface{}{"url": "https://203.0.113.11/hook"}, expectedStatus: http.StatusUnauthorized},
+		{name: "GetSubscriptions", method: "GET", url: "/webhooks", expectedStatus: http.StatusUnauthorized},
+		{name: "GetDeliveries", method: "GET", url: "/webhooks/" + subscription.ID + "/deliveries", expectedStatus: http.StatusUnauthorized},
+		{name: "GetDeadLetters", method: "GET", url: "/webhooks/dead-letters", expectedStatus: http.StatusUnauthorized},
+		{name: "DeleteSubscription", method: "DELETE", url: "/webhooks/" + subscription.ID, expectedStatus: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			RunTestCase(t, r, tc)
+		})
+	}
+
+	// Test the rejected requests changed nothing
+	subscriptions, _ := webhookService.GetSubscriptions()
+	assert.Len(t, subscriptions, 1)
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// RequireAPIKeys rejects the requests without one of the API keys, given by the name of their holder, with 401 Unauthorized.
+// The accepted requests carry the name of the key holder as their principal. Without keys every request is rejected.
+func RequireAPIKeys(keys map[string]string) func(http.Handler) http.Handler {
+	holder := apiKeyHolder(keys)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal := holder(r.Header.Get(APIKeyHeader)); principal != "" {
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", `APIKey header="`+APIKeyHeader+`"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+	}
+}
+
+// IdentifyAPIKeys sets the name of the key holder as the principal of the requests sent with one of the API keys,
+// passing on every request. Placed before the RateLimiter, it limits the key holders by key rather than by IP address.
+func IdentifyAPIKeys(keys map[string]string) func(http.Handler) http.Handler {
+	holder := apiKeyHolder(keys)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal := holder(r.Header.Get(APIKeyHeader)); principal != "" {
+				r = r.WithContext(WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiKeyHolder returns a function returning the name of the holder of an API key, or an empty name for unknown keys
+func apiKeyHolder(keys map[string]string) func(key string) string {
+	// Compare digests of the same length in constant time, so the comparison leaks neither the keys nor their length
+	digests := make(map[string][sha256.Size]byte, len(keys))
+	for name, key := range keys {
+		if key != "" {
+			digests[name] = sha256.Sum256([]byte(key))
+		}
+	}
+
+	return func(key string) string {
+		if key == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(key))
+		principal := ""
+		for name, digest := range digests {
+			if subtle.ConstantTimeCompare(sum[:], digest[:]) == 1 {
+				principal = name
+			}
+		}
+		return principal
+	}
+}
@@ -0,0 +1,78 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+func TestRequireAPIKeys(t *testing.T) {
+	// whoami writes the client key of the request
+	whoami := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(middleware.ClientKey(r)))
+	})
+
+	tests := []struct {
+		name     string
+		keys     map[string]string
+		key      string
+		expected int
+		body     string
+	}{
+		{"ValidKey", map[string]string{"alice": "secret-a", "bob": "secret-b"}, "secret-b", http.StatusOK, "user:bob"},
+		{"InvalidKey", map[string]string{"alice": "secret-a"}, "secret-b", http.StatusUnauthorized, "unauthorized\n"},
+		{"MissingKey", map[string]string{"alice": "secret-a"}, "", http.StatusUnauthorized, "unauthorized\n"},
+		{"EmptyKeyNeverMatches", map[string]string{"alice": ""}, "", http.StatusUnauthorized, "unauthorized\n"},
+		{"NoKeys", nil, "secret-a", http.StatusUnauthorized, "unauthorized\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+			if tc.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tc.key)
+			}
+			rr := httptest.NewRecorder()
+			middleware.RequireAPIKeys(tc.keys)(whoami).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expected, rr.Code)
+			assert.Equal(t, tc.body, rr.Body.String())
+			if tc.expected == http.StatusUnauthorized {
+				assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestIdentifyAPIKeys(t *testing.T) {
+	whoami := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(middleware.ClientKey(r)))
+	})
+
+	tests := []struct {
+		name string
+		key  string
+		body string
+	}{
+		{"ValidKey", "secret-b", "user:bob"},
+		{"InvalidKey", "secret-c", "ip:192.0.2.1"},
+		{"MissingKey", "", "ip:192.0.2.1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Test every request is passed on, identified by its key holder when the key is valid
+			req := httptest.NewRequest(http.MethodGet, "/users/1/favorites", nil)
+			if tc.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tc.key)
+			}
+			rr := httptest.NewRecorder()
+			middleware.IdentifyAPIKeys(map[string]string{"alice": "secret-a", "bob": "secret-b"})(whoami).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tc.body, rr.Body.String())
+		})
+	}
+}
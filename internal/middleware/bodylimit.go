@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// DefaultMaxBodySize is the largest request body accepted by default, in bytes
+const DefaultMaxBodySize = 1 << 20
+
+// LimitBody rejects the requests with a body larger than maxBytes with 413 Request Entity Too Large.
+// Requests declaring a larger Content-Length are rejected before the handler runs; for the others the body
+// is wrapped with http.MaxBytesReader, so the handler reading past the limit gets an *http.MaxBytesError.
+// A maxBytes of zero or less disables the limit.
+func LimitBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > maxBytes {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
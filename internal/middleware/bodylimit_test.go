@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+func TestLimitBody(t *testing.T) {
+	// echo writes the request body back, or 413 if reading it hit the limit
+	var called bool
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		data, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Write(data)
+	})
+
+	tests := []struct {
+		name          string
+		limit         int64
+		body          string
+		contentLength int64
+		expected      int
+		called        bool
+	}{
+		{"UnderLimit", 10, "0123456789", 10, http.StatusOK, true},
+		{"DeclaredOverLimit", 10, "0123456789a", 11, http.StatusRequestEntityTooLarge, false},
+		{"StreamedOverLimit", 10, "0123456789a", -1, http.StatusRequestEntityTooLarge, true},
+		{"Disabled", 0, "0123456789a", 11, http.StatusOK, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.ContentLength = tc.contentLength
+			rr := httptest.NewRecorder()
+			middleware.LimitBody(tc.limit)(echo).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expected, rr.Code)
+			assert.Equal(t, tc.called, called)
+			if tc.expected == http.StatusOK {
+				assert.Equal(t, tc.body, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionConfig defines which responses are compressed
+type CompressionConfig struct {
+	Enabled bool `json:"enabled"`
+	MinSize int  `json:"minSize"` // responses smaller than MinSize bytes are sent uncompressed
+}
+
+// encoder is a compressing writer that can be reused for another response
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compressor compresses the responses with gzip or zstd, as negotiated with the Accept-Encoding header
+type Compressor struct {
+	config   CompressionConfig
+	encoders map[string]*sync.Pool
+}
+
+// NewCompressor creates a new Compressor with the given configuration
+func NewCompressor(config CompressionConfig) *Compressor {
+	return &Compressor{
+		config: config,
+		encoders: map[string]*sync.Pool{
+			"zstd": {New: func() interface{} {
+				encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedFastest))
+				return encoder
+			}},
+			"gzip": {New: func() interface{} {
+				encoder, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+				return encoder
+			}},
+		},
+	}
+}
+
+// Middleware compresses the responses of at least MinSize bytes in the encoding preferred by the client.
+// Smaller responses are buffered until the handler ends and sent as they are, event streams and responses
+// that are already encoded are never compressed, and flushing compresses what was written so far.
+func (c *Compressor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.config.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, compressor: c, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the supported encoding with the highest quality in an Accept-Encoding header,
+// preferring zstd to gzip, or an empty string if the client accepts neither
+func negotiateEncoding(header string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, err := mime.ParseMediaType("x/" + strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		qualities[strings.TrimPrefix(name, "x/")] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range []string{"zstd", "gzip"} {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the beginning of a response until it knows whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	compressor *Compressor
+	encoding   string
+	status     int
+	buf        []byte
+	started    bool
+	encoder    encoder // encoder is nil for responses sent uncompressed
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.started {
+		if !w.compressible() {
+			w.start(false)
+		} else {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) < w.compressor.config.MinSize {
+				return len(p), nil
+			}
+			return len(p), w.start(true)
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what was written so far, compressing it if the response can be compressed
+func (w *compressWriter) Flush() {
+	if !w.started {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.start(w.compressible())
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the original ResponseWriter, for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether the response can be compressed, based on its status and headers
+func (w *compressWriter) compressible() bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	// Images other than SVG are already compressed
+	return !strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "image/svg+xml")
+}
+
+// start writes the header, choosing whether the response is compressed, and the buffered beginning of the body
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	if compress {
+		header := w.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		w.encoder = w.compressor.encoders[w.encoding].Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// close sends the responses that stayed under MinSize and finishes the compressed ones
+func (w *compressWriter) close() {
+	if !w.started {
+		w.start(false)
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+		w.encoder.Reset(nil)
+		w.compressor.encoders[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
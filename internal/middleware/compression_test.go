@@ -0,0 +1,142 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+// compress serves a request with the given Accept-Encoding through a Compressor
+func compress(config middleware.CompressionConfig, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	middleware.NewCompressor(config).Middleware(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+// writeBody returns a handler writing body in chunks of 100 bytes
+func writeBody(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		for i := 0; i < len(body); i += 100 {
+			io.WriteString(w, body[i:min(i+100, len(body))])
+		}
+	}
+}
+
+// decompress returns the body of a response decoded with its Content-Encoding
+func decompress(t *testing.T, rr *httptest.ResponseRecorder) string {
+	var reader io.Reader = rr.Body
+	switch rr.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(rr.Body)
+		assert.NoError(t, err)
+		reader = gz
+	case "zstd":
+		zr, err := zstd.NewReader(rr.Body)
+		assert.NoError(t, err)
+		defer zr.Close()
+		reader = zr
+	}
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestCompressor(t *testing.T) {
+	config := middleware.CompressionConfig{Enabled: true, MinSize: 1024}
+	large := strings.Repeat(`{"id":1,"type":"Insight"}`, 200)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{"NoAcceptEncoding", "", ""},
+		{"Gzip", "gzip", "gzip"},
+		{"Zstd", "zstd", "zstd"},
+		{"PrefersZstd", "gzip, deflate, br, zstd", "zstd"},
+		{"Quality", "zstd;q=0.5, gzip", "gzip"},
+		{"Wildcard", "*", "zstd"},
+		{"Excluded", "zstd;q=0, *", "gzip"},
+		{"Unsupported", "br, deflate", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := compress(config, tc.acceptEncoding, writeBody("application/json", large))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tc.expected, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			assert.Equal(t, large, decompress(t, rr))
+			if tc.expected != "" {
+				assert.Less(t, rr.Body.Len(), len(large))
+			}
+		})
+	}
+
+	t.Run("UnderMinSize", func(t *testing.T) {
+		rr := compress(config, "gzip", writeBody("application/json", large[:1000]))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, large[:1000], rr.Body.String())
+	})
+
+	t.Run("KeepsStatus", func(t *testing.T) {
+		rr := compress(config, "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, large)
+		})
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, large, decompress(t, rr))
+
+		rr = compress(config, "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Body.String())
+	})
+
+	t.Run("SkipsImagesAndEventStreams", func(t *testing.T) {
+		for _, contentType := range []string{"image/png", "text/event-stream"} {
+			rr := compress(config, "gzip", writeBody(contentType, large))
+			assert.Empty(t, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, large, rr.Body.String())
+		}
+
+		rr := compress(config, "gzip", writeBody("image/svg+xml", large))
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		rr := compress(config, "zstd", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, "[")
+			w.(http.Flusher).Flush()
+			assert.True(t, recorder(w).Flushed)
+			io.WriteString(w, "1]")
+		})
+		assert.Equal(t, "zstd", rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "[1]", decompress(t, rr))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		rr := compress(middleware.CompressionConfig{MinSize: 1024}, "gzip", writeBody("application/json", large))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Empty(t, rr.Header().Get("Vary"))
+	})
+}
+
+// recorder returns the recorder wrapped by the Compressor
+func recorder(w http.ResponseWriter) *httptest.ResponseRecorder {
+	return w.(interface{ Unwrap() http.ResponseWriter }).Unwrap().(*httptest.ResponseRecorder)
+}
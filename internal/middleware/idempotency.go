@@ -0,0 +1,271 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// IdempotencyKeyHeader is the header clients send to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long the responses of requests with an Idempotency-Key are replayed by default
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is a request sent with an Idempotency-Key, and its response once the request completed
+type IdempotencyRecord struct {
+	Fingerprint string // hash of the method, path and body of the request
+	ExpiresAt   time.Time
+	Completed   bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore holds the records of the requests sent with an Idempotency-Key. The in-memory store keeps them
+// per process, a shared store (e.g. Redis) can implement the same interface to replay responses across instances.
+type IdempotencyStore interface {
+	// Reserve saves the record of a new request under the key and returns true,
+	// or returns the record already saved under the key and false if it has not expired at now
+	Reserve(key string, record IdempotencyRecord, now time.Time) (IdempotencyRecord, bool)
+	// Complete replaces the record of a reserved key with the completed one
+	Complete(key string, record IdempotencyRecord)
+	// Release removes a key, so the request can be sent again
+	Release(key string)
+}
+
+// Idempotency is a middleware replaying the response of a request sent again with the same Idempotency-Key,
+// so clients can retry changes on flaky networks without applying them twice.
+// Keys are scoped to the user sending them, so users cannot replay each other's responses.
+type Idempotency struct {
+	Store   IdempotencyStore
+	KeyFunc func(r *http.Request) string
+	TTL     time.Duration
+}
+
+// NewIdempotency creates a new Idempotency middleware replaying responses for ttl, identifying users with IdempotencyScope
+func NewIdempotency(store IdempotencyStore, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		Store:   store,
+		KeyFunc: IdempotencyScope,
+		TTL:     ttl,
+	}
+}
+
+// IdempotencyScope identifies the user of a request by its verified principal, or else by the {id} of the route,
+// rather than by the IP address, so the clients retrying from another network still get their responses replayed.
+// The requests of neither fall back to ClientKey.
+func IdempotencyScope(r *http.Request) string {
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal
+	}
+	if userID, err := strconv.Atoi(mux.Vars(r)["id"]); err == nil {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return ClientKey(r)
+}
+
+// Middleware handles the requests with an Idempotency-Key header and an unsafe method (POST, PUT, PATCH, DELETE).
+// The first response is saved and replayed with an Idempotent-Replayed header to the retries, a key reused for another
+// request gets 422 Unprocessable Entity and a retry sent while the first request is still running 409 Conflict.
+// Server errors are not saved, so the request can be retried.
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || i.TTL <= 0 || !unsafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, "invalid idempotency key", http.StatusBadRequest)
+			return
+		}
+
+		fingerprint, ok := requestFingerprint(w, r)
+		if !ok {
+			return
+		}
+
+		storeKey := i.KeyFunc(r) + " " + key
+		now := time.Now()
+		existing, reserved := i.Store.Reserve(storeKey, IdempotencyRecord{Fingerprint: fingerprint, ExpiresAt: now.Add(i.TTL)}, now)
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				http.Error(w, "idempotency key reused with a different request", http.StatusUnprocessableEntity)
+			case !existing.Completed:
+				http.Error(w, "a request with the same idempotency key is in progress", http.StatusConflict)
+			default:
+				replay(w, existing)
+			}
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if !completed {
+				i.Store.Release(storeKey)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		i.Store.Complete(storeKey, IdempotencyRecord{
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().Add(i.TTL),
+			Completed:   true,
+			Status:      rec.status,
+			Header:      rec.header,
+			Body:        rec.body.Bytes(),
+		})
+		completed = true
+	})
+}
+
+// unsafeMethod reports whether a request with the method can change the state of the server
+func unsafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+// requestFingerprint reads the body of a request, leaving it readable for the handler,
+// and returns the hash of the method, path and body, writing the error response if the body cannot be read
+func requestFingerprint(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			} else {
+				http.Error(w, "Error reading request body", http.StatusInternalServerError)
+			}
+			return "", false
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// replay writes a saved response. Headers already set by the outer middleware, like the rate limits, are kept.
+func replay(w http.ResponseWriter, record IdempotencyRecord) {
+	header := w.Header()
+	for name, values := range record.Header {
+		if _, ok := header[name]; !ok {
+			header[name] = values
+		}
+	}
+	header.Set("Idempotent-Replayed", "true")
+	header.Set("Content-Length", strconv.Itoa(len(record.Body)))
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// recordingWriter passes a response through while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the original ResponseWriter, for http.ResponseController
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// InMemoryIdempotencyStore is an in-memory implementation of the IdempotencyStore interface
+type InMemoryIdempotencyStore struct {
+	records  map[string]IdempotencyRecord
+	reserves int
+	mu       sync.Mutex // mu protects the records map from concurrent access
+}
+
+// NewInMemoryIdempotencyStore creates a new instance of InMemoryIdempotencyStore
+func NewInMemoryIdempotencyStore() *InMemoryIdempotencyStore {
+	return &InMemoryIdempotencyStore{
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+// Reserve saves the record under the key unless an unexpired record is already saved there
+func (s *InMemoryIdempotencyStore) Reserve(key string, record IdempotencyRecord, now time.Time) (IdempotencyRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reserves++
+	if s.reserves%cleanupInterval == 0 {
+		s.cleanup(now)
+	}
+
+	if existing, ok := s.records[key]; ok && existing.ExpiresAt.After(now) {
+		return existing, false
+	}
+	s.records[key] = record
+	return record, true
+}
+
+// Complete replaces the record saved under the key
+func (s *InMemoryIdempotencyStore) Complete(key string, record IdempotencyRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+}
+
+// Release removes the record saved under the key
+func (s *InMemoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+}
+
+// cleanup removes the expired records
+func (s *InMemoryIdempotencyStore) cleanup(now time.Time) {
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+}
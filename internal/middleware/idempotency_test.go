@@ -0,0 +1,209 @@
+package middleware_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+func TestInMemoryIdempotencyStore(t *testing.T) {
+	store := middleware.NewInMemoryIdempotencyStore()
+	now := time.Now()
+	record := middleware.IdempotencyRecord{Fingerprint: "a", ExpiresAt: now.Add(time.Minute)}
+
+	// Test the first request reserves the key
+	_, reserved := store.Reserve("key", record, now)
+	assert.True(t, reserved)
+
+	// Test the retries get the saved record
+	store.Complete("key", middleware.IdempotencyRecord{Fingerprint: "a", ExpiresAt: now.Add(time.Minute), Completed: true, Status: http.StatusCreated})
+	existing, reserved := store.Reserve("key", record, now)
+	assert.False(t, reserved)
+	assert.True(t, existing.Completed)
+	assert.Equal(t, http.StatusCreated, existing.Status)
+
+	// Test expired records are replaced
+	_, reserved = store.Reserve("key", record, now.Add(time.Minute))
+	assert.True(t, reserved)
+
+	// Test released keys can be reserved again
+	store.Release("key")
+	_, reserved = store.Reserve("key", record, now)
+	assert.True(t, reserved)
+}
+
+// newIdempotentHandler returns a handler creating a numbered resource on every call it receives
+func newIdempotentHandler(calls *int32) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":%d}`, n)
+	})
+	return middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour).Middleware(handler)
+}
+
+// sendIdempotent sends a request with an Idempotency-Key to the handler, from a client authenticated as principal
+func sendIdempotent(h http.Handler, method, url, body, key, principal string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), principal))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("ReplaysRetries", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls)
+
+		first := sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "abc", "client")
+		retry := sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "abc", "client")
+
+		assert.Equal(t, int32(1), calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("DifferentRequest", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls)
+
+		sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "abc", "client")
+		rr := sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":101}`, "abc", "client")
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+		rr = sendIdempotent(h, http.MethodPost, "/users/2/favorites", `{"id":100}`, "abc", "client")
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("ScopedToClient", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls)
+
+		sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "abc", "client")
+		rr := sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":101}`, "abc", "other")
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("ScopedToPathUser", func(t *testing.T) {
+		var calls int32
+		r := mux.NewRouter()
+		r.Handle("/users/{id}/favorites", newIdempotentHandler(&calls))
+
+		send := func(url, remoteAddr string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"id":100}`))
+			req.Header.Set(middleware.IdempotencyKeyHeader, "abc")
+			req.RemoteAddr = remoteAddr
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			return rr
+		}
+
+		// Test a retry from another network is replayed
+		first := send("/users/1/favorites", "192.0.2.1:1234")
+		retry := send("/users/1/favorites", "198.51.100.7:4321")
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, int32(1), calls)
+
+		// Test another user can use the same key
+		rr := send("/users/2/favorites", "192.0.2.1:1234")
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("WithoutKey", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls)
+
+		sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "", "client")
+		sendIdempotent(h, http.MethodPost, "/users/1/favorites", `{"id":100}`, "", "client")
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("SafeMethods", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls)
+
+		sendIdempotent(h, http.MethodGet, "/users/1/favorites", "", "abc", "client")
+		sendIdempotent(h, http.MethodGet, "/users/1/favorites", "", "abc", "client")
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		var calls int32
+		rr := sendIdempotent(newIdempotentHandler(&calls), http.MethodPost, "/", "", strings.Repeat("k", 256), "client")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, int32(0), calls)
+	})
+
+	t.Run("ServerErrorsAreRetried", func(t *testing.T) {
+		var calls int32
+		h := middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		rr := sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client")
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		rr = sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client")
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		rr = sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client")
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		h := middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client") }()
+		<-started
+
+		rr := sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client")
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("BodyStillReadable", func(t *testing.T) {
+		h := middleware.NewIdempotency(middleware.NewInMemoryIdempotencyStore(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			w.Write(body)
+		}))
+
+		rr := sendIdempotent(h, http.MethodPost, "/", "body", "abc", "client")
+		assert.Equal(t, "body", rr.Body.String())
+	})
+}
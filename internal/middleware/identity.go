@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// APIKeyHeader is the header clients send their API key in
+const APIKeyHeader = "X-API-Key"
+
+// contextKey is the type of the keys this package stores in a request context
+type contextKey int
+
+const (
+	principalKey contextKey = iota
+	requestIDKey
+)
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal of a request
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey).(string)
+	return principal, ok && principal != ""
+}
+
+// ClientKey identifies the caller of a request by its authenticated principal, falling back to the client IP address.
+// Unverified credentials such as an X-API-Key header that no middleware accepted are ignored, since any client
+// could send a new one with every request.
+func ClientKey(r *http.Request) string {
+	return ClientKeyFor(r.Context(), ClientIP(r))
+}
+
+// ClientKeyFor identifies a caller the same way as ClientKey, for callers that are not HTTP requests
+func ClientKeyFor(ctx context.Context, ip string) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return "user:" + principal
+	}
+	return "ip:" + ip
+}
+
+// ClientIP returns the IP address of the client that sent the request
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Limit defines a token bucket: it refills at Rate tokens per second and holds at most Burst tokens
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// RateLimitConfig defines the limits applied by the RateLimiter.
+// Routes are keyed by method and path template, e.g. "POST /users/{id}/favorites",
+// and requests to any other route use the Default limit.
+type RateLimitConfig struct {
+	Enabled bool             `json:"enabled"`
+	Default Limit            `json:"default"`
+	Routes  map[string]Limit `json:"routes"`
+}
+
+// RateLimitResult is the state of a bucket after trying to take a token from it
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // time until a token is available, zero when Allowed
+	Reset      time.Duration // time until the bucket is full again
+}
+
+// RateLimitStore holds the token buckets. The in-memory store keeps them per process,
+// a shared store (e.g. Redis) can implement the same interface to limit across instances.
+type RateLimitStore interface {
+	Take(key string, limit Limit, now time.Time) RateLimitResult
+}
+
+// RateLimiter is a middleware limiting the request rate of every client per route
+type RateLimiter struct {
+	Store   RateLimitStore
+	KeyFunc func(r *http.Request) string
+
+	mu     sync.RWMutex
+	config RateLimitConfig
+}
+
+// NewRateLimiter creates a new RateLimiter identifying clients with ClientKey
+func NewRateLimiter(store RateLimitStore, config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		Store:   store,
+		KeyFunc: ClientKey,
+		config:  config,
+	}
+}
+
+// SetConfig replaces the limits, which allows reloading them while the server runs
+func (l *RateLimiter) SetConfig(config RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+}
+
+// Middleware rejects requests over the limit with 429 Too Many Requests.
+// Every limited response carries the RateLimit-* headers, and rejected ones a Retry-After header.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeKey(r)
+
+		l.mu.RLock()
+		config := l.config
+		l.mu.RUnlock()
+
+		limit, ok := config.Routes[route]
+		if !ok {
+			limit = config.Default
+		}
+		if !config.Enabled || limit.Rate <= 0 || limit.Burst <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result := l.Store.Take(l.KeyFunc(r)+" "+route, limit, time.Now())
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeKey returns the method and path template of the matched route, or the raw path when no route matched
+func routeKey(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+	return r.Method + " " + r.URL.Path
+}
+
+// ceilSeconds rounds a duration up to whole seconds, as required by the Retry-After header
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// bucket is a single token bucket, remembering its limit for the cleanup of idle buckets
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// InMemoryRateLimitStore is an in-memory implementation of the RateLimitStore interface
+type InMemoryRateLimitStore struct {
+	buckets map[string]*bucket
+	takes   int
+	mu      sync.Mutex // mu protects the buckets map from concurrent access
+}
+
+// cleanupInterval is the number of takes between two removals of idle buckets
+const cleanupInterval = 1024
+
+// NewInMemoryRateLimitStore creates a new instance of InMemoryRateLimitStore
+func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Take refills the bucket for the time elapsed since the last take and removes one token if available
+func (s *InMemoryRateLimitStore) Take(key string, limit Limit, now time.Time) RateLimitResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%cleanupInterval == 0 {
+		s.cleanup(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+
+	// Refill the tokens accumulated since the last take, up to the burst size
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+
+	result := RateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+	return result
+}
+
+// cleanup removes the buckets that would be full by now, since they behave exactly like new ones
+func (s *InMemoryRateLimitStore) cleanup(now time.Time) {
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,124 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryRateLimitStore(t *testing.T) {
+	store := middleware.NewInMemoryRateLimitStore()
+	limit := middleware.Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	// Test the burst is available immediately
+	result := store.Take("client", limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result = store.Take("client", limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Test the bucket is empty and reports when the next token is available
+	result = store.Take("client", limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	// Test other clients have their own bucket
+	result = store.Take("other", limit, now)
+	assert.True(t, result.Allowed)
+
+	// Test the bucket refills over time
+	result = store.Take("client", limit, now.Add(time.Second))
+	assert.True(t, result.Allowed)
+}
+
+// newRateLimitedRouter returns a router with two routes limited by the given config
+func newRateLimitedRouter(config middleware.RateLimitConfig) *mux.Router {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/favorites", ok).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}/favorites", ok).Methods(http.MethodPost)
+	r.Use(middleware.NewRateLimiter(middleware.NewInMemoryRateLimitStore(), config).Middleware)
+	return r
+}
+
+// serve sends a request to the router and returns the response recorder
+func serve(r http.Handler, method, url, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, nil)
+	req.RemoteAddr = remoteAddr
+	if apiKey != "" {
+		req.Header.Set(middleware.APIKeyHeader, apiKey)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	r := newRateLimitedRouter(middleware.RateLimitConfig{
+		Enabled: true,
+		Default: middleware.Limit{Rate: 1, Burst: 5},
+		Routes: map[string]middleware.Limit{
+			"POST /users/{id}/favorites": {Rate: 1, Burst: 1},
+		},
+	})
+
+	// Test the route specific limit
+	rr := serve(r, http.MethodPost, "/users/1/favorites", "10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = serve(r, http.MethodPost, "/users/2/favorites", "10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), "too many requests")
+
+	// Test the other routes use the default limit
+	rr = serve(r, http.MethodGet, "/users/1/favorites", "10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("RateLimit-Limit"))
+
+	// Test other IP addresses are limited separately
+	rr = serve(r, http.MethodPost, "/users/1/favorites", "10.0.0.2:1234", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Test an unverified API key does not get a limit of its own
+	rr = serve(r, http.MethodPost, "/users/1/favorites", "10.0.0.1:1234", "random-key")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	r := newRateLimitedRouter(middleware.RateLimitConfig{
+		Enabled: false,
+		Default: middleware.Limit{Rate: 1, Burst: 1},
+	})
+
+	for i := 0; i < 3; i++ {
+		rr := serve(r, http.MethodGet, "/users/1/favorites", "10.0.0.1:1234", "")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.10:5555"
+	assert.Equal(t, "ip:192.168.1.10", middleware.ClientKey(req))
+
+	// Test an unverified API key does not identify the client, so new keys cannot get new rate limits
+	req.Header.Set(middleware.APIKeyHeader, "secret")
+	assert.Equal(t, "ip:192.168.1.10", middleware.ClientKey(req))
+
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), "admin"))
+	assert.Equal(t, "user:admin", middleware.ClientKey(req))
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the ID of a request, sent by the client or generated by the server
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the longest request ID accepted from a client
+const maxRequestIDLength = 128
+
+// RequestID identifies every request, keeping the ID sent by the client in the X-Request-ID header if it is valid
+// and generating a new one otherwise. The ID is returned in the response header and stored in the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := RequestIDFor(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// RequestIDFor returns the request ID sent by a client if it is valid, or a newly generated one,
+// for callers that are not HTTP requests
+func RequestIDFor(requestID string) string {
+	if validRequestID(requestID) {
+		return requestID
+	}
+	return newRequestID()
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of a request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok && requestID != ""
+}
+
+// validRequestID reports whether a client sent request ID can be kept. Only short IDs of letters, digits and
+// "-_.:" are, so the IDs are safe to write to logs and the audit log.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random request ID of 32 hexadecimal characters
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	// echo writes the request ID stored in the request context
+	echo := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID, _ := middleware.RequestIDFromContext(r.Context())
+		w.Write([]byte(requestID))
+	}))
+
+	tests := []struct {
+		name string
+		sent string
+		kept bool
+	}{
+		{"ClientID", "checkout-42:retry.1_a", true},
+		{"MissingID", "", false},
+		{"InvalidCharacters", "id with spaces\n", false},
+		{"TooLong", strings.Repeat("a", 129), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(middleware.RequestIDHeader, tc.sent)
+			rr := httptest.NewRecorder()
+			echo.ServeHTTP(rr, req)
+
+			requestID := rr.Header().Get(middleware.RequestIDHeader)
+			assert.Equal(t, requestID, rr.Body.String())
+			if tc.kept {
+				assert.Equal(t, tc.sent, requestID)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", requestID)
+			}
+		})
+	}
+}
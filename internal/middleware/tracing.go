@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+// Tracing starts a server span for every request, named after its route, e.g. "POST /users/{id}/favorites".
+// Requests with a W3C traceparent header continue the trace of their caller.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+		}
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				attrs = append(attrs, semconv.HTTPRoute(template))
+			}
+		}
+		if userID, err := strconv.Atoi(mux.Vars(r)["id"]); err == nil {
+			attrs = append(attrs, telemetry.UserIDKey.Int(userID))
+		}
+
+		ctx, span := telemetry.Tracer().Start(ctx, routeKey(r), trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		sw := &StatusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		// Client errors are an outcome of the request, but only server errors mark the span as failed
+		status := sw.StatusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusBadRequest {
+			span.SetAttributes(telemetry.OutcomeKey.String("error"))
+		} else {
+			span.SetAttributes(telemetry.OutcomeKey.String("ok"))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// StatusWriter records the status code of a response, passing flushes through for streamed responses
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *StatusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *StatusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends the response written so far, if the original ResponseWriter supports it
+func (w *StatusWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the original ResponseWriter, for http.ResponseController
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// StatusCode returns the status code of the response, 200 if the handler wrote none
+func (w *StatusWriter) StatusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditRecord is an entry of the append-only audit log, recorded for every change of a favourite.
+// The values of the changed fields are encrypted with a key of the user, so the log keeps nothing readable
+// of the favourites once the key is deleted by the erasure of their user.
+type AuditRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"requestId,omitempty"` // empty for changes made outside of a request
+	UserID    int       `json:"userId"`
+	AssetID   int       `json:"assetId"`
+	Operation Operation `json:"operation"`
+	Version   int       `json:"version"` // version of the favourite in its history
+	Fields    []string  `json:"fields"`  // names of the fields of the favourite that were set, changed or removed
+	// Changes are the values of the Fields before and after the change, returned while the key of the user exists
+	Changes []FieldChange `json:"changes,omitempty"`
+	// EncryptedChanges are the Changes as stored by the audit sinks, encrypted with the key of the user
+	EncryptedChanges []byte `json:"encryptedChanges,omitempty"`
+}
+
+// FieldChange is the JSON value of a field of a favourite before and after a change, missing where the field was not set
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditPage is a page of the records of the audit log, with the cursor continuing after them
+type AuditPage struct {
+	Records []AuditRecord `json:"records"`
+	// Next is the cursor of the next page. On a page with fewer records than requested, it continues after the end
+	// of the log, so it can be used to poll for the records appended later.
+	Next string `json:"next"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Role is a custom type for the access a member has to a collection
+type Role string
+
+// Define constants for the collection roles
+const (
+	RoleViewer Role = "viewer" // can see the collection
+	RoleEditor Role = "editor" // can also add and remove assets
+)
+
+// Collection is a named list of favourites owned by a user and shared with other users
+type Collection struct {
+	ID        int          `json:"id"`
+	OwnerID   int          `json:"ownerId"`
+	Name      string       `json:"name"`
+	Members   map[int]Role `json:"members"` // roles of the members, keyed by user id
+	Assets    []AssetRef   `json:"assets"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Version   int          `json:"version"` // incremented by every update, so concurrent updates are detected
+}
+
+// AssetRef points to an asset in the favourites of a user
+type AssetRef struct {
+	UserID  int `json:"userId"`
+	AssetID int `json:"assetId"`
+}
+
+// CollectionItem is an asset of a collection, resolved from the favourites of the user who owns it
+type CollectionItem struct {
+	UserID int   `json:"userId"`
+	Asset  Asset `json:"asset"`
+}
+
+// CollectionDetails is a collection with its assets resolved.
+// Assets that were deleted from their owner's favourites since they were added are left out.
+type CollectionDetails struct {
+	Collection
+	Items []CollectionItem `json:"items"`
+}
+
+// SharedCollection is a collection shared with a user, with the role the user was given
+type SharedCollection struct {
+	Collection
+	Role Role `json:"role"`
+}
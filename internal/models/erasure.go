@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ErasureRecord is the proof that the data held about a user was erased, holding none of the erased data.
+// Removed counts what was removed or anonymized, by kind, e.g. "favorites" or "historyEntries".
+type ErasureRecord struct {
+	ID          int            `json:"id"`
+	UserID      int            `json:"userId"`
+	Actor       string         `json:"actor"`
+	RequestedAt time.Time      `json:"requestedAt"`
+	CompletedAt time.Time      `json:"completedAt"`
+	Removed     map[string]int `json:"removed"`
+	UserDeleted bool           `json:"userDeleted"` // false when the repository kept the user, without any favourites
+}
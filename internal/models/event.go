@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// EventType is a custom type for the kinds of changes published to the favourites listeners
+type EventType string
+
+// Define constants for the published event types
+const (
+	EventFavoriteAdded   EventType = "favorite.added"
+	EventFavoriteEdited  EventType = "favorite.edited"
+	EventFavoriteDeleted EventType = "favorite.deleted"
+)
+
+// EventTypes lists every event type that can be published
+var EventTypes = []EventType{EventFavoriteAdded, EventFavoriteEdited, EventFavoriteDeleted}
+
+// FavoriteEvent describes a change made to a user's favourite.
+// Asset is the favourite after the change, or the deleted favourite for deletions.
+type FavoriteEvent struct {
+	Type      EventType `json:"type"`
+	UserID    int       `json:"userId"`
+	AssetID   int       `json:"assetId"`
+	Version   int       `json:"version"`
+	Actor     string    `json:"actor"`
+	Timestamp time.Time `json:"timestamp"`
+	Asset     Asset     `json:"asset"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Operation is a custom type for the mutations applied to a favourite
+type Operation string
+
+// Define constants for the recorded operations
+const (
+	OperationAdd     Operation = "add"
+	OperationEdit    Operation = "edit"
+	OperationDelete  Operation = "delete"
+	OperationRestore Operation = "restore"
+	OperationErase   Operation = "erase" // erasure of a user, only recorded in the audit log
+)
+
+// HistoryEntry is a version of a user's favourite, recorded on every mutation.
+// Before is nil for additions and After is nil for deletions.
+type HistoryEntry struct {
+	Version   int       `json:"version"`
+	UserID    int       `json:"userId"`
+	AssetID   int       `json:"assetId"`
+	Operation Operation `json:"operation"`
+	Actor     string    `json:"actor"`
+	Timestamp time.Time `json:"timestamp"`
+	Before    Asset     `json:"before"`
+	After     Asset     `json:"after"`
+}
+
+// TrashItem is a deleted favourite that can be restored until it expires
+type TrashItem struct {
+	UserID    int       `json:"userId"`
+	Asset     Asset     `json:"asset"`
+	DeletedBy string    `json:"deletedBy"`
+	DeletedAt time.Time `json:"deletedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired reports whether the item expired at or before now. Items with a zero ExpiresAt never expire.
+func (item TrashItem) Expired(now time.Time) bool {
+	return !item.ExpiresAt.IsZero() && !item.ExpiresAt.After(now)
+}
@@ -0,0 +1,436 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Age groups are the canonical buckets an Audience age falls into
+const (
+	AgeGroupTeen       string = "0-17"
+	AgeGroupYoungAdult string = "18-25"
+	AgeGroupAdult      string = "26-40"
+	AgeGroupMiddleAged string = "41-65"
+	AgeGroupSenior     string = "66+"
+)
+
+// Genders are the allowed values of Audience.Gender
+const (
+	GenderMale      string = "Male"
+	GenderFemale    string = "Female"
+	GenderNonBinary string = "Non-Binary"
+)
+
+// AgeGroup describes an age bucket, MaxAge is nil for the open ended last bucket
+type AgeGroup struct {
+	Name   string `json:"name"`
+	MinAge uint   `json:"minAge"`
+	MaxAge *uint  `json:"maxAge"`
+}
+
+// Country is a country as defined by ISO 3166-1, identified by its alpha-2 code
+type Country struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// AgeGroups lists the age buckets in ascending order
+var AgeGroups = []AgeGroup{
+	{Name: AgeGroupTeen, MinAge: 0, MaxAge: uintPtr(17)},
+	{Name: AgeGroupYoungAdult, MinAge: 18, MaxAge: uintPtr(25)},
+	{Name: AgeGroupAdult, MinAge: 26, MaxAge: uintPtr(40)},
+	{Name: AgeGroupMiddleAged, MinAge: 41, MaxAge: uintPtr(65)},
+	{Name: AgeGroupSenior, MinAge: 66},
+}
+
+// Genders lists the allowed genders
+var Genders = []string{GenderMale, GenderFemale, GenderNonBinary}
+
+// genderAliases maps alternative spellings (in lower case) to the canonical gender
+var genderAliases = map[string]string{
+	"m":          GenderMale,
+	"man":        GenderMale,
+	"f":          GenderFemale,
+	"woman":      GenderFemale,
+	"nb":         GenderNonBinary,
+	"nonbinary":  GenderNonBinary,
+	"non binary": GenderNonBinary,
+	"enby":       GenderNonBinary,
+}
+
+// countryAliases maps alternative names (in lower case) to the ISO 3166-1 alpha-2 code
+var countryAliases = map[string]string{
+	"usa":                                   "US",
+	"u.s.":                                  "US",
+	"u.s.a.":                                "US",
+	"united states of america":              "US",
+	"america":                               "US",
+	"uk":                                    "GB",
+	"u.k.":                                  "GB",
+	"great britain":                         "GB",
+	"britain":                               "GB",
+	"czech republic":                        "CZ",
+	"cape verde":                            "CV",
+	"ivory coast":                           "CI",
+	"cote d'ivoire":                         "CI",
+	"curacao":                               "CW",
+	"reunion":                               "RE",
+	"aland islands":                         "AX",
+	"saint barthelemy":                      "BL",
+	"russian federation":                    "RU",
+	"republic of korea":                     "KR",
+	"korea":                                 "KR",
+	"democratic people's republic of korea": "KP",
+	"dr congo":                              "CD",
+	"drc":                                   "CD",
+	"republic of the congo":                 "CG",
+	"holy see":                              "VA",
+	"vatican":                               "VA",
+	"burma":                                 "MM",
+	"swaziland":                             "SZ",
+	"east timor":                            "TL",
+	"macedonia":                             "MK",
+	"turkiye":                               "TR",
+	"türkiye":                               "TR",
+	"brunei darussalam":                     "BN",
+	"viet nam":                              "VN",
+	"lao people's democratic republic":      "LA",
+	"syrian arab republic":                  "SY",
+	"iran, islamic republic of":             "IR",
+	"state of palestine":                    "PS",
+	"the netherlands":                       "NL",
+	"holland":                               "NL",
+	"the gambia":                            "GM",
+	"the bahamas":                           "BS",
+}
+
+// Countries lists every ISO 3166-1 country, sorted by code
+var Countries = []Country{
+	{Code: "AD", Name: "Andorra"},
+	{Code: "AE", Name: "United Arab Emirates"},
+	{Code: "AF", Name: "Afghanistan"},
+	{Code: "AG", Name: "Antigua and Barbuda"},
+	{Code: "AI", Name: "Anguilla"},
+	{Code: "AL", Name: "Albania"},
+	{Code: "AM", Name: "Armenia"},
+	{Code: "AO", Name: "Angola"},
+	{Code: "AQ", Name: "Antarctica"},
+	{Code: "AR", Name: "Argentina"},
+	{Code: "AS", Name: "American Samoa"},
+	{Code: "AT", Name: "Austria"},
+	{Code: "AU", Name: "Australia"},
+	{Code: "AW", Name: "Aruba"},
+	{Code: "AX", Name: "Åland Islands"},
+	{Code: "AZ", Name: "Azerbaijan"},
+	{Code: "BA", Name: "Bosnia and Herzegovina"},
+	{Code: "BB", Name: "Barbados"},
+	{Code: "BD", Name: "Bangladesh"},
+	{Code: "BE", Name: "Belgium"},
+	{Code: "BF", Name: "Burkina Faso"},
+	{Code: "BG", Name: "Bulgaria"},
+	{Code: "BH", Name: "Bahrain"},
+	{Code: "BI", Name: "Burundi"},
+	{Code: "BJ", Name: "Benin"},
+	{Code: "BL", Name: "Saint Barthélemy"},
+	{Code: "BM", Name: "Bermuda"},
+	{Code: "BN", Name: "Brunei"},
+	{Code: "BO", Name: "Bolivia"},
+	{Code: "BQ", Name: "Bonaire, Sint Eustatius and Saba"},
+	{Code: "BR", Name: "Brazil"},
+	{Code: "BS", Name: "Bahamas"},
+	{Code: "BT", Name: "Bhutan"},
+	{Code: "BV", Name: "Bouvet Island"},
+	{Code: "BW", Name: "Botswana"},
+	{Code: "BY", Name: "Belarus"},
+	{Code: "BZ", Name: "Belize"},
+	{Code: "CA", Name: "Canada"},
+	{Code: "CC", Name: "Cocos (Keeling) Islands"},
+	{Code: "CD", Name: "Democratic Republic of the Congo"},
+	{Code: "CF", Name: "Central African Republic"},
+	{Code: "CG", Name: "Congo"},
+	{Code: "CH", Name: "Switzerland"},
+	{Code: "CI", Name: "Côte d'Ivoire"},
+	{Code: "CK", Name: "Cook Islands"},
+	{Code: "CL", Name: "Chile"},
+	{Code: "CM", Name: "Cameroon"},
+	{Code: "CN", Name: "China"},
+	{Code: "CO", Name: "Colombia"},
+	{Code: "CR", Name: "Costa Rica"},
+	{Code: "CU", Name: "Cuba"},
+	{Code: "CV", Name: "Cabo Verde"},
+	{Code: "CW", Name: "Curaçao"},
+	{Code: "CX", Name: "Christmas Island"},
+	{Code: "CY", Name: "Cyprus"},
+	{Code: "CZ", Name: "Czechia"},
+	{Code: "DE", Name: "Germany"},
+	{Code: "DJ", Name: "Djibouti"},
+	{Code: "DK", Name: "Denmark"},
+	{Code: "DM", Name: "Dominica"},
+	{Code: "DO", Name: "Dominican Republic"},
+	{Code: "DZ", Name: "Algeria"},
+	{Code: "EC", Name: "Ecuador"},
+	{Code: "EE", Name: "Estonia"},
+	{Code: "EG", Name: "Egypt"},
+	{Code: "EH", Name: "Western Sahara"},
+	{Code: "ER", Name: "Eritrea"},
+	{Code: "ES", Name: "Spain"},
+	{Code: "ET", Name: "Ethiopia"},
+	{Code: "FI", Name: "Finland"},
+	{Code: "FJ", Name: "Fiji"},
+	{Code: "FK", Name: "Falkland Islands"},
+	{Code: "FM", Name: "Micronesia"},
+	{Code: "FO", Name: "Faroe Islands"},
+	{Code: "FR", Name: "France"},
+	{Code: "GA", Name: "Gabon"},
+	{Code: "GB", Name: "United Kingdom"},
+	{Code: "GD", Name: "Grenada"},
+	{Code: "GE", Name: "Georgia"},
+	{Code: "GF", Name: "French Guiana"},
+	{Code: "GG", Name: "Guernsey"},
+	{Code: "GH", Name: "Ghana"},
+	{Code: "GI", Name: "Gibraltar"},
+	{Code: "GL", Name: "Greenland"},
+	{Code: "GM", Name: "Gambia"},
+	{Code: "GN", Name: "Guinea"},
+	{Code: "GP", Name: "Guadeloupe"},
+	{Code: "GQ", Name: "Equatorial Guinea"},
+	{Code: "GR", Name: "Greece"},
+	{Code: "GS", Name: "South Georgia and the South Sandwich Islands"},
+	{Code: "GT", Name: "Guatemala"},
+	{Code: "GU", Name: "Guam"},
+	{Code: "GW", Name: "Guinea-Bissau"},
+	{Code: "GY", Name: "Guyana"},
+	{Code: "HK", Name: "Hong Kong"},
+	{Code: "HM", Name: "Heard Island and McDonald Islands"},
+	{Code: "HN", Name: "Honduras"},
+	{Code: "HR", Name: "Croatia"},
+	{Code: "HT", Name: "Haiti"},
+	{Code: "HU", Name: "Hungary"},
+	{Code: "ID", Name: "Indonesia"},
+	{Code: "IE", Name: "Ireland"},
+	{Code: "IL", Name: "Israel"},
+	{Code: "IM", Name: "Isle of Man"},
+	{Code: "IN", Name: "India"},
+	{Code: "IO", Name: "British Indian Ocean Territory"},
+	{Code: "IQ", Name: "Iraq"},
+	{Code: "IR", Name: "Iran"},
+	{Code: "IS", Name: "Iceland"},
+	{Code: "IT", Name: "Italy"},
+	{Code: "JE", Name: "Jersey"},
+	{Code: "JM", Name: "Jamaica"},
+	{Code: "JO", Name: "Jordan"},
+	{Code: "JP", Name: "Japan"},
+	{Code: "KE", Name: "Kenya"},
+	{Code: "KG", Name: "Kyrgyzstan"},
+	{Code: "KH", Name: "Cambodia"},
+	{Code: "KI", Name: "Kiribati"},
+	{Code: "KM", Name: "Comoros"},
+	{Code: "KN", Name: "Saint Kitts and Nevis"},
+	{Code: "KP", Name: "North Korea"},
+	{Code: "KR", Name: "South Korea"},
+	{Code: "KW", Name: "Kuwait"},
+	{Code: "KY", Name: "Cayman Islands"},
+	{Code: "KZ", Name: "Kazakhstan"},
+	{Code: "LA", Name: "Laos"},
+	{Code: "LB", Name: "Lebanon"},
+	{Code: "LC", Name: "Saint Lucia"},
+	{Code: "LI", Name: "Liechtenstein"},
+	{Code: "LK", Name: "Sri Lanka"},
+	{Code: "LR", Name: "Liberia"},
+	{Code: "LS", Name: "Lesotho"},
+	{Code: "LT", Name: "Lithuania"},
+	{Code: "LU", Name: "Luxembourg"},
+	{Code: "LV", Name: "Latvia"},
+	{Code: "LY", Name: "Libya"},
+	{Code: "MA", Name: "Morocco"},
+	{Code: "MC", Name: "Monaco"},
+	{Code: "MD", Name: "Moldova"},
+	{Code: "ME", Name: "Montenegro"},
+	{Code: "MF", Name: "Saint Martin"},
+	{Code: "MG", Name: "Madagascar"},
+	{Code: "MH", Name: "Marshall Islands"},
+	{Code: "MK", Name: "North Macedonia"},
+	{Code: "ML", Name: "Mali"},
+	{Code: "MM", Name: "Myanmar"},
+	{Code: "MN", Name: "Mongolia"},
+	{Code: "MO", Name: "Macao"},
+	{Code: "MP", Name: "Northern Mariana Islands"},
+	{Code: "MQ", Name: "Martinique"},
+	{Code: "MR", Name: "Mauritania"},
+	{Code: "MS", Name: "Montserrat"},
+	{Code: "MT", Name: "Malta"},
+	{Code: "MU", Name: "Mauritius"},
+	{Code: "MV", Name: "Maldives"},
+	{Code: "MW", Name: "Malawi"},
+	{Code: "MX", Name: "Mexico"},
+	{Code: "MY", Name: "Malaysia"},
+	{Code: "MZ", Name: "Mozambique"},
+	{Code: "NA", Name: "Namibia"},
+	{Code: "NC", Name: "New Caledonia"},
+	{Code: "NE", Name: "Niger"},
+	{Code: "NF", Name: "Norfolk Island"},
+	{Code: "NG", Name: "Nigeria"},
+	{Code: "NI", Name: "Nicaragua"},
+	{Code: "NL", Name: "Netherlands"},
+	{Code: "NO", Name: "Norway"},
+	{Code: "NP", Name: "Nepal"},
+	{Code: "NR", Name: "Nauru"},
+	{Code: "NU", Name: "Niue"},
+	{Code: "NZ", Name: "New Zealand"},
+	{Code: "OM", Name: "Oman"},
+	{Code: "PA", Name: "Panama"},
+	{Code: "PE", Name: "Peru"},
+	{Code: "PF", Name: "French Polynesia"},
+	{Code: "PG", Name: "Papua New Guinea"},
+	{Code: "PH", Name: "Philippines"},
+	{Code: "PK", Name: "Pakistan"},
+	{Code: "PL", Name: "Poland"},
+	{Code: "PM", Name: "Saint Pierre and Miquelon"},
+	{Code: "PN", Name: "Pitcairn"},
+	{Code: "PR", Name: "Puerto Rico"},
+	{Code: "PS", Name: "Palestine"},
+	{Code: "PT", Name: "Portugal"},
+	{Code: "PW", Name: "Palau"},
+	{Code: "PY", Name: "Paraguay"},
+	{Code: "QA", Name: "Qatar"},
+	{Code: "RE", Name: "Réunion"},
+	{Code: "RO", Name: "Romania"},
+	{Code: "RS", Name: "Serbia"},
+	{Code: "RU", Name: "Russia"},
+	{Code: "RW", Name: "Rwanda"},
+	{Code: "SA", Name: "Saudi Arabia"},
+	{Code: "SB", Name: "Solomon Islands"},
+	{Code: "SC", Name: "Seychelles"},
+	{Code: "SD", Name: "Sudan"},
+	{Code: "SE", Name: "Sweden"},
+	{Code: "SG", Name: "Singapore"},
+	{Code: "SH", Name: "Saint Helena, Ascension and Tristan da Cunha"},
+	{Code: "SI", Name: "Slovenia"},
+	{Code: "SJ", Name: "Svalbard and Jan Mayen"},
+	{Code: "SK", Name: "Slovakia"},
+	{Code: "SL", Name: "Sierra Leone"},
+	{Code: "SM", Name: "San Marino"},
+	{Code: "SN", Name: "Senegal"},
+	{Code: "SO", Name: "Somalia"},
+	{Code: "SR", Name: "Suriname"},
+	{Code: "SS", Name: "South Sudan"},
+	{Code: "ST", Name: "Sao Tome and Principe"},
+	{Code: "SV", Name: "El Salvador"},
+	{Code: "SX", Name: "Sint Maarten"},
+	{Code: "SY", Name: "Syria"},
+	{Code: "SZ", Name: "Eswatini"},
+	{Code: "TC", Name: "Turks and Caicos Islands"},
+	{Code: "TD", Name: "Chad"},
+	{Code: "TF", Name: "French Southern Territories"},
+	{Code: "TG", Name: "Togo"},
+	{Code: "TH", Name: "Thailand"},
+	{Code: "TJ", Name: "Tajikistan"},
+	{Code: "TK", Name: "Tokelau"},
+	{Code: "TL", Name: "Timor-Leste"},
+	{Code: "TM", Name: "Turkmenistan"},
+	{Code: "TN", Name: "Tunisia"},
+	{Code: "TO", Name: "Tonga"},
+	{Code: "TR", Name: "Turkey"},
+	{Code: "TT", Name: "Trinidad and Tobago"},
+	{Code: "TV", Name: "Tuvalu"},
+	{Code: "TW", Name: "Taiwan"},
+	{Code: "TZ", Name: "Tanzania"},
+	{Code: "UA", Name: "Ukraine"},
+	{Code: "UG", Name: "Uganda"},
+	{Code: "UM", Name: "United States Minor Outlying Islands"},
+	{Code: "US", Name: "United States"},
+	{Code: "UY", Name: "Uruguay"},
+	{Code: "UZ", Name: "Uzbekistan"},
+	{Code: "VA", Name: "Vatican City"},
+	{Code: "VC", Name: "Saint Vincent and the Grenadines"},
+	{Code: "VE", Name: "Venezuela"},
+	{Code: "VG", Name: "British Virgin Islands"},
+	{Code: "VI", Name: "U.S. Virgin Islands"},
+	{Code: "VN", Name: "Vietnam"},
+	{Code: "VU", Name: "Vanuatu"},
+	{Code: "WF", Name: "Wallis and Futuna"},
+	{Code: "WS", Name: "Samoa"},
+	{Code: "YE", Name: "Yemen"},
+	{Code: "YT", Name: "Mayotte"},
+	{Code: "ZA", Name: "South Africa"},
+	{Code: "ZM", Name: "Zambia"},
+	{Code: "ZW", Name: "Zimbabwe"},
+}
+
+// countryIndex maps codes, names and aliases (in lower case) to the alpha-2 code
+var countryIndex = buildCountryIndex()
+
+func buildCountryIndex() map[string]string {
+	index := make(map[string]string, len(Countries)*2+len(countryAliases))
+	for _, c := range Countries {
+		index[strings.ToLower(c.Code)] = c.Code
+		index[strings.ToLower(c.Name)] = c.Code
+	}
+	for alias, code := range countryAliases {
+		index[alias] = code
+	}
+	return index
+}
+
+// AgeGroupForAge returns the name of the age bucket an age falls into
+func AgeGroupForAge(age uint) string {
+	for _, g := range AgeGroups {
+		if g.MaxAge == nil || age <= *g.MaxAge {
+			return g.Name
+		}
+	}
+	return AgeGroups[len(AgeGroups)-1].Name
+}
+
+// NormalizeGender returns the canonical gender for a value or one of its aliases.
+// An empty value is kept as is, since the gender is optional.
+func NormalizeGender(gender string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(gender))
+	if value == "" {
+		return "", nil
+	}
+	for _, g := range Genders {
+		if value == strings.ToLower(g) {
+			return g, nil
+		}
+	}
+	if g, ok := genderAliases[value]; ok {
+		return g, nil
+	}
+	return "", errors.New("invalid gender")
+}
+
+// NormalizeCountry returns the ISO 3166-1 alpha-2 code for a code, country name or alias.
+// An empty value is kept as is, since the birth country is optional.
+func NormalizeCountry(country string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(country))
+	if value == "" {
+		return "", nil
+	}
+	if code, ok := countryIndex[value]; ok {
+		return code, nil
+	}
+	return "", errors.New("invalid birth country")
+}
+
+// NormalizeAudience normalises the controlled fields of an Audience in place,
+// deriving the age group from the age and replacing aliases with canonical values
+func NormalizeAudience(a *Audience) error {
+	gender, err := NormalizeGender(a.Gender)
+	if err != nil {
+		return err
+	}
+	country, err := NormalizeCountry(a.BirthCountry)
+	if err != nil {
+		return err
+	}
+
+	a.Gender = gender
+	a.BirthCountry = country
+	a.AgeGroup = AgeGroupForAge(a.Age)
+	return nil
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
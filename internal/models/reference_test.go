@@ -0,0 +1,77 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgeGroupForAge(t *testing.T) {
+	assert.Equal(t, models.AgeGroupTeen, models.AgeGroupForAge(0))
+	assert.Equal(t, models.AgeGroupTeen, models.AgeGroupForAge(17))
+	assert.Equal(t, models.AgeGroupYoungAdult, models.AgeGroupForAge(18))
+	assert.Equal(t, models.AgeGroupAdult, models.AgeGroupForAge(40))
+	assert.Equal(t, models.AgeGroupMiddleAged, models.AgeGroupForAge(41))
+	assert.Equal(t, models.AgeGroupSenior, models.AgeGroupForAge(66))
+	assert.Equal(t, models.AgeGroupSenior, models.AgeGroupForAge(120))
+}
+
+func TestNormalizeGender(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Male":       models.GenderMale,
+		" female ":   models.GenderFemale,
+		"m":          models.GenderMale,
+		"nonbinary":  models.GenderNonBinary,
+		"NON-BINARY": models.GenderNonBinary,
+		"":           "",
+	} {
+		gender, err := models.NormalizeGender(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, gender, input)
+	}
+
+	_, err := models.NormalizeGender("unknown")
+	assert.EqualError(t, err, "invalid gender")
+}
+
+func TestNormalizeCountry(t *testing.T) {
+	for input, expected := range map[string]string{
+		"US":                       "US",
+		"us":                       "US",
+		"USA":                      "US",
+		"United States":            "US",
+		"United States of America": "US",
+		"Greece":                   "GR",
+		"Czech Republic":           "CZ",
+		"Côte d'Ivoire":            "CI",
+		"":                         "",
+	} {
+		country, err := models.NormalizeCountry(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, country, input)
+	}
+
+	_, err := models.NormalizeCountry("Atlantis")
+	assert.EqualError(t, err, "invalid birth country")
+}
+
+func TestNormalizeAudience(t *testing.T) {
+	audience := models.Audience{Age: 40, AgeGroup: "25-45", Gender: "m", BirthCountry: "USA"}
+	assert.NoError(t, models.NormalizeAudience(&audience))
+	assert.Equal(t, models.AgeGroupAdult, audience.AgeGroup)
+	assert.Equal(t, models.GenderMale, audience.Gender)
+	assert.Equal(t, "US", audience.BirthCountry)
+
+	audience.BirthCountry = "Atlantis"
+	assert.Error(t, models.NormalizeAudience(&audience))
+}
+
+func TestCountriesAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range models.Countries {
+		assert.Len(t, c.Code, 2)
+		assert.False(t, seen[c.Code], c.Code)
+		seen[c.Code] = true
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// WebhookSubscription is an endpoint that receives the favourite events it subscribed to.
+// An empty Events list subscribes to every event type.
+type WebhookSubscription struct {
+	ID        string      `json:"id"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"-"` // Secret signs the payloads and is only returned when the subscription is created
+	Events    []EventType `json:"events"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// DeliveryStatus is a custom type for the states of a webhook delivery
+type DeliveryStatus string
+
+// Define constants for the delivery states
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryRetrying  DeliveryStatus = "retrying"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead"      // every attempt failed
+	DeliveryCancelled DeliveryStatus = "cancelled" // the user of the event was erased before it was delivered
+)
+
+// WebhookDelivery is the delivery of an event to a subscription, with every attempt made so far
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscriptionId"`
+	Event          FavoriteEvent    `json:"event"`
+	Status         DeliveryStatus   `json:"status"`
+	Attempts       []WebhookAttempt `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"nextAttemptAt,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
+
+// WebhookAttempt is a single request sent for a delivery.
+// StatusCode is zero when no response was received.
+type WebhookAttempt struct {
+	Number     int           `json:"number"`
+	Timestamp  time.Time     `json:"timestamp"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
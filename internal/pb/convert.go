@@ -0,0 +1,93 @@
+package pb
+
+import (
+	"errors"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// FromModel converts an asset of the models package, stored by value or by pointer, to its protobuf message
+func FromModel(asset models.Asset) (*Asset, error) {
+	switch a := asset.(type) {
+	case *models.Chart:
+		return FromModel(*a)
+	case *models.Insight:
+		return FromModel(*a)
+	case *models.Audience:
+		return FromModel(*a)
+	case models.Chart:
+		points := make([]*Point, len(a.DataPoints))
+		for i, p := range a.DataPoints {
+			points[i] = &Point{X: p.X, Y: p.Y}
+		}
+		return &Asset{Asset: &Asset_Chart{Chart: &Chart{
+			Id:          int64(a.ID),
+			Description: a.Description,
+			Title:       a.Title,
+			XAxesTitle:  a.XAxesTitle,
+			YAxesTitle:  a.YAxesTitle,
+			DataPoints:  points,
+		}}}, nil
+	case models.Insight:
+		return &Asset{Asset: &Asset_Insight{Insight: &Insight{
+			Id:          int64(a.ID),
+			Description: a.Description,
+			Text:        a.Text,
+		}}}, nil
+	case models.Audience:
+		return &Asset{Asset: &Asset_Audience{Audience: &Audience{
+			Id:                int64(a.ID),
+			Description:       a.Description,
+			Age:               uint32(a.Age),
+			AgeGroup:          a.AgeGroup,
+			Gender:            a.Gender,
+			BirthCountry:      a.BirthCountry,
+			HoursSpentOnMedia: uint32(a.HoursSpentOnMedia),
+			NumberOfPurchases: uint32(a.NumberOfPurchases),
+		}}}, nil
+	default:
+		return nil, errors.New("invalid asset type")
+	}
+}
+
+// ToModel converts the protobuf message of an asset to a pointer to the matching type of the models package,
+// the same form utils.DecodeAsset returns
+func (x *Asset) ToModel() (models.Asset, error) {
+	switch a := x.GetAsset().(type) {
+	case *Asset_Chart:
+		points := make([]models.Point, len(a.Chart.GetDataPoints()))
+		for i, p := range a.Chart.GetDataPoints() {
+			points[i] = models.Point{X: p.GetX(), Y: p.GetY()}
+		}
+		return &models.Chart{
+			ID:          int(a.Chart.GetId()),
+			Type:        models.ChartType,
+			Description: a.Chart.GetDescription(),
+			Title:       a.Chart.GetTitle(),
+			XAxesTitle:  a.Chart.GetXAxesTitle(),
+			YAxesTitle:  a.Chart.GetYAxesTitle(),
+			DataPoints:  points,
+		}, nil
+	case *Asset_Insight:
+		return &models.Insight{
+			ID:          int(a.Insight.GetId()),
+			Type:        models.InsightType,
+			Description: a.Insight.GetDescription(),
+			Text:        a.Insight.GetText(),
+		}, nil
+	case *Asset_Audience:
+		return &models.Audience{
+			ID:                int(a.Audience.GetId()),
+			Type:              models.AudienceType,
+			Description:       a.Audience.GetDescription(),
+			Age:               uint(a.Audience.GetAge()),
+			AgeGroup:          a.Audience.GetAgeGroup(),
+			Gender:            a.Audience.GetGender(),
+			BirthCountry:      a.Audience.GetBirthCountry(),
+			HoursSpentOnMedia: uint(a.Audience.GetHoursSpentOnMedia()),
+			NumberOfPurchases: uint(a.Audience.GetNumberOfPurchases()),
+		}, nil
+	default:
+		return nil, errors.New("invalid asset type")
+	}
+}
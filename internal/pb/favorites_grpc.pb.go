@@ -0,0 +1,296 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v5.27.3
+// source: favorites.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FavoritesService_GetUserFavorite_FullMethodName    = "/favorites.v1.FavoritesService/GetUserFavorite"
+	FavoritesService_ListUserFavorites_FullMethodName  = "/favorites.v1.FavoritesService/ListUserFavorites"
+	FavoritesService_AddUserFavorite_FullMethodName    = "/favorites.v1.FavoritesService/AddUserFavorite"
+	FavoritesService_EditUserFavorite_FullMethodName   = "/favorites.v1.FavoritesService/EditUserFavorite"
+	FavoritesService_DeleteUserFavorite_FullMethodName = "/favorites.v1.FavoritesService/DeleteUserFavorite"
+)
+
+// FavoritesServiceClient is the client API for FavoritesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FavoritesServiceClient interface {
+	// GetUserFavorite returns a single asset from the user's favourites.
+	GetUserFavorite(ctx context.Context, in *GetUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error)
+	// ListUserFavorites streams the user's favourites, ordered by asset id.
+	ListUserFavorites(ctx context.Context, in *ListUserFavoritesRequest, opts ...grpc.CallOption) (FavoritesService_ListUserFavoritesClient, error)
+	// AddUserFavorite adds an asset to the user's favourites and returns the stored asset.
+	AddUserFavorite(ctx context.Context, in *AddUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error)
+	// EditUserFavorite replaces an asset of the user's favourites and returns the stored asset.
+	EditUserFavorite(ctx context.Context, in *EditUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error)
+	// DeleteUserFavorite deletes an asset from the user's favourites.
+	DeleteUserFavorite(ctx context.Context, in *DeleteUserFavoriteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type favoritesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFavoritesServiceClient(cc grpc.ClientConnInterface) FavoritesServiceClient {
+	return &favoritesServiceClient{cc}
+}
+
+func (c *favoritesServiceClient) GetUserFavorite(ctx context.Context, in *GetUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error) {
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, FavoritesService_GetUserFavorite_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoritesServiceClient) ListUserFavorites(ctx context.Context, in *ListUserFavoritesRequest, opts ...grpc.CallOption) (FavoritesService_ListUserFavoritesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FavoritesService_ServiceDesc.Streams[0], FavoritesService_ListUserFavorites_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &favoritesServiceListUserFavoritesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FavoritesService_ListUserFavoritesClient interface {
+	Recv() (*Asset, error)
+	grpc.ClientStream
+}
+
+type favoritesServiceListUserFavoritesClient struct {
+	grpc.ClientStream
+}
+
+func (x *favoritesServiceListUserFavoritesClient) Recv() (*Asset, error) {
+	m := new(Asset)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *favoritesServiceClient) AddUserFavorite(ctx context.Context, in *AddUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error) {
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, FavoritesService_AddUserFavorite_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoritesServiceClient) EditUserFavorite(ctx context.Context, in *EditUserFavoriteRequest, opts ...grpc.CallOption) (*Asset, error) {
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, FavoritesService_EditUserFavorite_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoritesServiceClient) DeleteUserFavorite(ctx context.Context, in *DeleteUserFavoriteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FavoritesService_DeleteUserFavorite_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FavoritesServiceServer is the server API for FavoritesService service.
+// All implementations must embed UnimplementedFavoritesServiceServer
+// for forward compatibility
+type FavoritesServiceServer interface {
+	// GetUserFavorite returns a single asset from the user's favourites.
+	GetUserFavorite(context.Context, *GetUserFavoriteRequest) (*Asset, error)
+	// ListUserFavorites streams the user's favourites, ordered by asset id.
+	ListUserFavorites(*ListUserFavoritesRequest, FavoritesService_ListUserFavoritesServer) error
+	// AddUserFavorite adds an asset to the user's favourites and returns the stored asset.
+	AddUserFavorite(context.Context, *AddUserFavoriteRequest) (*Asset, error)
+	// EditUserFavorite replaces an asset of the user's favourites and returns the stored asset.
+	EditUserFavorite(context.Context, *EditUserFavoriteRequest) (*Asset, error)
+	// DeleteUserFavorite deletes an asset from the user's favourites.
+	DeleteUserFavorite(context.Context, *DeleteUserFavoriteRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedFavoritesServiceServer()
+}
+
+// UnimplementedFavoritesServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFavoritesServiceServer struct {
+}
+
+func (UnimplementedFavoritesServiceServer) GetUserFavorite(context.Context, *GetUserFavoriteRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserFavorite not implemented")
+}
+func (UnimplementedFavoritesServiceServer) ListUserFavorites(*ListUserFavoritesRequest, FavoritesService_ListUserFavoritesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListUserFavorites not implemented")
+}
+func (UnimplementedFavoritesServiceServer) AddUserFavorite(context.Context, *AddUserFavoriteRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddUserFavorite not implemented")
+}
+func (UnimplementedFavoritesServiceServer) EditUserFavorite(context.Context, *EditUserFavoriteRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditUserFavorite not implemented")
+}
+func (UnimplementedFavoritesServiceServer) DeleteUserFavorite(context.Context, *DeleteUserFavoriteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserFavorite not implemented")
+}
+func (UnimplementedFavoritesServiceServer) mustEmbedUnimplementedFavoritesServiceServer() {}
+
+// UnsafeFavoritesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FavoritesServiceServer will
+// result in compilation errors.
+type UnsafeFavoritesServiceServer interface {
+	mustEmbedUnimplementedFavoritesServiceServer()
+}
+
+func RegisterFavoritesServiceServer(s grpc.ServiceRegistrar, srv FavoritesServiceServer) {
+	s.RegisterService(&FavoritesService_ServiceDesc, srv)
+}
+
+func _FavoritesService_GetUserFavorite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserFavoriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoritesServiceServer).GetUserFavorite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoritesService_GetUserFavorite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoritesServiceServer).GetUserFavorite(ctx, req.(*GetUserFavoriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoritesService_ListUserFavorites_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListUserFavoritesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FavoritesServiceServer).ListUserFavorites(m, &favoritesServiceListUserFavoritesServer{stream})
+}
+
+type FavoritesService_ListUserFavoritesServer interface {
+	Send(*Asset) error
+	grpc.ServerStream
+}
+
+type favoritesServiceListUserFavoritesServer struct {
+	grpc.ServerStream
+}
+
+func (x *favoritesServiceListUserFavoritesServer) Send(m *Asset) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FavoritesService_AddUserFavorite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddUserFavoriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoritesServiceServer).AddUserFavorite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoritesService_AddUserFavorite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoritesServiceServer).AddUserFavorite(ctx, req.(*AddUserFavoriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoritesService_EditUserFavorite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditUserFavoriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoritesServiceServer).EditUserFavorite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoritesService_EditUserFavorite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoritesServiceServer).EditUserFavorite(ctx, req.(*EditUserFavoriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoritesService_DeleteUserFavorite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserFavoriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoritesServiceServer).DeleteUserFavorite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoritesService_DeleteUserFavorite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoritesServiceServer).DeleteUserFavorite(ctx, req.(*DeleteUserFavoriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FavoritesService_ServiceDesc is the grpc.ServiceDesc for FavoritesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FavoritesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "favorites.v1.FavoritesService",
+	HandlerType: (*FavoritesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserFavorite",
+			Handler:    _FavoritesService_GetUserFavorite_Handler,
+		},
+		{
+			MethodName: "AddUserFavorite",
+			Handler:    _FavoritesService_AddUserFavorite_Handler,
+		},
+		{
+			MethodName: "EditUserFavorite",
+			Handler:    _FavoritesService_EditUserFavorite_Handler,
+		},
+		{
+			MethodName: "DeleteUserFavorite",
+			Handler:    _FavoritesService_DeleteUserFavorite_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListUserFavorites",
+			Handler:       _FavoritesService_ListUserFavorites_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "favorites.proto",
+}
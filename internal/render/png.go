@@ -0,0 +1,226 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// anchor defines how a text is positioned horizontally relative to its coordinates
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// canvas wraps an RGBA image with the drawing primitives needed for charts
+type canvas struct {
+	img *image.RGBA
+}
+
+// PNG renders the chart as a PNG image and writes it to w
+func PNG(w io.Writer, chart models.Chart, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	l := newLayout(chart, opts)
+
+	c := canvas{img: image.NewRGBA(image.Rect(0, 0, l.Width, l.Height))}
+	draw.Draw(c.img, c.img.Bounds(), &image.Uniform{parseColor(colorBackground)}, image.Point{}, draw.Src)
+
+	axisColor := parseColor(colorAxis)
+	gridColor := parseColor(colorGrid)
+	textColor := parseColor(colorText)
+	seriesColor := parseColor(colorSeries)
+
+	// Grid lines and tick labels
+	for _, t := range l.X.Ticks {
+		x := l.mapX(t)
+		c.line(x, l.Plot.Y0, x, l.Plot.Y1, 1, gridColor)
+		c.line(x, l.Plot.Y1, x, l.Plot.Y1+5, 1, axisColor)
+		c.text(x, l.Plot.Y1+14, formatTick(t), 1, anchorMiddle, textColor)
+	}
+	for _, t := range l.Y.Ticks {
+		y := l.mapY(t)
+		c.line(l.Plot.X0, y, l.Plot.X1, y, 1, gridColor)
+		c.line(l.Plot.X0-5, y, l.Plot.X0, y, 1, axisColor)
+		c.text(l.Plot.X0-8, y, formatTick(t), 1, anchorEnd, textColor)
+	}
+
+	// Data series
+	switch l.Style {
+	case StyleLine:
+		for i := 1; i < len(l.Points); i++ {
+			p, q := l.Points[i-1], l.Points[i]
+			c.line(l.mapX(float64(p.X)), l.mapY(float64(p.Y)), l.mapX(float64(q.X)), l.mapY(float64(q.Y)), 2, seriesColor)
+		}
+		for _, p := range l.Points {
+			c.circle(l.mapX(float64(p.X)), l.mapY(float64(p.Y)), 3, seriesColor)
+		}
+	case StyleScatter:
+		for _, p := range l.Points {
+			c.circle(l.mapX(float64(p.X)), l.mapY(float64(p.Y)), 4, seriesColor)
+		}
+	case StyleBar:
+		base := l.mapY(0)
+		width := l.barWidth()
+		for _, p := range l.Points {
+			x, y := l.mapX(float64(p.X)), l.mapY(float64(p.Y))
+			c.rect(x-width/2, math.Min(y, base), x+width/2, math.Max(y, base), seriesColor)
+		}
+	}
+
+	// Axes
+	c.line(l.Plot.X0, l.Plot.Y1, l.Plot.X1, l.Plot.Y1, 2, axisColor)
+	c.line(l.Plot.X0, l.Plot.Y0, l.Plot.X0, l.Plot.Y1, 2, axisColor)
+
+	// Titles
+	c.text(float64(l.Width)/2, marginTop/2, l.Title, 2, anchorMiddle, textColor)
+	c.text((l.Plot.X0+l.Plot.X1)/2, float64(l.Height)-16, l.XTitle, 1, anchorMiddle, textColor)
+	c.verticalText(16, (l.Plot.Y0+l.Plot.Y1)/2, l.YTitle, 1, textColor)
+
+	return png.Encode(w, c.img)
+}
+
+// set colors a single pixel, ignoring coordinates outside the image
+func (c canvas) set(x, y int, col color.RGBA) {
+	if image.Pt(x, y).In(c.img.Rect) {
+		c.img.SetRGBA(x, y, col)
+	}
+}
+
+// line draws a straight line of the given thickness using Bresenham's algorithm
+func (c canvas) line(x0f, y0f, x1f, y1f float64, thickness int, col color.RGBA) {
+	x0, y0 := int(math.Round(x0f)), int(math.Round(y0f))
+	x1, y1 := int(math.Round(x1f)), int(math.Round(y1f))
+
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		for i := 0; i < thickness; i++ {
+			for j := 0; j < thickness; j++ {
+				c.set(x0+i-thickness/2, y0+j-thickness/2, col)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// circle draws a filled circle centered at (cxf, cyf)
+func (c canvas) circle(cxf, cyf float64, r int, col color.RGBA) {
+	cx, cy := int(math.Round(cxf)), int(math.Round(cyf))
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				c.set(cx+x, cy+y, col)
+			}
+		}
+	}
+}
+
+// rect draws a filled rectangle between the two corners
+func (c canvas) rect(x0, y0, x1, y1 float64, col color.RGBA) {
+	r := image.Rect(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)))
+	draw.Draw(c.img, r.Intersect(c.img.Rect), &image.Uniform{col}, image.Point{}, draw.Src)
+}
+
+// text draws a string with the built-in bitmap font, vertically centered at y
+func (c canvas) text(x, y float64, s string, scale int, a anchor, col color.RGBA) {
+	width := textWidth(s, scale)
+	left := int(math.Round(x))
+	switch a {
+	case anchorMiddle:
+		left -= width / 2
+	case anchorEnd:
+		left -= width
+	}
+	top := int(math.Round(y)) - glyphHeight*scale/2
+
+	for i, r := range []rune(s) {
+		glyph := lookupGlyph(r)
+		for gx := 0; gx < glyphWidth; gx++ {
+			for gy := 0; gy < glyphHeight; gy++ {
+				if glyph[gx]&(1<<gy) == 0 {
+					continue
+				}
+				for sx := 0; sx < scale; sx++ {
+					for sy := 0; sy < scale; sy++ {
+						c.set(left+(i*glyphAdvance+gx)*scale+sx, top+gy*scale+sy, col)
+					}
+				}
+			}
+		}
+	}
+}
+
+// verticalText draws a string rotated 90 degrees counter clockwise, centered at (x, y)
+func (c canvas) verticalText(x, y float64, s string, scale int, col color.RGBA) {
+	height := textWidth(s, scale)
+	bottom := int(math.Round(y)) + height/2
+	left := int(math.Round(x)) - glyphHeight*scale/2
+
+	for i, r := range []rune(s) {
+		glyph := lookupGlyph(r)
+		for gx := 0; gx < glyphWidth; gx++ {
+			for gy := 0; gy < glyphHeight; gy++ {
+				if glyph[gx]&(1<<gy) == 0 {
+					continue
+				}
+				for sx := 0; sx < scale; sx++ {
+					for sy := 0; sy < scale; sy++ {
+						c.set(left+gy*scale+sx, bottom-(i*glyphAdvance+gx)*scale-sy, col)
+					}
+				}
+			}
+		}
+	}
+}
+
+// textWidth returns the width in pixels of a string drawn with the bitmap font
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * scale
+}
+
+// parseColor converts a #rrggbb hex string into a color
+func parseColor(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
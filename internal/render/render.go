@@ -0,0 +1,211 @@
+package render
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// Style is the way the data points of a chart are drawn
+type Style string
+
+// Define constants for the supported chart styles
+const (
+	StyleLine    Style = "line"
+	StyleScatter Style = "scatter"
+	StyleBar     Style = "bar"
+)
+
+// Limits for the size of the rendered image in pixels
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 400
+	MinSize       = 200
+	MaxSize       = 4000
+)
+
+// Margins around the plot area, leaving space for the title, tick labels and axis titles
+const (
+	marginTop    = 40
+	marginRight  = 24
+	marginBottom = 56
+	marginLeft   = 72
+)
+
+// Options defines how a chart is rendered
+type Options struct {
+	Width  int
+	Height int
+	Style  Style
+}
+
+// DefaultOptions returns the options used when the client does not specify any
+func DefaultOptions() Options {
+	return Options{
+		Width:  DefaultWidth,
+		Height: DefaultHeight,
+		Style:  StyleLine,
+	}
+}
+
+// ParseStyle converts a string into a Style, defaulting to a line chart when empty
+func ParseStyle(s string) (Style, error) {
+	switch Style(s) {
+	case "":
+		return StyleLine, nil
+	case StyleLine, StyleScatter, StyleBar:
+		return Style(s), nil
+	default:
+		return "", errors.New("invalid chart style")
+	}
+}
+
+// Validate checks that the options can be used for rendering
+func (o Options) Validate() error {
+	if o.Width < MinSize || o.Width > MaxSize || o.Height < MinSize || o.Height > MaxSize {
+		return errors.New("invalid chart size")
+	}
+	if _, err := ParseStyle(string(o.Style)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// rect is an axis aligned rectangle in image coordinates
+type rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// axis holds the data range shown on an axis and the tick values along it
+type axis struct {
+	Min, Max float64
+	Ticks    []float64
+}
+
+// layout contains everything the SVG and PNG renderers need to draw a chart,
+// so both formats always produce the same geometry
+type layout struct {
+	Width, Height int
+	Style         Style
+	Title         string
+	XTitle        string
+	YTitle        string
+	Plot          rect
+	X, Y          axis
+	Points        []models.Point
+}
+
+// newLayout computes the plot area, axis ranges and ticks for a chart
+func newLayout(chart models.Chart, opts Options) layout {
+	points := make([]models.Point, len(chart.DataPoints))
+	copy(points, chart.DataPoints)
+
+	// Lines and bars are drawn from left to right, so the points are sorted by X
+	if opts.Style != StyleScatter {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].X < points[j].X })
+	}
+
+	xMin, xMax := math.Inf(1), math.Inf(-1)
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		xMin, xMax = math.Min(xMin, float64(p.X)), math.Max(xMax, float64(p.X))
+		yMin, yMax = math.Min(yMin, float64(p.Y)), math.Max(yMax, float64(p.Y))
+	}
+	if len(points) == 0 {
+		xMin, xMax, yMin, yMax = 0, 1, 0, 1
+	}
+
+	// Bars grow from zero, so zero must always be visible on the Y axis,
+	// and the X range is padded by half a bar slot so the outer bars are not clipped
+	if opts.Style == StyleBar {
+		yMin, yMax = math.Min(yMin, 0), math.Max(yMax, 0)
+		pad := 0.5
+		if len(points) > 1 {
+			pad = (xMax - xMin) / float64(len(points)-1) / 2
+		}
+		xMin, xMax = xMin-pad, xMax+pad
+	}
+
+	return layout{
+		Width:  opts.Width,
+		Height: opts.Height,
+		Style:  opts.Style,
+		Title:  chart.Title,
+		XTitle: chart.XAxesTitle,
+		YTitle: chart.YAxesTitle,
+		Plot: rect{
+			X0: marginLeft,
+			Y0: marginTop,
+			X1: float64(opts.Width - marginRight),
+			Y1: float64(opts.Height - marginBottom),
+		},
+		X:      newAxis(xMin, xMax),
+		Y:      newAxis(yMin, yMax),
+		Points: points,
+	}
+}
+
+// newAxis expands the [min, max] range to "nice" tick boundaries
+func newAxis(min, max float64) axis {
+	if min == max {
+		min, max = min-1, max+1
+	}
+
+	step := niceNumber((max - min) / 5)
+	lo := math.Floor(min/step) * step
+	hi := math.Ceil(max/step) * step
+
+	var ticks []float64
+	for v := lo; v <= hi+step/2; v += step {
+		// Round away floating point noise so labels like 0.30000000000000004 do not appear
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+
+	return axis{Min: lo, Max: hi, Ticks: ticks}
+}
+
+// niceNumber returns a number of the form 1, 2 or 5 times a power of ten close to x
+func niceNumber(x float64) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+
+	var nf float64
+	switch {
+	case f < 1.5:
+		nf = 1
+	case f < 3:
+		nf = 2
+	case f < 7:
+		nf = 5
+	default:
+		nf = 10
+	}
+	return nf * math.Pow(10, exp)
+}
+
+// mapX converts a data X value into an image X coordinate
+func (l layout) mapX(v float64) float64 {
+	return l.Plot.X0 + (v-l.X.Min)/(l.X.Max-l.X.Min)*(l.Plot.X1-l.Plot.X0)
+}
+
+// mapY converts a data Y value into an image Y coordinate, which grows downwards
+func (l layout) mapY(v float64) float64 {
+	return l.Plot.Y1 - (v-l.Y.Min)/(l.Y.Max-l.Y.Min)*(l.Plot.Y1-l.Plot.Y0)
+}
+
+// barWidth returns the width of a single bar so that all bars fit in the plot area
+func (l layout) barWidth() float64 {
+	n := len(l.Points)
+	if n == 0 {
+		return 0
+	}
+	return math.Max(1, (l.Plot.X1-l.Plot.X0)/float64(n)*0.6)
+}
+
+// formatTick formats a tick value without trailing zeros
+func formatTick(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
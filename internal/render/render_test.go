@@ -0,0 +1,113 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/render"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run `go test ./internal/render -update` to regenerate the golden files after an intentional change
+var update = flag.Bool("update", false, "update golden files")
+
+var sampleChart = models.Chart{
+	ID:          1,
+	Type:        models.ChartType,
+	Description: "Sample Chart",
+	Title:       "Weekly Active Users",
+	XAxesTitle:  "Week",
+	YAxesTitle:  "Users (k)",
+	DataPoints: []models.Point{
+		{X: 1, Y: 12.5},
+		{X: 3, Y: 18},
+		{X: 2, Y: 9.25},
+		{X: 4, Y: -3},
+		{X: 5, Y: 22},
+	},
+}
+
+// checkGolden compares the rendered output with the golden file, or rewrites it when -update is set
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match golden file %s (run with -update to accept the change)", path)
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	emptyChart := models.Chart{ID: 2, Type: models.ChartType, Title: "Empty Chart", XAxesTitle: "X", YAxesTitle: "Y"}
+
+	testCases := []struct {
+		name  string
+		chart models.Chart
+		style render.Style
+	}{
+		{name: "line", chart: sampleChart, style: render.StyleLine},
+		{name: "scatter", chart: sampleChart, style: render.StyleScatter},
+		{name: "bar", chart: sampleChart, style: render.StyleBar},
+		{name: "empty", chart: emptyChart, style: render.StyleLine},
+	}
+
+	for _, tc := range testCases {
+		opts := render.DefaultOptions()
+		opts.Style = tc.style
+
+		t.Run(tc.name+"SVG", func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, render.SVG(&buf, tc.chart, opts))
+			checkGolden(t, tc.name+".golden.svg", buf.Bytes())
+		})
+
+		t.Run(tc.name+"PNG", func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, render.PNG(&buf, tc.chart, opts))
+			checkGolden(t, tc.name+".golden.png", buf.Bytes())
+		})
+	}
+}
+
+func TestRenderInvalidOptions(t *testing.T) {
+	var buf bytes.Buffer
+
+	// Test size out of range
+	opts := render.DefaultOptions()
+	opts.Width = 10
+	assert.EqualError(t, render.SVG(&buf, sampleChart, opts), "invalid chart size")
+	assert.EqualError(t, render.PNG(&buf, sampleChart, opts), "invalid chart size")
+
+	// Test unknown style
+	opts = render.DefaultOptions()
+	opts.Style = "pie"
+	assert.EqualError(t, render.SVG(&buf, sampleChart, opts), "invalid chart style")
+}
+
+func TestParseStyle(t *testing.T) {
+	style, err := render.ParseStyle("")
+	assert.NoError(t, err)
+	assert.Equal(t, render.StyleLine, style)
+
+	style, err = render.ParseStyle("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, render.StyleBar, style)
+
+	_, err = render.ParseStyle("pie")
+	assert.Error(t, err)
+}
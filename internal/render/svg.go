@@ -0,0 +1,103 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// Colors shared by the SVG and PNG renderers
+const (
+	colorBackground = "#ffffff"
+	colorAxis       = "#333333"
+	colorGrid       = "#e0e0e0"
+	colorText       = "#222222"
+	colorSeries     = "#1f77b4"
+)
+
+// SVG renders the chart as an SVG document and writes it to w
+func SVG(w io.Writer, chart models.Chart, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	l := newLayout(chart, opts)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		l.Width, l.Height, l.Width, l.Height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", colorBackground)
+
+	// Grid lines and tick labels
+	for _, t := range l.X.Ticks {
+		x := l.mapX(t)
+		fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n", x, l.Plot.Y0, x, l.Plot.Y1, colorGrid)
+		fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n", x, l.Plot.Y1, x, l.Plot.Y1+5, colorAxis)
+		fmt.Fprintf(bw, `<text x="%.2f" y="%.2f" font-size="11" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			x, l.Plot.Y1+18, colorText, escape(formatTick(t)))
+	}
+	for _, t := range l.Y.Ticks {
+		y := l.mapY(t)
+		fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n", l.Plot.X0, y, l.Plot.X1, y, colorGrid)
+		fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n", l.Plot.X0-5, y, l.Plot.X0, y, colorAxis)
+		fmt.Fprintf(bw, `<text x="%.2f" y="%.2f" font-size="11" text-anchor="end" dominant-baseline="middle" fill="%s">%s</text>`+"\n",
+			l.Plot.X0-8, y, colorText, escape(formatTick(t)))
+	}
+
+	// Data series
+	switch l.Style {
+	case StyleLine:
+		if len(l.Points) > 0 {
+			coords := make([]string, len(l.Points))
+			for i, p := range l.Points {
+				coords[i] = fmt.Sprintf("%.2f,%.2f", l.mapX(float64(p.X)), l.mapY(float64(p.Y)))
+			}
+			fmt.Fprintf(bw, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(coords, " "), colorSeries)
+		}
+		for _, p := range l.Points {
+			fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="3" fill="%s"/>`+"\n", l.mapX(float64(p.X)), l.mapY(float64(p.Y)), colorSeries)
+		}
+	case StyleScatter:
+		for _, p := range l.Points {
+			fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="4" fill="%s"/>`+"\n", l.mapX(float64(p.X)), l.mapY(float64(p.Y)), colorSeries)
+		}
+	case StyleBar:
+		base := l.mapY(0)
+		width := l.barWidth()
+		for _, p := range l.Points {
+			x, y := l.mapX(float64(p.X)), l.mapY(float64(p.Y))
+			top, height := y, base-y
+			if height < 0 {
+				top, height = base, -height
+			}
+			fmt.Fprintf(bw, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n", x-width/2, top, width, height, colorSeries)
+		}
+	}
+
+	// Axes
+	fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="1.5"/>`+"\n",
+		l.Plot.X0, l.Plot.Y1, l.Plot.X1, l.Plot.Y1, colorAxis)
+	fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="1.5"/>`+"\n",
+		l.Plot.X0, l.Plot.Y0, l.Plot.X0, l.Plot.Y1, colorAxis)
+
+	// Titles
+	fmt.Fprintf(bw, `<text x="%.2f" y="%d" font-size="16" font-weight="bold" text-anchor="middle" fill="%s">%s</text>`+"\n",
+		float64(l.Width)/2, marginTop/2+6, colorText, escape(l.Title))
+	fmt.Fprintf(bw, `<text x="%.2f" y="%d" font-size="12" text-anchor="middle" fill="%s">%s</text>`+"\n",
+		(l.Plot.X0+l.Plot.X1)/2, l.Height-12, colorText, escape(l.XTitle))
+	fmt.Fprintf(bw, `<text x="16" y="%.2f" font-size="12" text-anchor="middle" fill="%s" transform="rotate(-90 16 %.2f)">%s</text>`+"\n",
+		(l.Plot.Y0+l.Plot.Y1)/2, colorText, (l.Plot.Y0+l.Plot.Y1)/2, escape(l.YTitle))
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// escape escapes text so it can be safely embedded in the SVG document
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"crypto/rand"
+)
+
+// auditKeySize is the size of the keys of the users, for AES-256
+const auditKeySize = 32
+
+// AuditKeyStore defines the methods that any type of storage of the keys encrypting the values of the audit log must implement.
+// Every user has a key of their own, so deleting it erases the values recorded for the user from the append-only log.
+type AuditKeyStore interface {
+	// GetAuditKey returns the key of a user and true, creating the key if the user has none and create is set,
+	// or false if the user has no key
+	GetAuditKey(userID int, create bool) ([]byte, bool, error)
+	// DeleteAuditKey deletes the key of a user, returning false if the user had none
+	DeleteAuditKey(userID int) (bool, error)
+}
+
+// newAuditKey returns a new random key
+func newAuditKey() ([]byte, error) {
+	key := make([]byte, auditKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAuditKeyStore tests creating, getting and deleting the keys of an empty store
+func testAuditKeyStore(t *testing.T, store repository.AuditKeyStore) {
+	// Test a key is only created when asked to
+	_, ok, err := store.GetAuditKey(1, false)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	key, ok, err := store.GetAuditKey(1, true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, key, 32)
+
+	// Test every user gets their own key, kept between calls
+	again, _, _ := store.GetAuditKey(1, true)
+	assert.Equal(t, key, again)
+	other, _, _ := store.GetAuditKey(2, true)
+	assert.NotEqual(t, key, other)
+
+	// Test deleted keys are gone, and replaced by a new key
+	deleted, err := store.DeleteAuditKey(1)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, ok, _ = store.GetAuditKey(1, false)
+	assert.False(t, ok)
+	deleted, _ = store.DeleteAuditKey(1)
+	assert.False(t, deleted)
+	replaced, _, _ := store.GetAuditKey(1, true)
+	assert.NotEqual(t, key, replaced)
+}
+
+func TestInMemoryAuditKeyStore(t *testing.T) {
+	testAuditKeyStore(t, repository.NewInMemoryAuditKeyStore())
+}
+
+func TestFileAuditKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit-keys.json")
+	store, err := repository.OpenFileAuditKeyStore(path)
+	assert.NoError(t, err)
+	testAuditKeyStore(t, store)
+
+	// Test the keys are read again from the file, readable only by its owner, without the deleted ones
+	key, _, _ := store.GetAuditKey(2, false)
+	_, err = store.DeleteAuditKey(1)
+	assert.NoError(t, err)
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	store, err = repository.OpenFileAuditKeyStore(path)
+	assert.NoError(t, err)
+	reopened, ok, _ := store.GetAuditKey(2, false)
+	assert.True(t, ok)
+	assert.Equal(t, key, reopened)
+	_, ok, _ = store.GetAuditKey(1, false)
+	assert.False(t, ok)
+
+	// Test invalid files are refused
+	assert.NoError(t, os.WriteFile(path, []byte(`{"1":"c2hvcnQ="}`), 0o600))
+	_, err = repository.OpenFileAuditKeyStore(path)
+	assert.EqualError(t, err, "invalid audit key file")
+}
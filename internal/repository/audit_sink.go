@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// AuditSink defines the methods that any type of audit log storage must implement.
+// Records are only ever appended, never changed or removed.
+type AuditSink interface {
+	AppendAuditRecord(record models.AuditRecord) error
+	GetAuditRecords(filter AuditFilter) (models.AuditPage, error)
+}
+
+// AuditFilter selects audit records. Zero values match every record.
+type AuditFilter struct {
+	After  string    // cursor of a previous page, selecting only the records appended after it
+	From   time.Time // records at or after From
+	To     time.Time // records before To
+	Actor  string
+	UserID int
+	Limit  int // largest number of records returned, the oldest ones first
+}
+
+// Match reports whether a record is selected by the filter
+func (filter AuditFilter) Match(record models.AuditRecord) bool {
+	switch {
+	case !filter.From.IsZero() && record.Timestamp.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !record.Timestamp.Before(filter.To):
+		return false
+	case filter.Actor != "" && record.Actor != filter.Actor:
+		return false
+	case filter.UserID != 0 && record.UserID != filter.UserID:
+		return false
+	}
+	return true
+}
+
+// full reports whether the records already selected reach the limit of the filter
+func (filter AuditFilter) full(records []models.AuditRecord) bool {
+	return filter.Limit > 0 && len(records) >= filter.Limit
+}
+
+// encodeAuditCursor returns the opaque cursor of a position in an audit log
+func encodeAuditCursor(position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("audit:" + strconv.FormatInt(position, 10)))
+}
+
+// decodeAuditCursor returns the position a cursor points to, the start of the log for an empty cursor
+func decodeAuditCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), "audit:") {
+		return 0, errors.New("invalid cursor")
+	}
+	position, err := strconv.ParseInt(strings.TrimPrefix(string(data), "audit:"), 10, 64)
+	if err != nil || position < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return position, nil
+}
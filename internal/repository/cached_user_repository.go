@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// CachedUserRepository is a UserRepository caching the favourites of the users read from another one.
+// Favourites are read through an in-process LRU, then a remote cache shared by the instances, then the repository,
+// and every change invalidates the favourites of the user in both caches. Either cache is optional.
+// Changes made by other instances are seen once the in-process copy expires, so LocalTTL should be short,
+// and a read racing with a change of another instance can keep stale favourites in the remote cache up to RemoteTTL.
+// Errors of the remote cache never fail a request, the repository is used instead.
+type CachedUserRepository struct {
+	Next      UserRepository
+	Local     *cache.LRU  // nil disables the in-process cache
+	Remote    cache.Cache // nil disables the remote cache
+	LocalTTL  time.Duration
+	RemoteTTL time.Duration
+	KeyPrefix string // prefix of the keys of the remote cache, shared with the other instances
+
+	generation uint64     // generation counts the changes, so reads that raced with a change are not cached
+	mu         sync.Mutex // mu protects the generation and orders the invalidations with the fills of the local cache
+}
+
+// NewCachedUserRepository creates a new CachedUserRepository in front of next
+func NewCachedUserRepository(next UserRepository, local *cache.LRU, remote cache.Cache) *CachedUserRepository {
+	return &CachedUserRepository{
+		Next:      next,
+		Local:     local,
+		Remote:    remote,
+		LocalTTL:  time.Minute,
+		RemoteTTL: 10 * time.Minute,
+		KeyPrefix: "favorites:",
+	}
+}
+
+// GetUserIDs returns the IDs of all users from the repository, they are not cached
+func (repo *CachedUserRepository) GetUserIDs(ctx context.Context) ([]int, error) {
+	return repo.Next.GetUserIDs(ctx)
+}
+
+// GetUserFavorites returns a copy of the user's favourites from the first cache holding them, or from the repository,
+// filling the caches. Errors, like an unknown user, are not cached.
+func (repo *CachedUserRepository) GetUserFavorites(ctx context.Context, userID int) (favorites map[int]models.Asset, err error) {
+	ctx, span := telemetry.Start(ctx, "CachedUserRepository.GetUserFavorites", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	key := repo.key(userID)
+	if repo.Local != nil {
+		if cached, ok := repo.Local.Get(key); ok {
+			span.SetAttributes(telemetry.CacheResultKey.String("local"))
+			return copyFavorites(cached.(map[int]models.Asset)), nil
+		}
+	}
+
+	generation := repo.currentGeneration()
+	if repo.Remote != nil {
+		if favorites, ok := repo.getRemote(ctx, key); ok {
+			span.SetAttributes(telemetry.CacheResultKey.String("remote"))
+			repo.fillLocal(key, favorites, generation)
+			return copyFavorites(favorites), nil
+		}
+	}
+
+	span.SetAttributes(telemetry.CacheResultKey.String("miss"))
+	favorites, err = repo.Next.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	favorites = copyFavorites(favorites)
+
+	if repo.Remote != nil && repo.currentGeneration() == generation {
+		if data, err := encodeFavorites(favorites); err == nil {
+			if err := repo.Remote.Set(ctx, key, data, repo.RemoteTTL); err != nil {
+				span.RecordError(err)
+			}
+		}
+	}
+	repo.fillLocal(key, favorites, generation)
+	return copyFavorites(favorites), nil
+}
+
+// AddUserFavorite adds an asset to the user's favourites in the repository and invalidates the cached ones
+func (repo *CachedUserRepository) AddUserFavorite(ctx context.Context, userID int, asset models.Asset) error {
+	defer repo.invalidate(ctx, userID)
+	return repo.Next.AddUserFavorite(ctx, userID, asset)
+}
+
+// DeleteUserFavorite deletes an asset from the user's favourites in the repository and invalidates the cached ones
+func (repo *CachedUserRepository) DeleteUserFavorite(ctx context.Context, userID, assetID int) error {
+	defer repo.invalidate(ctx, userID)
+	return repo.Next.DeleteUserFavorite(ctx, userID, assetID)
+}
+
+// EditUserFavorite edits an asset in the user's favourites in the repository and invalidates the cached ones
+func (repo *CachedUserRepository) EditUserFavorite(ctx context.Context, userID int, assetID int, asset models.Asset) error {
+	defer repo.invalidate(ctx, userID)
+	return repo.Next.EditUserFavorite(ctx, userID, assetID, asset)
+}
+
+// ReplaceUsers replaces the users of the repository, which must be a UserReplacer,
+// and invalidates the cached favourites of both the replaced and the new users
+func (repo *CachedUserRepository) ReplaceUsers(ctx context.Context, users map[int]models.User) error {
+	replacer, ok := repo.Next.(UserReplacer)
+	if !ok {
+		return errors.New("repository cannot replace its users")
+	}
+	ids, err := repo.Next.GetUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, id := range ids {
+			repo.invalidate(ctx, id)
+		}
+		for id := range users {
+			repo.invalidate(ctx, id)
+		}
+	}()
+	return replacer.ReplaceUsers(ctx, users)
+}
+
+// DeleteUser deletes a user from the repository, which must be a UserDeleter, and invalidates the cached favourites
+func (repo *CachedUserRepository) DeleteUser(ctx context.Context, userID int) error {
+	deleter, ok := repo.Next.(UserDeleter)
+	if !ok {
+		return errors.New("repository cannot delete its users")
+	}
+	defer repo.invalidate(ctx, userID)
+	return deleter.DeleteUser(ctx, userID)
+}
+
+// invalidate removes the favourites of a user from both caches. It runs even when the change failed,
+// since a failed change may still have been applied in part.
+func (repo *CachedUserRepository) invalidate(ctx context.Context, userID int) {
+	key := repo.key(userID)
+
+	repo.mu.Lock()
+	repo.generation++
+	if repo.Local != nil {
+		repo.Local.Delete(key)
+	}
+	repo.mu.Unlock()
+
+	if repo.Remote != nil {
+		if err := repo.Remote.Delete(ctx, key); err != nil {
+			_, span := telemetry.Start(ctx, "CachedUserRepository.invalidate", telemetry.UserIDKey.Int(userID))
+			telemetry.End(span, err)
+		}
+	}
+}
+
+// getRemote returns the favourites saved in the remote cache, treating errors and undecodable values as misses
+func (repo *CachedUserRepository) getRemote(ctx context.Context, key string) (map[int]models.Asset, bool) {
+	data, ok, err := repo.Remote.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+	favorites, err := decodeFavorites(data)
+	if err != nil {
+		return nil, false
+	}
+	return favorites, true
+}
+
+// fillLocal saves favourites in the local cache unless a change happened since they were read
+func (repo *CachedUserRepository) fillLocal(key string, favorites map[int]models.Asset, generation uint64) {
+	if repo.Local == nil {
+		return
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.generation == generation {
+		repo.Local.Set(key, favorites, repo.LocalTTL)
+	}
+}
+
+func (repo *CachedUserRepository) currentGeneration() uint64 {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.generation
+}
+
+func (repo *CachedUserRepository) key(userID int) string {
+	return repo.KeyPrefix + strconv.Itoa(userID)
+}
+
+// copyFavorites copies a map of favourites, so the cached maps are never shared with callers
+func copyFavorites(favorites map[int]models.Asset) map[int]models.Asset {
+	copied := make(map[int]models.Asset, len(favorites))
+	for id, asset := range favorites {
+		copied[id] = asset
+	}
+	return copied
+}
+
+// encodeFavorites encodes favourites as the JSON array saved in the remote cache
+func encodeFavorites(favorites map[int]models.Asset) ([]byte, error) {
+	assets := make([]models.Asset, 0, len(favorites))
+	for _, asset := range favorites {
+		assets = append(assets, asset)
+	}
+	return json.Marshal(assets)
+}
+
+// decodeFavorites decodes the JSON array of favourites saved in the remote cache
+func decodeFavorites(data []byte) (map[int]models.Asset, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	favorites := make(map[int]models.Asset, len(raw))
+	for _, item := range raw {
+		asset, err := utils.DecodeAsset(item)
+		if err != nil {
+			return nil, err
+		}
+		favorites[asset.GetID()] = asset
+	}
+	return favorites, nil
+}
@@ -0,0 +1,268 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/cache"
+	"github.com/ceciivanov/platform-go-challenge/internal/cache/resptest"
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingRepository counts the favourites read from an InMemoryUserRepository
+type countingRepository struct {
+	*repository.InMemoryUserRepository
+	reads int
+	mu    sync.Mutex
+}
+
+func (repo *countingRepository) GetUserFavorites(ctx context.Context, userID int) (map[int]models.Asset, error) {
+	repo.mu.Lock()
+	repo.reads++
+	repo.mu.Unlock()
+	return repo.InMemoryUserRepository.GetUserFavorites(ctx, userID)
+}
+
+func (repo *countingRepository) Reads() int {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.reads
+}
+
+// failingCache is a remote cache that cannot be reached
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+
+func setupCounting() *countingRepository {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(2, 3)
+	return &countingRepository{InMemoryUserRepository: repo}
+}
+
+func TestCachedUserRepositoryLocal(t *testing.T) {
+	ctx := context.Background()
+	next := setupCounting()
+	repo := repository.NewCachedUserRepository(next, cache.NewLRU(10), nil)
+
+	// Test the favourites are read through the cache
+	favorites, err := repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 3)
+	cached, err := repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, favorites, cached)
+	assert.Equal(t, 1, next.Reads())
+
+	// Test callers changing the returned map do not change the cached one
+	delete(cached, favorites[1].GetID())
+	cached, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Len(t, cached, 3)
+
+	// Test errors are not cached
+	_, err = repo.GetUserFavorites(ctx, 999)
+	assert.EqualError(t, err, "user not found")
+	_, err = repo.GetUserFavorites(ctx, 999)
+	assert.EqualError(t, err, "user not found")
+	assert.Equal(t, 3, next.Reads())
+
+	// Test every change invalidates the cached favourites, even a failed one
+	newAsset := &models.Insight{ID: 100, Type: models.InsightType, Description: "New Insight", Text: "Some text"}
+	assert.NoError(t, repo.AddUserFavorite(ctx, 1, newAsset))
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, newAsset, favorites[100])
+	assert.Equal(t, 4, next.Reads())
+
+	editedAsset := &models.Insight{ID: 100, Type: models.InsightType, Description: "Edited Insight", Text: "Some text"}
+	assert.NoError(t, repo.EditUserFavorite(ctx, 1, 100, editedAsset))
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, editedAsset, favorites[100])
+
+	assert.NoError(t, repo.DeleteUserFavorite(ctx, 1, 100))
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.NotContains(t, favorites, 100)
+
+	assert.Error(t, repo.DeleteUserFavorite(ctx, 1, 100))
+	repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, 7, next.Reads())
+
+	// Test the cached favourites expire
+	repo.LocalTTL = time.Nanosecond
+	repo.AddUserFavorite(ctx, 2, newAsset)
+	repo.GetUserFavorites(ctx, 2)
+	time.Sleep(time.Millisecond)
+	repo.GetUserFavorites(ctx, 2)
+	assert.Equal(t, 9, next.Reads())
+
+	// Test the user IDs are passed through
+	ids, err := repo.GetUserIDs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
+func TestCachedUserRepositoryRemote(t *testing.T) {
+	ctx := context.Background()
+	server := resptest.NewServer()
+	defer server.Close()
+	client := cache.NewRESPClient(server.Addr)
+	defer client.Close()
+
+	next := setupCounting()
+	first := repository.NewCachedUserRepository(next, cache.NewLRU(10), client)
+	second := repository.NewCachedUserRepository(next, cache.NewLRU(10), client)
+
+	// Test the favourites read by one instance are served to the other from the remote cache
+	favorites, err := first.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	_, ok := server.Get("favorites:1")
+	assert.True(t, ok)
+
+	shared, err := second.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, favorites, shared)
+	assert.Equal(t, 1, next.Reads())
+
+	// Test a change invalidates the remote cache, the other instance sees it once its local copy expires
+	newAsset := &models.Insight{ID: 100, Type: models.InsightType, Description: "New Insight", Text: "Some text"}
+	assert.NoError(t, first.AddUserFavorite(ctx, 1, newAsset))
+	_, ok = server.Get("favorites:1")
+	assert.False(t, ok)
+
+	stale, _ := second.GetUserFavorites(ctx, 1)
+	assert.NotContains(t, stale, 100)
+	second.Local.Delete("favorites:1")
+	fresh, _ := second.GetUserFavorites(ctx, 1)
+	assert.Equal(t, newAsset, fresh[100])
+	assert.Equal(t, 2, next.Reads())
+
+	// Test the remote favourites expire
+	server.FastForward(first.RemoteTTL)
+	first.Local.Delete("favorites:1")
+	first.GetUserFavorites(ctx, 1)
+	assert.Equal(t, 3, next.Reads())
+
+	// Test undecodable remote values are read from the repository
+	client.Set(ctx, "favorites:2", []byte("not json"), time.Minute)
+	favorites, err = first.GetUserFavorites(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 3)
+}
+
+func TestCachedUserRepositoryRemoteUnavailable(t *testing.T) {
+	ctx := context.Background()
+	next := setupCounting()
+	repo := repository.NewCachedUserRepository(next, nil, failingCache{})
+
+	// Test the errors of the remote cache do not fail the requests
+	favorites, err := repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 3)
+
+	newAsset := &models.Insight{ID: 100, Type: models.InsightType, Description: "New Insight", Text: "Some text"}
+	assert.NoError(t, repo.AddUserFavorite(ctx, 1, newAsset))
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, newAsset, favorites[100])
+	assert.Equal(t, 2, next.Reads())
+}
+
+func TestCachedUserRepositoryConcurrent(t *testing.T) {
+	ctx := context.Background()
+	next := setupCounting()
+	repo := repository.NewCachedUserRepository(next, cache.NewLRU(10), nil)
+
+	// Test concurrent reads and changes never leave stale favourites in the cache
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			repo.AddUserFavorite(ctx, 1, &models.Insight{ID: 100 + id, Type: models.InsightType, Description: "New Insight"})
+		}(i)
+		go func() {
+			defer wg.Done()
+			repo.GetUserFavorites(ctx, 1)
+		}()
+	}
+	wg.Wait()
+
+	favorites, err := repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 13)
+}
+
+func TestCachedUserRepositoryReplaceUsers(t *testing.T) {
+	ctx := context.Background()
+	server := resptest.NewServer()
+	defer server.Close()
+	client := cache.NewRESPClient(server.Addr)
+	defer client.Close()
+
+	next := setupCounting()
+	repo := repository.NewCachedUserRepository(next, cache.NewLRU(10), client)
+
+	// Test replacing the users invalidates the cached favourites of the replaced users
+	repo.GetUserFavorites(ctx, 1)
+	_, ok := server.Get("favorites:1")
+	assert.True(t, ok)
+
+	assert.NoError(t, repo.ReplaceUsers(ctx, repositorytest.Users()))
+	_, ok = server.Get("favorites:1")
+	assert.False(t, ok)
+	favorites, err := repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, repositorytest.Users()[1].Favourites, favorites)
+	_, err = repo.GetUserFavorites(ctx, 2)
+	assert.NoError(t, err)
+
+	// Test a repository that cannot replace its users
+	repo = repository.NewCachedUserRepository(failingRepository{}, nil, nil)
+	assert.EqualError(t, repo.ReplaceUsers(ctx, repositorytest.Users()), "repository cannot replace its users")
+	assert.EqualError(t, repo.DeleteUser(ctx, 1), "repository cannot delete its users")
+}
+
+// failingRepository is a UserRepository that can neither replace nor delete its users
+type failingRepository struct {
+	repository.UserRepository
+}
+
+func TestCachedUserRepositoryContract(t *testing.T) {
+	newNext := func() repository.UserRepository {
+		next := repository.NewInMemoryUserRepository()
+		next.Users = repositorytest.Users()
+		return next
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		repositorytest.RunContractTests(t, func() repository.UserRepository {
+			return repository.NewCachedUserRepository(newNext(), cache.NewLRU(10), nil)
+		})
+	})
+
+	t.Run("LocalAndRemote", func(t *testing.T) {
+		repositorytest.RunContractTests(t, func() repository.UserRepository {
+			server := resptest.NewServer()
+			client := cache.NewRESPClient(server.Addr)
+			t.Cleanup(func() {
+				client.Close()
+				server.Close()
+			})
+			return repository.NewCachedUserRepository(newNext(), cache.NewLRU(10), client)
+		})
+	})
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// CollectionRepository defines the methods that any type of collection repository must implement.
+// UpdateCollection only stores a collection whose Version is still the stored one, and increments it.
+type CollectionRepository interface {
+	AddCollection(collection models.Collection) (models.Collection, error)
+	GetCollection(id int) (models.Collection, error)
+	UpdateCollection(collection models.Collection) (models.Collection, error)
+	DeleteCollection(id int) error
+	GetCollectionsByOwner(userID int) ([]models.Collection, error)
+	GetCollectionsByMember(userID int) ([]models.Collection, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// ErasureRepository defines the methods that any type of erasure record repository must implement
+type ErasureRepository interface {
+	AddErasureRecord(record models.ErasureRecord) (models.ErasureRecord, error)
+	GetErasureRecords() ([]models.ErasureRecord, error)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// FileAuditKeyStore is an implementation of the AuditKeyStore interface keeping the keys in a JSON file,
+// so the values of the audit log of earlier runs stay readable. The file is replaced on every change,
+// so a deleted key does not stay on disk.
+type FileAuditKeyStore struct {
+	path string
+	keys map[int][]byte
+	mu   sync.Mutex // mu protects the keys map and serializes the writes of the file
+}
+
+// OpenFileAuditKeyStore reads the keys of the file at path, which is created by the first key if it does not exist
+func OpenFileAuditKeyStore(path string) (*FileAuditKeyStore, error) {
+	store := &FileAuditKeyStore{path: path, keys: make(map[int][]byte)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys map[string][]byte
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	for id, key := range keys {
+		userID, err := strconv.Atoi(id)
+		if err != nil || len(key) != auditKeySize {
+			return nil, errors.New("invalid audit key file")
+		}
+		store.keys[userID] = key
+	}
+	return store, nil
+}
+
+// GetAuditKey returns the key of a user, creating and saving it if the user has none and create is set
+func (store *FileAuditKeyStore) GetAuditKey(userID int, create bool) ([]byte, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if key, ok := store.keys[userID]; ok || !create {
+		return key, ok, nil
+	}
+	key, err := newAuditKey()
+	if err != nil {
+		return nil, false, err
+	}
+	store.keys[userID] = key
+	if err := store.save(); err != nil {
+		delete(store.keys, userID)
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// DeleteAuditKey deletes the key of a user, rewriting the file without it
+func (store *FileAuditKeyStore) DeleteAuditKey(userID int) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key, ok := store.keys[userID]
+	if !ok {
+		return false, nil
+	}
+	delete(store.keys, userID)
+	if err := store.save(); err != nil {
+		store.keys[userID] = key
+		return false, err
+	}
+	return true, nil
+}
+
+// save writes the keys to a temporary file renamed over the file once complete, so the file is never partially written
+func (store *FileAuditKeyStore) save() error {
+	keys := make(map[string][]byte, len(store.keys))
+	for userID, key := range store.keys {
+		keys[strconv.Itoa(userID)] = key
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(store.path), ".audit-keys-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), store.path)
+}
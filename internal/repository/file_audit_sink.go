@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// FileAuditSink is an implementation of the AuditSink interface appending the records to a JSON Lines file,
+// one JSON document per line. The records of earlier runs are kept, and queried along with the new ones.
+type FileAuditSink struct {
+	file *os.File
+	mu   sync.Mutex // mu serializes the appends, so the file always ends with a complete line between them
+}
+
+// OpenFileAuditSink opens the audit log file at path for appending, creating it if it does not exist
+func OpenFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	// Terminate a line left incomplete by a crash, so the next record starts on a line of its own
+	info, err := file.Stat()
+	if err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err = file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			_, err = file.Write([]byte{'\n'})
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+// AppendAuditRecord appends a record to the file as a single line
+func (sink *FileAuditSink) AppendAuditRecord(record models.AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	_, err = sink.file.Write(append(line, '\n'))
+	return err
+}
+
+// GetAuditRecords reads the file from the cursor, or from the start, and returns the records selected by the filter,
+// oldest first. The cursors are offsets of the file. Lines that are not a record, such as one left incomplete by a crash,
+// are skipped.
+func (sink *FileAuditSink) GetAuditRecords(filter AuditFilter) (models.AuditPage, error) {
+	offset, err := decodeAuditCursor(filter.After)
+	if err != nil {
+		return models.AuditPage{}, err
+	}
+
+	// Read up to the size of the file between two appends, without blocking the appends while reading
+	sink.mu.Lock()
+	info, err := sink.file.Stat()
+	sink.mu.Unlock()
+	if err != nil {
+		return models.AuditPage{}, err
+	}
+	if offset > info.Size() {
+		return models.AuditPage{}, errors.New("invalid cursor")
+	}
+
+	records := make([]models.AuditRecord, 0)
+	reader := bufio.NewReader(io.NewSectionReader(sink.file, offset, info.Size()-offset))
+	for !filter.full(records) {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return models.AuditPage{}, err
+		}
+		offset += int64(len(line))
+
+		var record models.AuditRecord
+		if json.Unmarshal(line, &record) != nil {
+			continue
+		}
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	return models.AuditPage{Records: records, Next: encodeAuditCursor(offset)}, nil
+}
+
+// Close closes the audit log file
+func (sink *FileAuditSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return sink.file.Close()
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := repository.OpenFileAuditSink(path)
+	assert.NoError(t, err)
+	testAuditSink(t, sink)
+	assert.NoError(t, sink.Close())
+
+	// Test the file holds one record per line, readable only by its owner
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, len(auditRecords)+1, strings.Count(string(data), "\n"))
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Test the records of an earlier run are kept when the file is opened again
+	sink, err = repository.OpenFileAuditSink(path)
+	assert.NoError(t, err)
+	defer sink.Close()
+	page, err := sink.GetAuditRecords(repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, append(auditRecords, auditRecords[0]), page.Records)
+}
+
+func TestFileAuditSinkIncompleteLine(t *testing.T) {
+	// Test a line left incomplete by a crash is skipped, and the next records are appended after it
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"timestamp":"2024-05-01T10:00:00Z","act`), 0o600))
+	sink, err := repository.OpenFileAuditSink(path)
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	assert.NoError(t, sink.AppendAuditRecord(auditRecords[0]))
+	page, err := sink.GetAuditRecords(repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, auditRecords[:1], page.Records)
+}
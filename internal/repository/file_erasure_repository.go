@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// FileErasureRepository is an implementation of the ErasureRepository interface appending the records to a JSON Lines
+// file, one JSON document per line, so the proof of the erasures outlives the process. The records of earlier runs
+// are read when the file is opened and kept in memory, since there is one per erasure.
+type FileErasureRepository struct {
+	file    *os.File
+	records []models.ErasureRecord
+	mu      sync.RWMutex // mu protects the records and serializes the appends
+}
+
+// OpenFileErasureRepository opens the erasure file at path for appending, creating it if it does not exist,
+// and reads its records. Lines that are not a record, such as one left incomplete by a crash, are skipped.
+func OpenFileErasureRepository(path string) (*FileErasureRepository, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &FileErasureRepository{file: file, records: make([]models.ErasureRecord, 0)}
+	reader := bufio.NewReader(file)
+	complete := true
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			complete = line[len(line)-1] == '\n'
+			var record models.ErasureRecord
+			if json.Unmarshal(line, &record) == nil {
+				repo.records = append(repo.records, record)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	// Terminate a line left incomplete by a crash, so the next record starts on a line of its own
+	if !complete {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// AddErasureRecord appends a record to the file, assigning it the next id
+func (repo *FileErasureRepository) AddErasureRecord(record models.ErasureRecord) (models.ErasureRecord, error) {
+	// Lock the records for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// Number after the last record rather than counting them, since skipped lines may have held records
+	record.ID = 1
+	if n := len(repo.records); n > 0 {
+		record.ID = repo.records[n-1].ID + 1
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return record, err
+	}
+	if _, err := repo.file.Write(append(line, '\n')); err != nil {
+		return record, err
+	}
+	repo.records = append(repo.records, record)
+	return record, nil
+}
+
+// GetErasureRecords returns every erasure record, oldest first
+func (repo *FileErasureRepository) GetErasureRecords() ([]models.ErasureRecord, error) {
+	// Lock the records for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	records := make([]models.ErasureRecord, len(repo.records))
+	copy(records, repo.records)
+	return records, nil
+}
+
+// Close closes the erasure file
+func (repo *FileErasureRepository) Close() error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.file.Close()
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileErasureRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "erasures.jsonl")
+	repo, err := repository.OpenFileErasureRepository(path)
+	assert.NoError(t, err)
+	records := testErasureRepository(t, repo)
+	assert.NoError(t, repo.Close())
+
+	// Test the file is readable only by its owner
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Test the records of an earlier run are kept, and the next records numbered after them
+	repo, err = repository.OpenFileErasureRepository(path)
+	assert.NoError(t, err)
+	defer repo.Close()
+	reopened, err := repo.GetErasureRecords()
+	assert.NoError(t, err)
+	assert.Equal(t, records, reopened)
+	third, err := repo.AddErasureRecord(models.ErasureRecord{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, third.ID)
+}
+
+func TestFileErasureRepositoryIncompleteLine(t *testing.T) {
+	// Test a line left incomplete by a crash is skipped, and the next records are appended after it
+	path := filepath.Join(t.TempDir(), "erasures.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte("{\"id\":1,\"userId\":3}\n{\"id\":2,\"us"), 0o600))
+	repo, err := repository.OpenFileErasureRepository(path)
+	assert.NoError(t, err)
+	record, err := repo.AddErasureRecord(models.ErasureRecord{UserID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, record.ID)
+	assert.NoError(t, repo.Close())
+
+	repo, err = repository.OpenFileErasureRepository(path)
+	assert.NoError(t, err)
+	defer repo.Close()
+	records, _ := repo.GetErasureRecords()
+	assert.Equal(t, []int{3, 1}, []int{records[0].UserID, records[1].UserID})
+}
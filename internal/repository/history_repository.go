@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// HistoryRepository defines the methods that any type of favourite history repository must implement
+type HistoryRepository interface {
+	AddHistoryEntry(entry models.HistoryEntry) (models.HistoryEntry, error)
+	GetHistory(userID, assetID int) ([]models.HistoryEntry, error)
+	GetUserHistory(userID int) ([]models.HistoryEntry, error)
+	AddToTrash(item models.TrashItem) error
+	GetTrash(userID int) ([]models.TrashItem, error)
+	RemoveFromTrash(userID, assetID int) (models.TrashItem, error)
+	PurgeTrash(now time.Time) (int, error)
+	DeleteUserHistory(userID int) (entries, items int, err error)
+}
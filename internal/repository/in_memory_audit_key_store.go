@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"sync"
+)
+
+// InMemoryAuditKeyStore is an in-memory implementation of the AuditKeyStore interface
+// InMemoryAuditKeyStore contains the keys of the users by user id
+type InMemoryAuditKeyStore struct {
+	keys map[int][]byte
+	mu   sync.Mutex // mu protects the keys map from concurrent access
+}
+
+// NewInMemoryAuditKeyStore creates a new instance of InMemoryAuditKeyStore
+func NewInMemoryAuditKeyStore() *InMemoryAuditKeyStore {
+	return &InMemoryAuditKeyStore{
+		keys: make(map[int][]byte),
+	}
+}
+
+// GetAuditKey returns the key of a user, creating it if the user has none and create is set
+func (store *InMemoryAuditKeyStore) GetAuditKey(userID int, create bool) ([]byte, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if key, ok := store.keys[userID]; ok || !create {
+		return key, ok, nil
+	}
+	key, err := newAuditKey()
+	if err != nil {
+		return nil, false, err
+	}
+	store.keys[userID] = key
+	return key, true, nil
+}
+
+// DeleteAuditKey deletes the key of a user
+func (store *InMemoryAuditKeyStore) DeleteAuditKey(userID int) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	_, ok := store.keys[userID]
+	delete(store.keys, userID)
+	return ok, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// DefaultMaxAuditRecords is how many records an InMemoryAuditSink holds by default
+const DefaultMaxAuditRecords = 10000
+
+// ErrAuditLogFull is returned when appending to an InMemoryAuditSink holding its maximum number of records
+var ErrAuditLogFull = errors.New("audit log full")
+
+// InMemoryAuditSink is an in-memory implementation of the AuditSink interface
+// InMemoryAuditSink contains the audit records in the order they were appended
+type InMemoryAuditSink struct {
+	records    []models.AuditRecord
+	maxRecords int
+	mu         sync.RWMutex // mu is a read-write mutex to protect the records from concurrent access
+}
+
+// NewInMemoryAuditSink creates a new instance of InMemoryAuditSink holding up to maxRecords records,
+// or any number of records if maxRecords is zero
+func NewInMemoryAuditSink(maxRecords int) *InMemoryAuditSink {
+	return &InMemoryAuditSink{
+		records:    make([]models.AuditRecord, 0),
+		maxRecords: maxRecords,
+	}
+}
+
+// AppendAuditRecord appends a record to the log. A full log refuses the record with ErrAuditLogFull rather than
+// dropping older records, since the log is append-only.
+func (sink *InMemoryAuditSink) AppendAuditRecord(record models.AuditRecord) error {
+	// Lock the records for writing
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.maxRecords > 0 && len(sink.records) >= sink.maxRecords {
+		return ErrAuditLogFull
+	}
+	sink.records = append(sink.records, record)
+	return nil
+}
+
+// GetAuditRecords returns the records selected by the filter, oldest first.
+// The cursors are the number of records appended before the next record.
+func (sink *InMemoryAuditSink) GetAuditRecords(filter AuditFilter) (models.AuditPage, error) {
+	start, err := decodeAuditCursor(filter.After)
+	if err != nil {
+		return models.AuditPage{}, err
+	}
+
+	// Lock the records for reading
+	sink.mu.RLock()
+	defer sink.mu.RUnlock()
+
+	if start > int64(len(sink.records)) {
+		return models.AuditPage{}, errors.New("invalid cursor")
+	}
+	records := make([]models.AuditRecord, 0)
+	position := start
+	for ; position < int64(len(sink.records)) && !filter.full(records); position++ {
+		if filter.Match(sink.records[position]) {
+			records = append(records, sink.records[position])
+		}
+	}
+	return models.AuditPage{Records: records, Next: encodeAuditCursor(position)}, nil
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// auditRecords are appended by testAuditSink, one minute apart
+var auditRecords = []models.AuditRecord{
+	{Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Actor: "user:alice", RequestID: "a", UserID: 1, AssetID: 1, Operation: models.OperationAdd, Version: 1},
+	{Timestamp: time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC), Actor: "user:bob", RequestID: "b", UserID: 2, AssetID: 1, Operation: models.OperationAdd, Version: 1},
+	{Timestamp: time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC), Actor: "user:alice", RequestID: "c", UserID: 1, AssetID: 1, Operation: models.OperationEdit, Version: 2,
+		Fields: []string{"text"}},
+	{Timestamp: time.Date(2024, 5, 1, 10, 3, 0, 0, time.UTC), Actor: "user:alice", UserID: 2, AssetID: 1, Operation: models.OperationDelete, Version: 2},
+}
+
+// testAuditSink tests appending the auditRecords to an empty sink and querying them
+func testAuditSink(t *testing.T, sink repository.AuditSink) {
+	page, err := sink.GetAuditRecords(repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Records)
+	start := page.Next
+
+	for _, record := range auditRecords {
+		assert.NoError(t, sink.AppendAuditRecord(record))
+	}
+
+	tests := []struct {
+		name     string
+		filter   repository.AuditFilter
+		expected []models.AuditRecord
+	}{
+		{"All", repository.AuditFilter{}, auditRecords},
+		{"From", repository.AuditFilter{From: auditRecords[2].Timestamp}, auditRecords[2:]},
+		{"To", repository.AuditFilter{To: auditRecords[2].Timestamp}, auditRecords[:2]},
+		{"Range", repository.AuditFilter{From: auditRecords[1].Timestamp, To: auditRecords[3].Timestamp}, auditRecords[1:3]},
+		{"Actor", repository.AuditFilter{Actor: "user:bob"}, auditRecords[1:2]},
+		{"UserID", repository.AuditFilter{UserID: 2}, []models.AuditRecord{auditRecords[1], auditRecords[3]}},
+		{"Limit", repository.AuditFilter{Actor: "user:alice", Limit: 2}, []models.AuditRecord{auditRecords[0], auditRecords[2]}},
+		{"NoMatch", repository.AuditFilter{Actor: "user:carol"}, []models.AuditRecord{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := sink.GetAuditRecords(tc.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, page.Records)
+		})
+	}
+
+	// Test paging through the records of the filter with the cursors, including records sharing a timestamp
+	var records []models.AuditRecord
+	filter := repository.AuditFilter{From: auditRecords[0].Timestamp, Actor: "user:alice", Limit: 1}
+	for {
+		page, err := sink.GetAuditRecords(filter)
+		assert.NoError(t, err)
+		if len(page.Records) == 0 {
+			break
+		}
+		records = append(records, page.Records...)
+		filter.After = page.Next
+	}
+	assert.Equal(t, []models.AuditRecord{auditRecords[0], auditRecords[2], auditRecords[3]}, records)
+
+	// Test the cursor of the last page continues with the records appended afterwards
+	assert.NoError(t, sink.AppendAuditRecord(auditRecords[0]))
+	page, err = sink.GetAuditRecords(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, auditRecords[:1], page.Records)
+
+	// Test the cursor of an empty log selects every record
+	page, err = sink.GetAuditRecords(repository.AuditFilter{After: start})
+	assert.NoError(t, err)
+	assert.Len(t, page.Records, len(auditRecords)+1)
+
+	// Test invalid cursors
+	for _, cursor := range []string{"abc", "YXVkaXQ6LTE", "YXVkaXQ6MTAwMDA"} {
+		_, err = sink.GetAuditRecords(repository.AuditFilter{After: cursor})
+		assert.EqualError(t, err, "invalid cursor", cursor)
+	}
+}
+
+func TestInMemoryAuditSink(t *testing.T) {
+	testAuditSink(t, repository.NewInMemoryAuditSink(0))
+}
+
+func TestInMemoryAuditSinkMaxRecords(t *testing.T) {
+	sink := repository.NewInMemoryAuditSink(2)
+	for _, record := range auditRecords[:2] {
+		assert.NoError(t, sink.AppendAuditRecord(record))
+	}
+
+	// Test a full log refuses the new records instead of dropping the oldest ones
+	assert.ErrorIs(t, sink.AppendAuditRecord(auditRecords[2]), repository.ErrAuditLogFull)
+	page, err := sink.GetAuditRecords(repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, auditRecords[:2], page.Records)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// InMemoryCollectionRepository is an in-memory implementation of the CollectionRepository interface
+// InMemoryCollectionRepository contains a map of all collections keyed by id
+type InMemoryCollectionRepository struct {
+	collections map[int]models.Collection
+	nextID      int
+	mu          sync.RWMutex // mu is a read-write mutex to protect the collections map from concurrent access
+}
+
+// NewInMemoryCollectionRepository creates a new instance of InMemoryCollectionRepository
+func NewInMemoryCollectionRepository() *InMemoryCollectionRepository {
+	return &InMemoryCollectionRepository{
+		collections: make(map[int]models.Collection),
+		nextID:      1,
+	}
+}
+
+// AddCollection stores a new collection, assigning it the next id
+func (repo *InMemoryCollectionRepository) AddCollection(collection models.Collection) (models.Collection, error) {
+	// Lock the collections map for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	collection.ID = repo.nextID
+	repo.nextID++
+	repo.collections[collection.ID] = cloneCollection(collection)
+	return collection, nil
+}
+
+// GetCollection returns a collection by its id
+func (repo *InMemoryCollectionRepository) GetCollection(id int) (models.Collection, error) {
+	// Lock the collections map for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	collection, ok := repo.collections[id]
+	if !ok {
+		return models.Collection{}, errors.New("collection not found")
+	}
+	return cloneCollection(collection), nil
+}
+
+// UpdateCollection replaces an existing collection and returns it with its new version.
+// It fails if the collection was updated since it was read, so concurrent changes are never lost.
+func (repo *InMemoryCollectionRepository) UpdateCollection(collection models.Collection) (models.Collection, error) {
+	// Lock the collections map for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.collections[collection.ID]
+	if !ok {
+		return models.Collection{}, errors.New("collection not found")
+	}
+	if stored.Version != collection.Version {
+		return models.Collection{}, errors.New("collection modified concurrently")
+	}
+
+	collection.Version++
+	repo.collections[collection.ID] = cloneCollection(collection)
+	return collection, nil
+}
+
+// DeleteCollection removes a collection
+func (repo *InMemoryCollectionRepository) DeleteCollection(id int) error {
+	// Lock the collections map for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.collections[id]; !ok {
+		return errors.New("collection not found")
+	}
+	delete(repo.collections, id)
+	return nil
+}
+
+// GetCollectionsByOwner returns the collections owned by a user, ordered by id
+func (repo *InMemoryCollectionRepository) GetCollectionsByOwner(userID int) ([]models.Collection, error) {
+	return repo.filter(func(c models.Collection) bool { return c.OwnerID == userID }), nil
+}
+
+// GetCollectionsByMember returns the collections shared with a user, ordered by id
+func (repo *InMemoryCollectionRepository) GetCollectionsByMember(userID int) ([]models.Collection, error) {
+	return repo.filter(func(c models.Collection) bool {
+		_, ok := c.Members[userID]
+		return ok
+	}), nil
+}
+
+// filter returns copies of the collections matching keep, ordered by id
+func (repo *InMemoryCollectionRepository) filter(keep func(models.Collection) bool) []models.Collection {
+	// Lock the collections map for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	collections := make([]models.Collection, 0)
+	for _, collection := range repo.collections {
+		if keep(collection) {
+			collections = append(collections, cloneCollection(collection))
+		}
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].ID < collections[j].ID })
+	return collections
+}
+
+// cloneCollection copies the members and assets of a collection, so stored collections are never shared with callers
+func cloneCollection(collection models.Collection) models.Collection {
+	members := make(map[int]models.Role, len(collection.Members))
+	for userID, role := range collection.Members {
+		members[userID] = role
+	}
+	collection.Members = members
+	collection.Assets = append(make([]models.AssetRef, 0, len(collection.Assets)), collection.Assets...)
+	return collection
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollections(t *testing.T) {
+	repo := repository.NewInMemoryCollectionRepository()
+
+	// Test ids are assigned in order
+	first, err := repo.AddCollection(models.Collection{OwnerID: 1, Name: "First", Members: map[int]models.Role{2: models.RoleViewer}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.ID)
+	second, _ := repo.AddCollection(models.Collection{OwnerID: 2, Name: "Second"})
+	assert.Equal(t, 2, second.ID)
+
+	// Test returned collections are copies
+	collection, err := repo.GetCollection(first.ID)
+	assert.NoError(t, err)
+	collection.Members[3] = models.RoleEditor
+	collection, _ = repo.GetCollection(first.ID)
+	assert.Len(t, collection.Members, 1)
+
+	// Test updates are stored with a new version
+	stale := collection
+	collection.Assets = append(collection.Assets, models.AssetRef{UserID: 1, AssetID: 1})
+	updated, err := repo.UpdateCollection(collection)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated.Version)
+	collection, _ = repo.GetCollection(first.ID)
+	assert.Len(t, collection.Assets, 1)
+	assert.Equal(t, updated, collection)
+
+	// Test an update of a collection read before the last update is refused
+	stale.Name = "Stale"
+	_, err = repo.UpdateCollection(stale)
+	assert.EqualError(t, err, "collection modified concurrently")
+	collection, _ = repo.GetCollection(first.ID)
+	assert.Equal(t, "First", collection.Name)
+
+	// Test listing by owner and member
+	owned, _ := repo.GetCollectionsByOwner(2)
+	assert.Len(t, owned, 1)
+	assert.Equal(t, "Second", owned[0].Name)
+	shared, _ := repo.GetCollectionsByMember(2)
+	assert.Len(t, shared, 1)
+	assert.Equal(t, "First", shared[0].Name)
+
+	// Test deleting
+	assert.NoError(t, repo.DeleteCollection(first.ID))
+	_, err = repo.GetCollection(first.ID)
+	assert.EqualError(t, err, "collection not found")
+	assert.EqualError(t, repo.DeleteCollection(first.ID), "collection not found")
+	_, err = repo.UpdateCollection(models.Collection{ID: first.ID})
+	assert.EqualError(t, err, "collection not found")
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// InMemoryErasureRepository is an in-memory implementation of the ErasureRepository interface
+// InMemoryErasureRepository contains the erasure records in the order they were added
+type InMemoryErasureRepository struct {
+	records []models.ErasureRecord
+	mu      sync.RWMutex // mu is a read-write mutex to protect the records from concurrent access
+}
+
+// NewInMemoryErasureRepository creates a new instance of InMemoryErasureRepository
+func NewInMemoryErasureRepository() *InMemoryErasureRepository {
+	return &InMemoryErasureRepository{
+		records: make([]models.ErasureRecord, 0),
+	}
+}
+
+// AddErasureRecord appends a record, assigning it the next id
+func (repo *InMemoryErasureRepository) AddErasureRecord(record models.ErasureRecord) (models.ErasureRecord, error) {
+	// Lock the records for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	record.ID = len(repo.records) + 1
+	repo.records = append(repo.records, record)
+	return record, nil
+}
+
+// GetErasureRecords returns every erasure record, oldest first
+func (repo *InMemoryErasureRepository) GetErasureRecords() ([]models.ErasureRecord, error) {
+	// Lock the records for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	records := make([]models.ErasureRecord, len(repo.records))
+	copy(records, repo.records)
+	return records, nil
+}
@@ -0,0 +1,31 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// testErasureRepository tests adding records to an empty repository and returns them
+func testErasureRepository(t *testing.T, repo repository.ErasureRepository) []models.ErasureRecord {
+	records, err := repo.GetErasureRecords()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	// Test records are numbered in the order they are added
+	first, err := repo.AddErasureRecord(models.ErasureRecord{UserID: 3, Actor: "user:alice", Removed: map[string]int{"favorites": 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.ID)
+	second, _ := repo.AddErasureRecord(models.ErasureRecord{UserID: 1, Actor: "user:bob", Removed: map[string]int{}})
+	assert.Equal(t, 2, second.ID)
+
+	records, _ = repo.GetErasureRecords()
+	assert.Equal(t, []models.ErasureRecord{first, second}, records)
+	return records
+}
+
+func TestErasureRecords(t *testing.T) {
+	testErasureRepository(t, repository.NewInMemoryErasureRepository())
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// historyKey identifies the history of a single favourite
+type historyKey struct {
+	UserID  int
+	AssetID int
+}
+
+// InMemoryHistoryRepository is an in-memory implementation of the HistoryRepository interface
+// InMemoryHistoryRepository contains the history of every favourite and the trash of every user
+type InMemoryHistoryRepository struct {
+	history map[historyKey][]models.HistoryEntry
+	trash   map[int]map[int]models.TrashItem // trashed items per user, keyed by asset id
+	mu      sync.RWMutex                     // mu is a read-write mutex to protect the maps from concurrent access
+}
+
+// NewInMemoryHistoryRepository creates a new instance of InMemoryHistoryRepository
+func NewInMemoryHistoryRepository() *InMemoryHistoryRepository {
+	return &InMemoryHistoryRepository{
+		history: make(map[historyKey][]models.HistoryEntry),
+		trash:   make(map[int]map[int]models.TrashItem),
+	}
+}
+
+// AddHistoryEntry appends an entry to the favourite's history, assigning it the next version number
+func (repo *InMemoryHistoryRepository) AddHistoryEntry(entry models.HistoryEntry) (models.HistoryEntry, error) {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := historyKey{UserID: entry.UserID, AssetID: entry.AssetID}
+	entry.Version = len(repo.history[key]) + 1
+	repo.history[key] = append(repo.history[key], entry)
+	return entry, nil
+}
+
+// GetHistory returns the history of a favourite, oldest version first
+func (repo *InMemoryHistoryRepository) GetHistory(userID, assetID int) ([]models.HistoryEntry, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	entries := repo.history[historyKey{UserID: userID, AssetID: assetID}]
+	history := make([]models.HistoryEntry, len(entries))
+	copy(history, entries)
+	return history, nil
+}
+
+// GetUserHistory returns the history of every favourite of a user, ordered by asset id then oldest version first
+func (repo *InMemoryHistoryRepository) GetUserHistory(userID int) ([]models.HistoryEntry, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	history := make([]models.HistoryEntry, 0)
+	for key, entries := range repo.history {
+		if key.UserID == userID {
+			history = append(history, entries...)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].AssetID != history[j].AssetID {
+			return history[i].AssetID < history[j].AssetID
+		}
+		return history[i].Version < history[j].Version
+	})
+	return history, nil
+}
+
+// AddToTrash puts a deleted favourite in the user's trash, replacing an older copy of the same asset
+func (repo *InMemoryHistoryRepository) AddToTrash(item models.TrashItem) error {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.trash[item.UserID]; !ok {
+		repo.trash[item.UserID] = make(map[int]models.TrashItem)
+	}
+	repo.trash[item.UserID][item.Asset.GetID()] = item
+	return nil
+}
+
+// GetTrash returns the trashed favourites of a user, most recently deleted first
+func (repo *InMemoryHistoryRepository) GetTrash(userID int) ([]models.TrashItem, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	items := make([]models.TrashItem, 0, len(repo.trash[userID]))
+	for _, item := range repo.trash[userID] {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].DeletedAt.Equal(items[j].DeletedAt) {
+			return items[i].DeletedAt.After(items[j].DeletedAt)
+		}
+		return items[i].Asset.GetID() < items[j].Asset.GetID()
+	})
+	return items, nil
+}
+
+// RemoveFromTrash takes a favourite out of the user's trash and returns it
+func (repo *InMemoryHistoryRepository) RemoveFromTrash(userID, assetID int) (models.TrashItem, error) {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	item, ok := repo.trash[userID][assetID]
+	if !ok {
+		return models.TrashItem{}, errors.New("asset not in trash")
+	}
+	delete(repo.trash[userID], assetID)
+	return item, nil
+}
+
+// PurgeTrash permanently removes the trashed favourites that expired at or before now
+// and returns how many were removed. Items with a zero ExpiresAt never expire.
+func (repo *InMemoryHistoryRepository) PurgeTrash(now time.Time) (int, error) {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	purged := 0
+	for userID, items := range repo.trash {
+		for assetID, item := range items {
+			if item.Expired(now) {
+				delete(items, assetID)
+				purged++
+			}
+		}
+		if len(items) == 0 {
+			delete(repo.trash, userID)
+		}
+	}
+	return purged, nil
+}
+
+// DeleteUserHistory permanently removes the history of every favourite of a user and the user's trash,
+// and returns how many history entries and trashed items were removed
+func (repo *InMemoryHistoryRepository) DeleteUserHistory(userID int) (entries, items int, err error) {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for key, history := range repo.history {
+		if key.UserID == userID {
+			entries += len(history)
+			delete(repo.history, key)
+		}
+	}
+	items = len(repo.trash[userID])
+	delete(repo.trash, userID)
+	return entries, items, nil
+}
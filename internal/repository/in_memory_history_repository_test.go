@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryEntries(t *testing.T) {
+	repo := repository.NewInMemoryHistoryRepository()
+	asset := models.Insight{ID: 1, Type: models.InsightType}
+
+	// Test versions are assigned per favourite
+	entry, err := repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationAdd, After: asset})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entry.Version)
+
+	entry, _ = repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationDelete, Before: asset})
+	assert.Equal(t, 2, entry.Version)
+
+	entry, _ = repo.AddHistoryEntry(models.HistoryEntry{UserID: 2, AssetID: 1, Operation: models.OperationAdd, After: asset})
+	assert.Equal(t, 1, entry.Version)
+
+	history, err := repo.GetHistory(1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, models.OperationDelete, history[1].Operation)
+
+	// Test unknown favourites have an empty history
+	history, err = repo.GetHistory(3, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestTrash(t *testing.T) {
+	repo := repository.NewInMemoryHistoryRepository()
+	now := time.Now()
+
+	assert.NoError(t, repo.AddToTrash(models.TrashItem{UserID: 1, Asset: models.Insight{ID: 1}, DeletedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	assert.NoError(t, repo.AddToTrash(models.TrashItem{UserID: 1, Asset: models.Insight{ID: 2}, DeletedAt: now.Add(time.Minute)}))
+
+	// Test items are returned most recently deleted first
+	trash, err := repo.GetTrash(1)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 2)
+	assert.Equal(t, 2, trash[0].Asset.GetID())
+
+	// Test only expired items are purged, items without expiry are kept
+	purged, err := repo.PurgeTrash(now.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	// Test removing items
+	item, err := repo.RemoveFromTrash(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, item.Asset.GetID())
+
+	_, err = repo.RemoveFromTrash(1, 2)
+	assert.EqualError(t, err, "asset not in trash")
+
+	trash, _ = repo.GetTrash(1)
+	assert.Empty(t, trash)
+}
+
+func TestDeleteUserHistory(t *testing.T) {
+	repo := repository.NewInMemoryHistoryRepository()
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationAdd})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationDelete})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 2, Operation: models.OperationAdd})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 2, AssetID: 1, Operation: models.OperationAdd})
+	repo.AddToTrash(models.TrashItem{UserID: 1, Asset: models.Insight{ID: 1}})
+
+	// Test only the history and trash of the user are removed
+	entries, items, err := repo.DeleteUserHistory(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, entries)
+	assert.Equal(t, 1, items)
+
+	history, _ := repo.GetHistory(1, 1)
+	assert.Empty(t, history)
+	trash, _ := repo.GetTrash(1)
+	assert.Empty(t, trash)
+	history, _ = repo.GetHistory(2, 1)
+	assert.Len(t, history, 1)
+}
+
+func TestGetUserHistory(t *testing.T) {
+	repo := repository.NewInMemoryHistoryRepository()
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 2, Operation: models.OperationAdd})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationAdd})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 1, AssetID: 1, Operation: models.OperationEdit})
+	repo.AddHistoryEntry(models.HistoryEntry{UserID: 2, AssetID: 1, Operation: models.OperationAdd})
+
+	// Test the history of every favourite of the user is returned, ordered by asset then version
+	history, err := repo.GetUserHistory(1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, []int{1, 1, 2}, []int{history[0].AssetID, history[1].AssetID, history[2].AssetID})
+	assert.Equal(t, []int{1, 2, 1}, []int{history[0].Version, history[1].Version, history[2].Version})
+
+	history, _ = repo.GetUserHistory(999)
+	assert.Empty(t, history)
+}
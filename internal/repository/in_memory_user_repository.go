@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
 )
 
 // InMemoryUserRepository is an in-memory implementation of the UserRepository interface
@@ -32,8 +35,64 @@ func (repo *InMemoryUserRepository) GenerateSampleUsers(NumberOfUsers, NumberOfA
 	repo.Users = mock_data.GenerateMockData(NumberOfUsers, NumberOfAssets)
 }
 
-// GetUserFavorites returns a map of user's favorite assets
-func (repo *InMemoryUserRepository) GetUserFavorites(userID int) (map[int]models.Asset, error) {
+// ReplaceUsers replaces all users with a copy of users, e.g. the ones of a fixture file
+func (repo *InMemoryUserRepository) ReplaceUsers(ctx context.Context, users map[int]models.User) (err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.ReplaceUsers")
+	defer func() { telemetry.End(span, err) }()
+
+	// Copy the users, so the caller changing its maps afterwards does not change the repository
+	replaced := make(map[int]models.User, len(users))
+	for id, user := range users {
+		user.Favourites = copyFavorites(user.Favourites)
+		replaced[id] = user
+	}
+
+	// Lock the Users map for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.Users = replaced
+	return nil
+}
+
+// DeleteUser deletes a user with their favourites
+func (repo *InMemoryUserRepository) DeleteUser(ctx context.Context, userID int) (err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.DeleteUser", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	// Lock the Users map for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.Users[userID]; !ok {
+		return errors.New("user not found")
+	}
+	delete(repo.Users, userID)
+	return nil
+}
+
+// GetUserIDs returns the IDs of all users in ascending order
+func (repo *InMemoryUserRepository) GetUserIDs(ctx context.Context) (ids []int, err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.GetUserIDs")
+	defer func() { telemetry.End(span, err) }()
+
+	// Lock the Users map for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	ids = make([]int, 0, len(repo.Users))
+	for id := range repo.Users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// GetUserFavorites returns a copy of the map of user's favorite assets
+func (repo *InMemoryUserRepository) GetUserFavorites(ctx context.Context, userID int) (favorites map[int]models.Asset, err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.GetUserFavorites", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
 	// Lock the Users map for reading
 	repo.mu.RLock()
 	defer repo.mu.RUnlock()
@@ -42,11 +101,16 @@ func (repo *InMemoryUserRepository) GetUserFavorites(userID int) (map[int]models
 	if !ok {
 		return nil, errors.New("user not found")
 	}
-	return user.Favourites, nil
+	// Return a copy, so callers can read it while the favourites change
+	return copyFavorites(user.Favourites), nil
 }
 
 // AddUserFavorite adds an asset to the user's favorites
-func (repo *InMemoryUserRepository) AddUserFavorite(userID int, asset models.Asset) error {
+func (repo *InMemoryUserRepository) AddUserFavorite(ctx context.Context, userID int, asset models.Asset) (err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.AddUserFavorite",
+		telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(asset.GetID()), telemetry.AssetTypeKey.String(string(asset.GetType())))
+	defer func() { telemetry.End(span, err) }()
+
 	// Lock the Users map for writing
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -66,7 +130,10 @@ func (repo *InMemoryUserRepository) AddUserFavorite(userID int, asset models.Ass
 }
 
 // DeleteUserFavorite deletes an asset from the user's favorites
-func (repo *InMemoryUserRepository) DeleteUserFavorite(userID, assetID int) error {
+func (repo *InMemoryUserRepository) DeleteUserFavorite(ctx context.Context, userID, assetID int) (err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.DeleteUserFavorite", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
 	// Lock the Users map for writing
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -86,7 +153,11 @@ func (repo *InMemoryUserRepository) DeleteUserFavorite(userID, assetID int) erro
 }
 
 // EditUserFavorite edits an asset in the user's favorites
-func (repo *InMemoryUserRepository) EditUserFavorite(userID int, assetID int, asset models.Asset) error {
+func (repo *InMemoryUserRepository) EditUserFavorite(ctx context.Context, userID int, assetID int, asset models.Asset) (err error) {
+	_, span := telemetry.Start(ctx, "InMemoryUserRepository.EditUserFavorite",
+		telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID), telemetry.AssetTypeKey.String(string(asset.GetType())))
+	defer func() { telemetry.End(span, err) }()
+
 	// Lock the Users map for writing
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
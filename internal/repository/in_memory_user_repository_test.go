@@ -1,12 +1,14 @@
 package repository_test
 
 import (
+	"context"
 	"testing"
 
 	"sync"
 
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/repositorytest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,16 +28,52 @@ func TestGenerateSampleUsers(t *testing.T) {
 	}
 }
 
+func TestReplaceUsers(t *testing.T) {
+	repo := setup()
+
+	// Test the users are replaced by the given ones
+	assert.NoError(t, repo.ReplaceUsers(context.Background(), repositorytest.Users()))
+	ids, _ := repo.GetUserIDs(context.Background())
+	assert.Equal(t, []int{1, 2, 10}, ids)
+	favorites, _ := repo.GetUserFavorites(context.Background(), 1)
+	assert.Len(t, favorites, 2)
+
+	// Test changing the given users afterwards does not change the repository
+	users := repositorytest.Users()
+	assert.NoError(t, repo.ReplaceUsers(context.Background(), users))
+	delete(users, 2)
+	delete(users[1].Favourites, 1)
+	ids, _ = repo.GetUserIDs(context.Background())
+	assert.Equal(t, []int{1, 2, 10}, ids)
+	favorites, _ = repo.GetUserFavorites(context.Background(), 1)
+	assert.Len(t, favorites, 2)
+}
+
+func TestGetUserIDs(t *testing.T) {
+	repo := repository.NewInMemoryUserRepository()
+
+	// Test empty repository
+	ids, err := repo.GetUserIDs(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// Test IDs are returned in ascending order
+	repo.GenerateSampleUsers(5, 1)
+	ids, err = repo.GetUserIDs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
 func TestGetUserFavorites(t *testing.T) {
 	repo := setup()
 
 	// Test existing user
-	favorites, err := repo.GetUserFavorites(1)
+	favorites, err := repo.GetUserFavorites(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, favorites)
 
 	// Test non-existing user
-	_, err = repo.GetUserFavorites(999)
+	_, err = repo.GetUserFavorites(context.Background(), 999)
 	assert.Error(t, err)
 }
 
@@ -49,19 +87,19 @@ func TestAddUserFavorite(t *testing.T) {
 	}
 
 	// Test adding asset to existing user
-	err := repo.AddUserFavorite(1, newAsset)
+	err := repo.AddUserFavorite(context.Background(), 1, newAsset)
 	assert.NoError(t, err)
 
 	// Verify asset was added
-	favorites, _ := repo.GetUserFavorites(1)
+	favorites, _ := repo.GetUserFavorites(context.Background(), 1)
 	assert.Equal(t, newAsset, favorites[2])
 
 	// Test adding existing asset to user
-	err = repo.AddUserFavorite(1, newAsset)
+	err = repo.AddUserFavorite(context.Background(), 1, newAsset)
 	assert.Error(t, err)
 
 	// Test adding asset to non-existing user
-	err = repo.AddUserFavorite(999, newAsset)
+	err = repo.AddUserFavorite(context.Background(), 999, newAsset)
 	assert.Error(t, err)
 }
 
@@ -69,19 +107,19 @@ func TestDeleteUserFavorite(t *testing.T) {
 	repo := setup()
 
 	// Test deleting existing asset
-	err := repo.DeleteUserFavorite(1, 1)
+	err := repo.DeleteUserFavorite(context.Background(), 1, 1)
 	assert.NoError(t, err)
 
 	// Verify asset was deleted
-	favorites, _ := repo.GetUserFavorites(1)
+	favorites, _ := repo.GetUserFavorites(context.Background(), 1)
 	assert.Empty(t, favorites)
 
 	// Test deleting non-existing asset
-	err = repo.DeleteUserFavorite(1, 999)
+	err = repo.DeleteUserFavorite(context.Background(), 1, 999)
 	assert.Error(t, err)
 
 	// Test deleting asset from non-existing user
-	err = repo.DeleteUserFavorite(999, 1)
+	err = repo.DeleteUserFavorite(context.Background(), 999, 1)
 	assert.Error(t, err)
 }
 
@@ -95,30 +133,30 @@ func TestEditUserFavorite(t *testing.T) {
 	}
 
 	// Test editing existing asset
-	err := repo.EditUserFavorite(1, 1, editedAsset)
+	err := repo.EditUserFavorite(context.Background(), 1, 1, editedAsset)
 	assert.NoError(t, err)
 
 	// Verify asset was edited
-	favorites, _ := repo.GetUserFavorites(1)
+	favorites, _ := repo.GetUserFavorites(context.Background(), 1)
 	assert.Equal(t, editedAsset, favorites[1])
 
 	// Test editing non-existing asset
-	err = repo.EditUserFavorite(1, 999, editedAsset)
+	err = repo.EditUserFavorite(context.Background(), 1, 999, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset for non-existing user
-	err = repo.EditUserFavorite(999, 1, editedAsset)
+	err = repo.EditUserFavorite(context.Background(), 999, 1, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset with mismatched ID
 	editedAsset.ID = 999
-	err = repo.EditUserFavorite(1, 1, editedAsset)
+	err = repo.EditUserFavorite(context.Background(), 1, 1, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset with mismatched type
 	editedAsset.ID = 1
 	editedAsset.Type = models.AudienceType
-	err = repo.EditUserFavorite(1, 1, editedAsset)
+	err = repo.EditUserFavorite(context.Background(), 1, 1, editedAsset)
 	assert.Error(t, err)
 }
 
@@ -137,7 +175,7 @@ func TestConcurrentGetUserFavorites(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, err := repo.GetUserFavorites(1)
+			_, err := repo.GetUserFavorites(context.Background(), 1)
 			assert.NoError(t, err)
 		}()
 	}
@@ -166,7 +204,7 @@ func TestConcurrentAddUserFavorite(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := repo.AddUserFavorite(userID, asset)
+			err := repo.AddUserFavorite(context.Background(), userID, asset)
 			if err != nil && err.Error() != "asset already exists" {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -175,7 +213,7 @@ func TestConcurrentAddUserFavorite(t *testing.T) {
 
 	wg.Wait()
 
-	favorites, err := repo.GetUserFavorites(userID)
+	favorites, err := repo.GetUserFavorites(context.Background(), userID)
 	assert.NoError(t, err)
 	assert.Equal(t, 11, len(favorites)) // expect 10 existing assets + 1 new asset
 }
@@ -195,7 +233,7 @@ func TestConcurrentDeleteUserFavorite(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := repo.DeleteUserFavorite(userID, 1)
+			err := repo.DeleteUserFavorite(context.Background(), userID, 1)
 			if err != nil && err.Error() != "asset not found" {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -204,7 +242,7 @@ func TestConcurrentDeleteUserFavorite(t *testing.T) {
 
 	wg.Wait()
 
-	favorites, err := repo.GetUserFavorites(userID)
+	favorites, err := repo.GetUserFavorites(context.Background(), userID)
 	assert.NoError(t, err)
 	assert.Equal(t, 9, len(favorites)) // expect 10 existing assets - 1 deleted asset
 }
@@ -244,7 +282,7 @@ func TestConcurrentEditUserFavorite(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := repo.EditUserFavorite(userID, assetID, editedAsset)
+			err := repo.EditUserFavorite(context.Background(), userID, assetID, editedAsset)
 			if err != nil && err.Error() != "asset not found" {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -254,7 +292,7 @@ func TestConcurrentEditUserFavorite(t *testing.T) {
 	wg.Wait()
 
 	// get user's favorites and verify the asset was edited
-	favorites, err := repo.GetUserFavorites(userID)
+	favorites, err := repo.GetUserFavorites(context.Background(), userID)
 	assert.NoError(t, err)
 	// expect 1 asset with the folowing edited description and text
 	editedFavorite := favorites[assetID]
@@ -264,3 +302,11 @@ func TestConcurrentEditUserFavorite(t *testing.T) {
 	// expect 1 asset in the user's favorites
 	assert.Equal(t, 1, len(favorites))
 }
+
+func TestInMemoryUserRepositoryContract(t *testing.T) {
+	repositorytest.RunContractTests(t, func() repository.UserRepository {
+		repo := repository.NewInMemoryUserRepository()
+		repo.Users = repositorytest.Users()
+		return repo
+	})
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// MaxDeliveriesPerSubscription is how many deliveries the in-memory delivery log keeps per subscription
+const MaxDeliveriesPerSubscription = 1000
+
+// InMemoryWebhookRepository is an in-memory implementation of the WebhookRepository interface
+// InMemoryWebhookRepository contains the webhook subscriptions, the log of their latest deliveries and their dead letters
+type InMemoryWebhookRepository struct {
+	subscriptions map[string]models.WebhookSubscription
+	deliveries    map[string][]models.WebhookDelivery // deliveries per subscription, oldest first
+	deadLetters   map[string][]models.WebhookDelivery // dead letters per subscription, oldest first, kept apart from the bounded log
+	mu            sync.RWMutex                        // mu is a read-write mutex to protect the maps from concurrent access
+}
+
+// NewInMemoryWebhookRepository creates a new instance of InMemoryWebhookRepository
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{
+		subscriptions: make(map[string]models.WebhookSubscription),
+		deliveries:    make(map[string][]models.WebhookDelivery),
+		deadLetters:   make(map[string][]models.WebhookDelivery),
+	}
+}
+
+// AddSubscription stores a new webhook subscription
+func (repo *InMemoryWebhookRepository) AddSubscription(subscription models.WebhookSubscription) error {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.subscriptions[subscription.ID]; ok {
+		return errors.New("subscription already exists")
+	}
+	repo.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+// GetSubscription returns a webhook subscription by its id
+func (repo *InMemoryWebhookRepository) GetSubscription(id string) (models.WebhookSubscription, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	subscription, ok := repo.subscriptions[id]
+	if !ok {
+		return models.WebhookSubscription{}, errors.New("subscription not found")
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions returns every webhook subscription, oldest first
+func (repo *InMemoryWebhookRepository) GetSubscriptions() ([]models.WebhookSubscription, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	subscriptions := make([]models.WebhookSubscription, 0, len(repo.subscriptions))
+	for _, subscription := range repo.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+// DeleteSubscription removes a webhook subscription together with its deliveries and dead letters
+func (repo *InMemoryWebhookRepository) DeleteSubscription(id string) error {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.subscriptions[id]; !ok {
+		return errors.New("subscription not found")
+	}
+	delete(repo.subscriptions, id)
+	delete(repo.deliveries, id)
+	delete(repo.deadLetters, id)
+	return nil
+}
+
+// SaveDelivery adds a delivery to the log of its subscription, or updates it if it is already there.
+// The oldest deliveries are dropped once the log holds MaxDeliveriesPerSubscription deliveries, and are not added again
+// by their later updates, told apart from the new deliveries by their attempts. Dead deliveries are also kept
+// as dead letters, which are never dropped.
+func (repo *InMemoryWebhookRepository) SaveDelivery(delivery models.WebhookDelivery) error {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.subscriptions[delivery.SubscriptionID]; !ok {
+		return errors.New("subscription not found")
+	}
+
+	// Store a copy of the attempts, so the caller can keep appending to its own slice
+	delivery.Attempts = append([]models.WebhookAttempt(nil), delivery.Attempts...)
+
+	deliveries := repo.deliveries[delivery.SubscriptionID]
+	logged := false
+	for i := range deliveries {
+		if deliveries[i].ID == delivery.ID {
+			// The event of a delivery never changes, so updates keep the stored one in case it was anonymized meanwhile
+			delivery.Event = deliveries[i].Event
+			deliveries[i] = delivery
+			logged = true
+			break
+		}
+	}
+	if delivery.Status == models.DeliveryDead {
+		repo.deadLetters[delivery.SubscriptionID] = replaceDelivery(repo.deadLetters[delivery.SubscriptionID], delivery)
+	}
+	if logged || len(delivery.Attempts) > 0 {
+		return nil
+	}
+
+	deliveries = append(deliveries, delivery)
+	if len(deliveries) > MaxDeliveriesPerSubscription {
+		deliveries = deliveries[len(deliveries)-MaxDeliveriesPerSubscription:]
+	}
+	repo.deliveries[delivery.SubscriptionID] = deliveries
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a subscription, most recent first
+func (repo *InMemoryWebhookRepository) GetDeliveries(subscriptionID string) ([]models.WebhookDelivery, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if _, ok := repo.subscriptions[subscriptionID]; !ok {
+		return nil, errors.New("subscription not found")
+	}
+
+	stored := repo.deliveries[subscriptionID]
+	deliveries := make([]models.WebhookDelivery, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, stored[i])
+	}
+	return deliveries, nil
+}
+
+// GetDeadLetters returns the deliveries of every subscription that failed all their attempts, most recent first
+func (repo *InMemoryWebhookRepository) GetDeadLetters() ([]models.WebhookDelivery, error) {
+	// Lock the maps for reading
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	deadLetters := make([]models.WebhookDelivery, 0)
+	for _, deliveries := range repo.deadLetters {
+		deadLetters = append(deadLetters, deliveries...)
+	}
+	sort.Slice(deadLetters, func(i, j int) bool {
+		if !deadLetters[i].CreatedAt.Equal(deadLetters[j].CreatedAt) {
+			return deadLetters[i].CreatedAt.After(deadLetters[j].CreatedAt)
+		}
+		return deadLetters[i].ID < deadLetters[j].ID
+	})
+	return deadLetters, nil
+}
+
+// AnonymizeUserDeliveries removes the user, the actor and the asset from the events of the deliveries and dead letters
+// to a user, keeping the outcome of the deliveries, and returns how many deliveries were anonymized
+func (repo *InMemoryWebhookRepository) AnonymizeUserDeliveries(userID int) (int, error) {
+	// Lock the maps for writing
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// Count the deliveries by id, since a dead letter can also be in the delivery log
+	anonymized := make(map[string]bool)
+	for _, stored := range []map[string][]models.WebhookDelivery{repo.deliveries, repo.deadLetters} {
+		for _, deliveries := range stored {
+			for i := range deliveries {
+				if deliveries[i].Event.UserID == userID {
+					deliveries[i].Event.UserID = 0
+					deliveries[i].Event.Actor = ""
+					deliveries[i].Event.Asset = nil
+					anonymized[deliveries[i].ID] = true
+				}
+			}
+		}
+	}
+	return len(anonymized), nil
+}
+
+// replaceDelivery replaces the delivery with the same id in deliveries, or appends the delivery if there is none
+func replaceDelivery(deliveries []models.WebhookDelivery, delivery models.WebhookDelivery) []models.WebhookDelivery {
+	for i := range deliveries {
+		if deliveries[i].ID == delivery.ID {
+			deliveries[i] = delivery
+			return deliveries
+		}
+	}
+	return append(deliveries, delivery)
+}
@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	repo := repository.NewInMemoryWebhookRepository()
+	now := time.Now()
+
+	assert.NoError(t, repo.AddSubscription(models.WebhookSubscription{ID: "b", URL: "http://b", CreatedAt: now.Add(time.Second)}))
+	assert.NoError(t, repo.AddSubscription(models.WebhookSubscription{ID: "a", URL: "http://a", CreatedAt: now}))
+	assert.EqualError(t, repo.AddSubscription(models.WebhookSubscription{ID: "a"}), "subscription already exists")
+
+	// Test subscriptions are returned oldest first
+	subscriptions, err := repo.GetSubscriptions()
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, "a", subscriptions[0].ID)
+
+	subscription, err := repo.GetSubscription("b")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://b", subscription.URL)
+
+	assert.NoError(t, repo.DeleteSubscription("b"))
+	_, err = repo.GetSubscription("b")
+	assert.EqualError(t, err, "subscription not found")
+	assert.EqualError(t, repo.DeleteSubscription("b"), "subscription not found")
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repo := repository.NewInMemoryWebhookRepository()
+	now := time.Now()
+	repo.AddSubscription(models.WebhookSubscription{ID: "a"})
+
+	// Test deliveries are added once and then updated
+	delivery := models.WebhookDelivery{ID: "1", SubscriptionID: "a", Status: models.DeliveryPending, CreatedAt: now}
+	assert.NoError(t, repo.SaveDelivery(delivery))
+	delivery.Status = models.DeliveryDead
+	delivery.Attempts = []models.WebhookAttempt{{Number: 1, Error: "timeout"}}
+	assert.NoError(t, repo.SaveDelivery(delivery))
+	assert.NoError(t, repo.SaveDelivery(models.WebhookDelivery{ID: "2", SubscriptionID: "a", Status: models.DeliverySucceeded, CreatedAt: now.Add(time.Second)}))
+
+	deliveries, err := repo.GetDeliveries("a")
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, "2", deliveries[0].ID)
+	assert.Equal(t, models.DeliveryDead, deliveries[1].Status)
+
+	// Test only the deliveries failing every attempt are dead letters
+	deadLetters, err := repo.GetDeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "1", deadLetters[0].ID)
+
+	// Test the delivery log is bounded
+	for i := 0; i < repository.MaxDeliveriesPerSubscription; i++ {
+		repo.SaveDelivery(models.WebhookDelivery{ID: strconv.Itoa(i + 10), SubscriptionID: "a"})
+	}
+	deliveries, _ = repo.GetDeliveries("a")
+	assert.Len(t, deliveries, repository.MaxDeliveriesPerSubscription)
+
+	// Test the dead letters are kept once dropped from the delivery log
+	deadLetters, _ = repo.GetDeadLetters()
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "1", deadLetters[0].ID)
+
+	// Test unknown subscriptions
+	assert.EqualError(t, repo.SaveDelivery(models.WebhookDelivery{ID: "3", SubscriptionID: "b"}), "subscription not found")
+	_, err = repo.GetDeliveries("b")
+	assert.EqualError(t, err, "subscription not found")
+}
+
+func TestWebhookDeliveriesEviction(t *testing.T) {
+	repo := repository.NewInMemoryWebhookRepository()
+	repo.AddSubscription(models.WebhookSubscription{ID: "a"})
+	retrying := models.WebhookDelivery{ID: "retrying", SubscriptionID: "a", Status: models.DeliveryPending}
+	repo.SaveDelivery(retrying)
+	for i := 0; i < repository.MaxDeliveriesPerSubscription; i++ {
+		repo.SaveDelivery(models.WebhookDelivery{ID: strconv.Itoa(i), SubscriptionID: "a", Status: models.DeliverySucceeded})
+	}
+
+	// Test an update of a delivery dropped from the log does not add it again
+	retrying.Status = models.DeliveryRetrying
+	retrying.Attempts = []models.WebhookAttempt{{Number: 1, Error: "timeout"}}
+	assert.NoError(t, repo.SaveDelivery(retrying))
+	deliveries, _ := repo.GetDeliveries("a")
+	assert.Len(t, deliveries, repository.MaxDeliveriesPerSubscription)
+	assert.Equal(t, strconv.Itoa(repository.MaxDeliveriesPerSubscription-1), deliveries[0].ID)
+
+	// Test it is still kept as a dead letter once every attempt failed
+	retrying.Status = models.DeliveryDead
+	assert.NoError(t, repo.SaveDelivery(retrying))
+	deadLetters, err := repo.GetDeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "retrying", deadLetters[0].ID)
+	deliveries, _ = repo.GetDeliveries("a")
+	assert.Len(t, deliveries, repository.MaxDeliveriesPerSubscription)
+
+	// Test the dead letters are removed with their subscription
+	assert.NoError(t, repo.DeleteSubscription("a"))
+	deadLetters, _ = repo.GetDeadLetters()
+	assert.Empty(t, deadLetters)
+}
+
+func TestAnonymizeUserDeliveries(t *testing.T) {
+	repo := repository.NewInMemoryWebhookRepository()
+	repo.AddSubscription(models.WebhookSubscription{ID: "a"})
+	event := models.FavoriteEvent{Type: models.EventFavoriteAdded, UserID: 1, AssetID: 1, Actor: "user:alice", Asset: models.Insight{ID: 1, Text: "Personal"}}
+	repo.SaveDelivery(models.WebhookDelivery{ID: "1", SubscriptionID: "a", Event: event, Status: models.DeliveryRetrying})
+	other := event
+	other.UserID = 2
+	repo.SaveDelivery(models.WebhookDelivery{ID: "2", SubscriptionID: "a", Event: other, Status: models.DeliverySucceeded})
+
+	// Test only the deliveries to the user are anonymized
+	anonymized, err := repo.AnonymizeUserDeliveries(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, anonymized)
+
+	// Test updating a delivery in flight keeps it anonymized
+	repo.SaveDelivery(models.WebhookDelivery{ID: "1", SubscriptionID: "a", Event: event, Status: models.DeliverySucceeded})
+	deliveries, _ := repo.GetDeliveries("a")
+	assert.Equal(t, models.FavoriteEvent{Type: models.EventFavoriteAdded, AssetID: 1}, deliveries[1].Event)
+	assert.Equal(t, models.DeliverySucceeded, deliveries[1].Status)
+	assert.Equal(t, other, deliveries[0].Event)
+
+	// Test dead letters are anonymized too, counted once with their delivery
+	repo.SaveDelivery(models.WebhookDelivery{ID: "3", SubscriptionID: "a", Event: other, Status: models.DeliveryDead})
+	anonymized, err = repo.AnonymizeUserDeliveries(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, anonymized)
+	deadLetters, _ := repo.GetDeadLetters()
+	assert.Zero(t, deadLetters[0].Event.UserID)
+}
@@ -0,0 +1,18 @@
+-- Users and their favourite assets. The type and description of an asset are typed columns,
+-- so they can be filtered and indexed, and the whole asset is kept as a JSONB payload.
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY CHECK (id > 0)
+);
+
+CREATE TABLE favorites (
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    asset_id    INTEGER     NOT NULL,
+    asset_type  TEXT        NOT NULL CHECK (asset_type IN ('Chart', 'Insight', 'Audience')),
+    description TEXT        NOT NULL DEFAULT '',
+    payload     JSONB       NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, asset_id)
+);
+
+CREATE INDEX favorites_user_id_asset_type ON favorites (user_id, asset_type);
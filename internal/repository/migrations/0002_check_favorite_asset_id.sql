@@ -0,0 +1,3 @@
+-- Assets are addressed by their ID in the API paths, which must be positive. The constraint is not validated
+-- against the existing favourites, so the ones stored before it keep migrating and can be cleaned up separately.
+ALTER TABLE favorites ADD CONSTRAINT favorites_asset_id_positive CHECK (asset_id > 0) NOT VALID;
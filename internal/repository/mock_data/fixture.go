@@ -0,0 +1,77 @@
+package mock_data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+// fixtureUser is a user in a fixture file, with its favourites as a JSON array of assets ordered by ID
+type fixtureUser struct {
+	ID         int               `json:"id"`
+	Favourites []json.RawMessage `json:"favourites"`
+}
+
+// WriteFixture writes the users as a JSON array ordered by user ID, so the same users always give the same file
+func WriteFixture(w io.Writer, users map[int]models.User) error {
+	ids := make([]int, 0, len(users))
+	for id := range users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	fixture := make([]fixtureUser, 0, len(ids))
+	for _, id := range ids {
+		assetIDs := make([]int, 0, len(users[id].Favourites))
+		for assetID := range users[id].Favourites {
+			assetIDs = append(assetIDs, assetID)
+		}
+		sort.Ints(assetIDs)
+
+		user := fixtureUser{ID: id, Favourites: make([]json.RawMessage, 0, len(assetIDs))}
+		for _, assetID := range assetIDs {
+			data, err := json.Marshal(users[id].Favourites[assetID])
+			if err != nil {
+				return err
+			}
+			user.Favourites = append(user.Favourites, data)
+		}
+		fixture = append(fixture, user)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(fixture)
+}
+
+// ReadFixture reads the users written by WriteFixture
+func ReadFixture(r io.Reader) (map[int]models.User, error) {
+	var fixture []fixtureUser
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, err
+	}
+
+	users := make(map[int]models.User, len(fixture))
+	for _, u := range fixture {
+		if _, ok := users[u.ID]; ok {
+			return nil, fmt.Errorf("user %d: duplicate user", u.ID)
+		}
+		user := models.User{ID: u.ID, Favourites: make(map[int]models.Asset, len(u.Favourites))}
+		for _, data := range u.Favourites {
+			asset, err := utils.DecodeAsset(data)
+			if err != nil {
+				return nil, fmt.Errorf("user %d: %v", u.ID, err)
+			}
+			if _, ok := user.Favourites[asset.GetID()]; ok {
+				return nil, fmt.Errorf("user %d: duplicate asset %d", u.ID, asset.GetID())
+			}
+			user.Favourites[asset.GetID()] = asset
+		}
+		users[u.ID] = user
+	}
+	return users, nil
+}
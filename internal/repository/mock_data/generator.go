@@ -0,0 +1,266 @@
+package mock_data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// DefaultSeed is the seed of the sample data generated when no seed is given
+const DefaultSeed = 1
+
+// TypeMix weights the asset types of the generated favourites, e.g. {Chart: 2, Insight: 1, Audience: 1}
+// generates twice as many charts as insights or audiences
+type TypeMix struct {
+	Chart    float64 `json:"chart"`
+	Insight  float64 `json:"insight"`
+	Audience float64 `json:"audience"`
+}
+
+// GeneratorConfig describes the sample data of a Generator
+type GeneratorConfig struct {
+	Seed          int64   `json:"seed"` // the same seed and settings always generate the same users
+	Users         int     `json:"users"`
+	AssetsPerUser int     `json:"assetsPerUser"`
+	TypeMix       TypeMix `json:"typeMix"`
+	SharedRatio   float64 `json:"sharedRatio"`  // share of each user's favourites picked from the shared assets, the rest are unique to the user
+	SharedAssets  int     `json:"sharedAssets"` // number of shared assets, zero uses AssetsPerUser
+	MinPoints     int     `json:"minPoints"`    // data points of the generated charts
+	MaxPoints     int     `json:"maxPoints"`
+}
+
+// DefaultGeneratorConfig returns the settings of the generator for users with assets each
+func DefaultGeneratorConfig(users, assets int) GeneratorConfig {
+	return GeneratorConfig{
+		Seed:          DefaultSeed,
+		Users:         users,
+		AssetsPerUser: assets,
+		TypeMix:       TypeMix{Chart: 1, Insight: 1, Audience: 1},
+		SharedRatio:   0.3,
+		MinPoints:     3,
+		MaxPoints:     12,
+	}
+}
+
+// Validate checks the settings can generate users
+func (c GeneratorConfig) Validate() error {
+	switch {
+	case c.Users < 0 || c.AssetsPerUser < 0 || c.SharedAssets < 0:
+		return errors.New("the numbers of users and assets cannot be negative")
+	case c.TypeMix.Chart < 0 || c.TypeMix.Insight < 0 || c.TypeMix.Audience < 0:
+		return errors.New("the type mix weights cannot be negative")
+	case c.TypeMix.Chart+c.TypeMix.Insight+c.TypeMix.Audience == 0:
+		return errors.New("the type mix needs at least one positive weight")
+	case c.SharedRatio < 0 || c.SharedRatio > 1:
+		return errors.New("the shared ratio must be between 0 and 1")
+	case c.MinPoints < 1 || c.MaxPoints < c.MinPoints:
+		return errors.New("the chart points must be at least 1 and at most the maximum")
+	}
+	return nil
+}
+
+// Generator generates reproducible sample users from its own random source
+type Generator struct {
+	Config GeneratorConfig
+	rand   *rand.Rand
+}
+
+// NewGenerator creates a new instance of Generator seeded with config.Seed
+func NewGenerator(config GeneratorConfig) *Generator {
+	return &Generator{
+		Config: config,
+		rand:   rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// Generate returns the users with IDs 1..Users, each with AssetsPerUser favourites.
+// The shared assets have the IDs 1..SharedAssets and the same content for every user who picks them,
+// the most popular ones being picked by most users, while the unique assets have IDs above them, never reused.
+func (g *Generator) Generate() (map[int]models.User, error) {
+	if err := g.Config.Validate(); err != nil {
+		return nil, err
+	}
+
+	poolSize := g.Config.SharedAssets
+	if poolSize == 0 {
+		poolSize = g.Config.AssetsPerUser
+	}
+	pool := make([]models.Asset, poolSize)
+	for i := range pool {
+		pool[i] = g.Asset(i + 1)
+	}
+
+	shared := int(math.Round(float64(g.Config.AssetsPerUser) * g.Config.SharedRatio))
+	if shared > poolSize {
+		shared = poolSize
+	}
+
+	users := make(map[int]models.User, g.Config.Users)
+	nextID := poolSize + 1
+	for userID := 1; userID <= g.Config.Users; userID++ {
+		user := models.User{
+			ID:         userID,
+			Favourites: make(map[int]models.Asset, g.Config.AssetsPerUser),
+		}
+		for _, index := range g.pickShared(shared, poolSize) {
+			asset := copyAsset(pool[index])
+			user.Favourites[asset.GetID()] = asset
+		}
+		for len(user.Favourites) < g.Config.AssetsPerUser {
+			user.Favourites[nextID] = g.Asset(nextID)
+			nextID++
+		}
+		users[userID] = user
+	}
+	return users, nil
+}
+
+// pickShared picks n distinct indexes of the shared assets, skewed towards the first ones so a few assets are
+// favourites of many users
+func (g *Generator) pickShared(n, size int) []int {
+	if n == 0 {
+		return nil
+	}
+	if n*2 > size {
+		return g.rand.Perm(size)[:n]
+	}
+
+	zipf := rand.NewZipf(g.rand, 1.2, 1, uint64(size-1))
+	picked := make(map[int]bool, n)
+	indexes := make([]int, 0, n)
+	for draws := 0; len(indexes) < n && draws < 20*n; draws++ {
+		index := int(zipf.Uint64())
+		if !picked[index] {
+			picked[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+
+	// The long tail is rarely drawn, pick the rest uniformly
+	for _, index := range g.rand.Perm(size) {
+		if len(indexes) == n {
+			break
+		}
+		if !picked[index] {
+			picked[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// Asset generates an asset with the given ID, of a type picked by the type mix
+func (g *Generator) Asset(id int) models.Asset {
+	mix := g.Config.TypeMix
+	weight := g.rand.Float64() * (mix.Chart + mix.Insight + mix.Audience)
+	switch {
+	case weight < mix.Chart:
+		return g.chart(id)
+	case weight < mix.Chart+mix.Insight:
+		return g.insight(id)
+	default:
+		return g.audience(id)
+	}
+}
+
+// Topics of the generated charts and insights
+var topics = []string{
+	"social media usage", "online purchases", "streaming subscriptions", "mobile gaming",
+	"podcast listening", "news consumption", "ad blocking", "voice assistants",
+}
+
+func (g *Generator) chart(id int) *models.Chart {
+	topic := topics[g.rand.Intn(len(topics))]
+	return &models.Chart{
+		ID:          id,
+		Type:        models.ChartType,
+		Description: fmt.Sprintf("Monthly trend of %s", topic),
+		Title:       fmt.Sprintf("GWI Chart %d: %s", id, topic),
+		XAxesTitle:  "Month",
+		YAxesTitle:  "Share of respondents (%)",
+		DataPoints:  g.trend(g.Config.MinPoints, g.Config.MaxPoints),
+	}
+}
+
+// trend generates the points of a random walk around a starting share, one point per month
+func (g *Generator) trend(minPoints, maxPoints int) []models.Point {
+	points := make([]models.Point, minPoints+g.rand.Intn(maxPoints-minPoints+1))
+	y := 20 + g.rand.Float64()*60
+	for i := range points {
+		y = math.Min(100, math.Max(0, y+g.rand.NormFloat64()*3))
+		points[i] = models.Point{X: float32(i + 1), Y: float32(math.Round(y*10) / 10)}
+	}
+	return points
+}
+
+func (g *Generator) insight(id int) *models.Insight {
+	topic := topics[g.rand.Intn(len(topics))]
+	country := g.country()
+	share := 5 + g.rand.Intn(90)
+	return &models.Insight{
+		ID:          id,
+		Type:        models.InsightType,
+		Description: fmt.Sprintf("Insight on %s in %s", topic, country),
+		Text:        fmt.Sprintf("%d%% of internet users in %s mention %s in the last month", share, country, topic),
+	}
+}
+
+func (g *Generator) audience(id int) *models.Audience {
+	// Ages follow a normal distribution of internet users clamped to 16..80, daily hours on media cluster
+	// around 7 and the purchases of the last month decay exponentially
+	age := uint(math.Min(80, math.Max(16, math.Round(38+g.rand.NormFloat64()*14))))
+	hours := uint(math.Min(24, math.Max(0, math.Round(7+g.rand.NormFloat64()*2.5))))
+	purchases := uint(math.Round(g.rand.ExpFloat64() * 4))
+	return &models.Audience{
+		ID:                id,
+		Type:              models.AudienceType,
+		Description:       "Sample Audience for GWI",
+		Age:               age,
+		AgeGroup:          models.AgeGroupForAge(age),
+		Gender:            g.gender(),
+		BirthCountry:      g.country(),
+		HoursSpentOnMedia: hours,
+		NumberOfPurchases: purchases,
+	}
+}
+
+func (g *Generator) gender() string {
+	switch weight := g.rand.Float64(); {
+	case weight < 0.49:
+		return models.GenderMale
+	case weight < 0.97:
+		return models.GenderFemale
+	default:
+		return models.GenderNonBinary
+	}
+}
+
+// Largest markets, picked for most of the generated countries
+var markets = []string{"US", "GB", "DE", "FR", "BR", "IN", "JP", "CN", "ES", "IT", "CA", "MX", "GR"}
+
+func (g *Generator) country() string {
+	if g.rand.Float64() < 0.7 {
+		return markets[g.rand.Intn(len(markets))]
+	}
+	return models.Countries[g.rand.Intn(len(models.Countries))].Code
+}
+
+// copyAsset copies an asset, so users sharing it do not share changes to it
+func copyAsset(asset models.Asset) models.Asset {
+	switch a := asset.(type) {
+	case *models.Chart:
+		chart := *a
+		chart.DataPoints = append([]models.Point(nil), a.DataPoints...)
+		return &chart
+	case *models.Insight:
+		insight := *a
+		return &insight
+	case *models.Audience:
+		audience := *a
+		return &audience
+	}
+	return asset
+}
@@ -0,0 +1,168 @@
+package mock_data_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func generate(t *testing.T, config mock_data.GeneratorConfig) map[int]models.User {
+	users, err := mock_data.NewGenerator(config).Generate()
+	assert.NoError(t, err)
+	return users
+}
+
+func TestGeneratorIsReproducible(t *testing.T) {
+	config := mock_data.DefaultGeneratorConfig(20, 10)
+
+	// Test the same seed generates the same users and another seed different ones
+	assert.Equal(t, generate(t, config), generate(t, config))
+	config.Seed = 2
+	assert.NotEqual(t, generate(t, mock_data.DefaultGeneratorConfig(20, 10)), generate(t, config))
+
+	// Test GenerateMockData always returns the same users
+	assert.Equal(t, mock_data.GenerateMockData(5, 6), mock_data.GenerateMockData(5, 6))
+}
+
+func TestGeneratorLayout(t *testing.T) {
+	config := mock_data.DefaultGeneratorConfig(50, 10)
+	config.SharedRatio = 0.4
+	config.SharedAssets = 30
+	users := generate(t, config)
+
+	// Test every user has its favourites, 4 of them shared and the rest unique to the user
+	assert.Len(t, users, 50)
+	owners := make(map[int]int)
+	shared := make(map[int]models.Asset)
+	for id, user := range users {
+		assert.Equal(t, id, user.ID)
+		assert.Len(t, user.Favourites, 10)
+
+		sharedCount := 0
+		for assetID, asset := range user.Favourites {
+			assert.Equal(t, assetID, asset.GetID())
+			if assetID <= 30 {
+				sharedCount++
+				// Test the shared assets are the same for every user, but not the same pointer
+				if first, ok := shared[assetID]; ok {
+					assert.Equal(t, first, asset)
+					assert.NotSame(t, first, asset)
+				}
+				shared[assetID] = asset
+			} else {
+				owners[assetID]++
+			}
+		}
+		assert.Equal(t, 4, sharedCount)
+	}
+	for assetID, count := range owners {
+		assert.Equal(t, 1, count, "asset %d is not unique", assetID)
+	}
+
+	// Test the generated users are valid assets within their ranges
+	for _, user := range users {
+		for _, asset := range user.Favourites {
+			switch a := asset.(type) {
+			case *models.Audience:
+				assert.GreaterOrEqual(t, a.Age, uint(16))
+				assert.LessOrEqual(t, a.Age, uint(80))
+				normalized := *a
+				assert.NoError(t, models.NormalizeAudience(&normalized))
+				assert.Equal(t, *a, normalized)
+				assert.LessOrEqual(t, a.HoursSpentOnMedia, uint(24))
+			case *models.Chart:
+				assert.GreaterOrEqual(t, len(a.DataPoints), config.MinPoints)
+				assert.LessOrEqual(t, len(a.DataPoints), config.MaxPoints)
+			}
+		}
+	}
+}
+
+func TestGeneratorOverlap(t *testing.T) {
+	// Test no shared favourites give every user unique assets
+	config := mock_data.DefaultGeneratorConfig(10, 5)
+	config.SharedRatio = 0
+	for _, user := range generate(t, config) {
+		for assetID := range user.Favourites {
+			assert.Greater(t, assetID, 5)
+		}
+	}
+
+	// Test only shared favourites give every user the same assets
+	config.SharedRatio = 1
+	for _, user := range generate(t, config) {
+		for assetID := 1; assetID <= 5; assetID++ {
+			assert.Contains(t, user.Favourites, assetID)
+		}
+	}
+}
+
+func TestGeneratorTypeMix(t *testing.T) {
+	count := func(users map[int]models.User) map[models.AssetType]int {
+		counts := make(map[models.AssetType]int)
+		for _, user := range users {
+			for _, asset := range user.Favourites {
+				counts[asset.GetType()]++
+			}
+		}
+		return counts
+	}
+
+	// Test a single type generates only assets of that type
+	config := mock_data.DefaultGeneratorConfig(10, 10)
+	config.TypeMix = mock_data.TypeMix{Insight: 1}
+	assert.Equal(t, map[models.AssetType]int{models.InsightType: 100}, count(generate(t, config)))
+
+	// Test the types follow their weights
+	config = mock_data.DefaultGeneratorConfig(100, 100)
+	config.SharedRatio = 0
+	config.TypeMix = mock_data.TypeMix{Chart: 3, Insight: 1}
+	counts := count(generate(t, config))
+	assert.InDelta(t, 7500, counts[models.ChartType], 300)
+	assert.InDelta(t, 2500, counts[models.InsightType], 300)
+	assert.Zero(t, counts[models.AudienceType])
+}
+
+func TestGeneratorValidate(t *testing.T) {
+	invalid := []func(*mock_data.GeneratorConfig){
+		func(c *mock_data.GeneratorConfig) { c.Users = -1 },
+		func(c *mock_data.GeneratorConfig) { c.TypeMix = mock_data.TypeMix{} },
+		func(c *mock_data.GeneratorConfig) { c.TypeMix.Chart = -1 },
+		func(c *mock_data.GeneratorConfig) { c.SharedRatio = 1.5 },
+		func(c *mock_data.GeneratorConfig) { c.MinPoints, c.MaxPoints = 5, 2 },
+	}
+	for _, change := range invalid {
+		config := mock_data.DefaultGeneratorConfig(1, 1)
+		change(&config)
+		_, err := mock_data.NewGenerator(config).Generate()
+		assert.Error(t, err)
+	}
+}
+
+func TestFixture(t *testing.T) {
+	users := generate(t, mock_data.DefaultGeneratorConfig(5, 4))
+
+	// Test the fixture reads back the users it was written from, always written the same way
+	var first, second bytes.Buffer
+	assert.NoError(t, mock_data.WriteFixture(&first, users))
+	assert.NoError(t, mock_data.WriteFixture(&second, users))
+	assert.Equal(t, first.String(), second.String())
+
+	read, err := mock_data.ReadFixture(&first)
+	assert.NoError(t, err)
+	assert.Equal(t, users, read)
+
+	// Test invalid fixtures
+	for _, fixture := range []string{
+		`{"id": 1}`,
+		`[{"id": 1, "favourites": []}, {"id": 1, "favourites": []}]`,
+		`[{"id": 1, "favourites": [{"id": 1, "type": "Unknown"}]}]`,
+		`[{"id": 1, "favourites": [{"id": 1, "type": "Insight"}, {"id": 1, "type": "Insight"}]}]`,
+	} {
+		_, err := mock_data.ReadFixture(bytes.NewBufferString(fixture))
+		assert.Error(t, err, fixture)
+	}
+}
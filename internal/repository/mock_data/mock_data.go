@@ -1,26 +1,25 @@
 package mock_data
 
 import (
-	"fmt"
 	"math/rand"
 
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 )
 
-// Age groups
+// Age groups, defined by the reference data in the models package
 const (
-	AgeGroupTeen       string = "0-17"
-	AgeGroupYoungAdult string = "18-25"
-	AgeGroupAdult      string = "26-40"
-	AgeGroupMiddleAged string = "41-65"
-	AgeGroupSenior     string = "66+"
+	AgeGroupTeen       = models.AgeGroupTeen
+	AgeGroupYoungAdult = models.AgeGroupYoungAdult
+	AgeGroupAdult      = models.AgeGroupAdult
+	AgeGroupMiddleAged = models.AgeGroupMiddleAged
+	AgeGroupSenior     = models.AgeGroupSenior
 )
 
-// Genders
+// Genders, defined by the reference data in the models package
 const (
-	Male      string = "Male"
-	Female    string = "Female"
-	NonBinary string = "Non-Binary"
+	Male      = models.GenderMale
+	Female    = models.GenderFemale
+	NonBinary = models.GenderNonBinary
 )
 
 // GetRandomNumber returns a random number in the [0, n] range
@@ -43,44 +42,24 @@ func GetRandomPoints(MinPoints, MaxPoints int) []models.Point {
 
 // GetRandomAgeGroup returns a random age group
 func GetRandomAgeGroup() string {
-	ageGroups := []string{AgeGroupTeen, AgeGroupAdult, AgeGroupSenior}
-	return ageGroups[rand.Intn(len(ageGroups))]
+	return models.AgeGroups[rand.Intn(len(models.AgeGroups))].Name
 }
 
 // GetRandomGender returns a random gender
 func GetRandomGender() string {
-	genders := []string{Male, Female, NonBinary}
-	return genders[rand.Intn(len(genders))]
+	return models.Genders[rand.Intn(len(models.Genders))]
 }
 
-// GetRandomCountry returns a random country from the predefined list
+// GetRandomCountry returns the ISO 3166-1 alpha-2 code of a random country
 func GetRandomCountry() string {
-	countries := []string{
-		"Afghanistan", "Albania", "Algeria", "Andorra", "Angola", "Argentina", "Armenia", "Australia", "Austria", "Azerbaijan",
-		"Bahamas", "Bahrain", "Bangladesh", "Barbados", "Belarus", "Belgium", "Belize", "Benin", "Bhutan", "Bolivia",
-		"Bosnia and Herzegovina", "Botswana", "Brazil", "Brunei", "Bulgaria", "Burkina Faso", "Burundi", "Cambodia", "Cameroon", "Canada",
-		"Cape Verde", "Central African Republic", "Chad", "Chile", "China", "Colombia", "Comoros", "Congo", "Costa Rica", "Croatia",
-		"Cuba", "Cyprus", "Czech Republic", "Denmark", "Djibouti", "Dominica", "Dominican Republic", "Ecuador", "Egypt", "El Salvador",
-		"Equatorial Guinea", "Eritrea", "Estonia", "Eswatini", "Ethiopia", "Fiji", "Finland", "France", "Gabon", "Gambia",
-		"Georgia", "Germany", "Ghana", "Greece", "Grenada", "Guatemala", "Guinea", "Guinea-Bissau", "Guyana", "Haiti",
-		"Honduras", "Hungary", "Iceland", "India", "Indonesia", "Iran", "Iraq", "Ireland", "Israel", "Italy",
-		"Jamaica", "Japan", "Jordan", "Kazakhstan", "Kenya", "Kiribati", "Kuwait", "Kyrgyzstan", "Laos", "Latvia",
-		"Lebanon", "Lesotho", "Liberia", "Libya", "Liechtenstein", "Lithuania", "Luxembourg", "Madagascar", "Malawi", "Malaysia",
-		"Maldives", "Mali", "Malta", "Marshall Islands", "Mauritania", "Mauritius", "Mexico", "Micronesia", "Moldova", "Monaco",
-		"Mongolia", "Montenegro", "Morocco", "Mozambique", "Myanmar", "Namibia", "Nauru", "Nepal", "Netherlands", "New Zealand",
-		"Nicaragua", "Niger", "Nigeria", "North Macedonia", "Norway", "Oman", "Pakistan", "Palau", "Panama", "Papua New Guinea",
-		"Paraguay", "Peru", "Philippines", "Poland", "Portugal", "Qatar", "Romania", "Russia", "Rwanda", "Saint Kitts and Nevis",
-		"Saint Lucia", "Saint Vincent and the Grenadines", "Samoa", "San Marino", "Sao Tome and Principe", "Saudi Arabia", "Senegal", "Serbia", "Seychelles", "Sierra Leone",
-		"Singapore", "Slovakia", "Slovenia", "Solomon Islands", "Somalia", "South Africa", "South Sudan", "Spain", "Sri Lanka", "Sudan",
-		"Suriname", "Sweden", "Switzerland", "Syria", "Taiwan", "Tajikistan", "Tanzania", "Thailand", "Timor-Leste", "Togo",
-		"Tonga", "Trinidad and Tobago", "Tunisia", "Turkey", "Turkmenistan", "Tuvalu", "Uganda", "Ukraine", "United Arab Emirates", "United Kingdom",
-		"United States", "Uruguay", "Uzbekistan", "Vanuatu", "Vatican City", "Venezuela", "Vietnam", "Yemen", "Zambia", "Zimbabwe",
-	}
-	return countries[rand.Intn(len(countries))]
+	return models.Countries[rand.Intn(len(models.Countries))].Code
 }
 
-// GenerateMockData generates mock data for users and assets and returns a map of users
+// GenerateMockData generates the users 1..NumberOfUsers, each with the assets 1..NumberOfAssets,
+// cycling through the Insight, Audience and Chart types. The values are generated from DefaultSeed,
+// so every call returns the same users. Use a Generator for a different layout.
 func GenerateMockData(NumberOfUsers, NumberOfAssets int) map[int]models.User {
+	g := NewGenerator(DefaultGeneratorConfig(NumberOfUsers, NumberOfAssets))
 	Users := make(map[int]models.User)
 
 	for i := 1; i <= NumberOfUsers; i++ {
@@ -93,40 +72,14 @@ func GenerateMockData(NumberOfUsers, NumberOfAssets int) map[int]models.User {
 		for j := 1; j <= NumberOfAssets; j++ {
 			assetID := j
 
-			// Randomly choose an asset type
-			assetType := j % 3
-
 			var asset models.Asset
-			switch assetType {
+			switch j % 3 {
 			case 0:
-				asset = &models.Chart{
-					ID:          assetID,
-					Type:        models.ChartType,
-					Description: "Sample Chart for GWI",
-					Title:       fmt.Sprintf("GWI Chart %d", j),
-					XAxesTitle:  "X-Axis",
-					YAxesTitle:  "Y-Axis",
-					DataPoints:  GetRandomPoints(1, 5),
-				}
+				asset = g.chart(assetID)
 			case 1:
-				asset = &models.Insight{
-					ID:          assetID,
-					Type:        models.InsightType,
-					Description: "Sample Insight for GWI",
-					Text:        fmt.Sprintf("GWI Insight %d", j),
-				}
+				asset = g.insight(assetID)
 			case 2:
-				asset = &models.Audience{
-					ID:                assetID,
-					Type:              models.AudienceType,
-					Description:       "Sample Audience for GWI",
-					Age:               GetRandomNumber(100),
-					AgeGroup:          GetRandomAgeGroup(),
-					Gender:            GetRandomGender(),
-					BirthCountry:      GetRandomCountry(),
-					HoursSpentOnMedia: GetRandomNumber(100),
-					NumberOfPurchases: GetRandomNumber(100),
-				}
+				asset = g.audience(assetID)
 			}
 			// Add the asset to the user's favourites
 			user.Favourites[assetID] = asset
@@ -0,0 +1,251 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/repositorytest"
+)
+
+// The PostgreSQL tests run against the server at GWI_TEST_POSTGRES_URL, or a server started from the initdb and
+// postgres binaries found in GWI_POSTGRES_BIN, the PATH or /usr/lib/postgresql/*/bin. They are skipped otherwise.
+var (
+	postgresOnce    sync.Once
+	postgresURL     string
+	postgresSkip    string
+	postgresStop    func()
+	postgresSchemas atomic.Int64
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if postgresStop != nil {
+		postgresStop()
+	}
+	os.Exit(code)
+}
+
+// testPostgresURL returns the URL of the test server, starting it on first use, or skips the test
+func testPostgresURL(t *testing.T) string {
+	postgresOnce.Do(func() {
+		if url := os.Getenv("GWI_TEST_POSTGRES_URL"); url != "" {
+			postgresURL = url
+			return
+		}
+		postgresURL, postgresStop, postgresSkip = startPostgres()
+	})
+	if postgresURL == "" {
+		t.Skip(postgresSkip)
+	}
+	return postgresURL
+}
+
+// startPostgres starts a throwaway server listening on a unix socket in a temporary directory
+func startPostgres() (string, func(), string) {
+	bin, ok := findPostgresBin()
+	if !ok {
+		return "", nil, "no PostgreSQL server: set GWI_TEST_POSTGRES_URL or install the initdb and postgres binaries"
+	}
+	if os.Geteuid() == 0 {
+		return "", nil, "PostgreSQL refuses to run as root: set GWI_TEST_POSTGRES_URL"
+	}
+
+	dir, err := os.MkdirTemp("", "gwi-postgres")
+	if err != nil {
+		return "", nil, err.Error()
+	}
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(filepath.Join(bin, "initdb"), "-D", data, "-U", "postgres", "-A", "trust", "--no-sync").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Sprintf("initdb failed: %v: %s", err, out)
+	}
+
+	port := freePort()
+	server := exec.Command(filepath.Join(bin, "postgres"), "-D", data, "-p", strconv.Itoa(port), "-k", dir,
+		"-c", "listen_addresses=", "-c", "fsync=off")
+	if err := server.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Sprintf("postgres failed to start: %v", err)
+	}
+	stop := func() {
+		server.Process.Signal(os.Interrupt)
+		server.Wait()
+		os.RemoveAll(dir)
+	}
+
+	url := fmt.Sprintf("postgres://postgres@/postgres?host=%s&port=%d&sslmode=disable", dir, port)
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(100 * time.Millisecond) {
+		if conn, err := pgx.Connect(context.Background(), url); err == nil {
+			conn.Close(context.Background())
+			return url, stop, ""
+		}
+	}
+	stop()
+	return "", nil, "postgres did not accept connections in time"
+}
+
+// findPostgresBin returns the directory holding the initdb and postgres binaries
+func findPostgresBin() (string, bool) {
+	candidates := []string{os.Getenv("GWI_POSTGRES_BIN")}
+	if path, err := exec.LookPath("initdb"); err == nil {
+		candidates = append(candidates, filepath.Dir(path))
+	}
+	installed, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	candidates = append(candidates, installed...)
+
+	for _, dir := range candidates {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "initdb")); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "postgres")); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 54329
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// newPostgresPool connects to a new migrated schema of the test server, dropped when the test ends
+func newPostgresPool(t *testing.T) *pgxpool.Pool {
+	url := testPostgresURL(t)
+	ctx := context.Background()
+	schema := fmt.Sprintf("gwi_test_%d_%d", os.Getpid(), postgresSchemas.Add(1))
+
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+	defer admin.Close(ctx)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("invalid PostgreSQL URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Close()
+		if admin, err := pgx.Connect(ctx, url); err == nil {
+			admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+			admin.Close(ctx)
+		}
+	})
+
+	if err := repository.MigratePostgres(ctx, pool); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return pool
+}
+
+func TestPostgresUserRepositoryContract(t *testing.T) {
+	testPostgresURL(t)
+	repositorytest.RunContractTests(t, func() repository.UserRepository {
+		repo := repository.NewPostgresUserRepository(newPostgresPool(t))
+		if err := repo.ReplaceUsers(context.Background(), repositorytest.Users()); err != nil {
+			t.Fatalf("failed to save users: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestMigratePostgres(t *testing.T) {
+	pool := newPostgresPool(t)
+	ctx := context.Background()
+
+	// Test migrating again applies nothing
+	assert.NoError(t, repository.MigratePostgres(ctx, pool))
+	var applied int
+	assert.NoError(t, pool.QueryRow(ctx, "SELECT count(*) FROM schema_migrations").Scan(&applied))
+	assert.Equal(t, 2, applied)
+
+	// Test favourites can only be stored with a positive asset ID
+	_, err := pool.Exec(ctx, "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING")
+	assert.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO favorites (user_id, asset_id, asset_type, payload) VALUES (1, 0, 'Insight', '{}')`)
+	assert.ErrorContains(t, err, "favorites_asset_id_positive")
+}
+
+func TestPostgresUserRepositoryColumns(t *testing.T) {
+	repo := repository.NewPostgresUserRepository(newPostgresPool(t))
+	ctx := context.Background()
+	assert.NoError(t, repo.ReplaceUsers(ctx, repositorytest.Users()))
+
+	// Test the typed columns follow the edits of the payload
+	edited := &models.Insight{ID: 2, Type: models.InsightType, Description: "Edited Insight", Text: "Edited"}
+	assert.NoError(t, repo.EditUserFavorite(ctx, 1, 2, edited))
+
+	var assetType, description, text string
+	err := repo.Pool.QueryRow(ctx, "SELECT asset_type, description, payload->>'text' FROM favorites WHERE user_id = 1 AND asset_id = 2").
+		Scan(&assetType, &description, &text)
+	assert.NoError(t, err)
+	assert.Equal(t, "Insight", assetType)
+	assert.Equal(t, "Edited Insight", description)
+	assert.Equal(t, "Edited", text)
+}
+
+func TestPostgresUserRepositorySampleUsers(t *testing.T) {
+	repo := repository.NewPostgresUserRepository(newPostgresPool(t))
+	ctx := context.Background()
+
+	// Test the sample users replace the existing ones
+	assert.NoError(t, repo.ReplaceUsers(ctx, repositorytest.Users()))
+	assert.NoError(t, repo.GenerateSampleUsers(ctx, 5, 4))
+
+	ids, err := repo.GetUserIDs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	for _, id := range ids {
+		favorites, err := repo.GetUserFavorites(ctx, id)
+		assert.NoError(t, err)
+		assert.Len(t, favorites, 4)
+	}
+}
+
+func TestOpenPostgres(t *testing.T) {
+	ctx := context.Background()
+
+	// Test invalid URLs and unreachable servers fail without a server
+	_, err := repository.OpenPostgres(ctx, "postgres://%zz", repository.PostgresOptions{})
+	assert.Error(t, err)
+	_, err = repository.OpenPostgres(ctx, fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?connect_timeout=1", freePort()), repository.PostgresOptions{})
+	assert.Error(t, err)
+
+	// Test the pool options are applied
+	pool, err := repository.OpenPostgres(ctx, testPostgresURL(t), repository.PostgresOptions{MaxConns: 3, MinConns: 1})
+	assert.NoError(t, err)
+	defer pool.Close()
+	assert.Equal(t, int32(3), pool.Config().MaxConns)
+	assert.Equal(t, int32(1), pool.Config().MinConns)
+}
@@ -0,0 +1,332 @@
+// Package repositorytest holds the contract tests every UserRepository implementation must pass,
+// so the in-memory, PostgreSQL and cached repositories are verified identically.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+)
+
+// Users returns the users the repositories created by the factory of RunContractTests must hold:
+// user 1 with a chart and an insight, user 2 with an audience and user 10 without favourites.
+// Every call returns new maps, so they can be given to a repository that keeps them.
+func Users() map[int]models.User {
+	return map[int]models.User{
+		1: {ID: 1, Favourites: map[int]models.Asset{
+			1: &models.Chart{ID: 1, Type: models.ChartType, Description: "Chart", Title: "Sales", XAxesTitle: "Month", YAxesTitle: "Revenue",
+				DataPoints: []models.Point{{X: 1, Y: 2.5}, {X: 2, Y: 3.25}, {X: 3, Y: 0.1}}},
+			2: &models.Insight{ID: 2, Type: models.InsightType, Description: "Insight", Text: "40% of millennials spend more than 3 hours on social media daily"},
+		}},
+		2: {ID: 2, Favourites: map[int]models.Asset{
+			1: &models.Audience{ID: 1, Type: models.AudienceType, Description: "Audience", Age: 35, AgeGroup: models.AgeGroupAdult,
+				Gender: models.GenderFemale, BirthCountry: "GR", HoursSpentOnMedia: 3, NumberOfPurchases: 2},
+		}},
+		10: {ID: 10, Favourites: map[int]models.Asset{}},
+	}
+}
+
+// RunContractTests runs the contract tests of the UserRepository interface. Every test calls factory
+// for a new repository holding the Users, which must not share its data with the other repositories.
+func RunContractTests(t *testing.T, factory func() repository.UserRepository) {
+	t.Run("GetUserIDs", func(t *testing.T) { testGetUserIDs(t, factory()) })
+	t.Run("GetUserFavorites", func(t *testing.T) { testGetUserFavorites(t, factory()) })
+	t.Run("GetUserFavoritesReturnsCopy", func(t *testing.T) { testGetUserFavoritesReturnsCopy(t, factory()) })
+	t.Run("AddUserFavorite", func(t *testing.T) { testAddUserFavorite(t, factory()) })
+	t.Run("DeleteUserFavorite", func(t *testing.T) { testDeleteUserFavorite(t, factory()) })
+	t.Run("EditUserFavorite", func(t *testing.T) { testEditUserFavorite(t, factory()) })
+	t.Run("DeleteUser", func(t *testing.T) { testDeleteUser(t, factory()) })
+	t.Run("ConcurrentAdds", func(t *testing.T) { testConcurrentAdds(t, factory()) })
+	t.Run("ConcurrentDeletes", func(t *testing.T) { testConcurrentDeletes(t, factory()) })
+	t.Run("ConcurrentEdits", func(t *testing.T) { testConcurrentEdits(t, factory()) })
+	t.Run("ConcurrentReadsAndWrites", func(t *testing.T) { testConcurrentReadsAndWrites(t, factory()) })
+}
+
+// newInsight is the asset added by the tests
+func newInsight(id int, text string) *models.Insight {
+	return &models.Insight{ID: id, Type: models.InsightType, Description: "New Insight", Text: text}
+}
+
+func testGetUserIDs(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test the IDs are in ascending numeric order
+	ids, err := repo.GetUserIDs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 10}, ids)
+
+	// Test the IDs do not change with the favourites
+	assert.NoError(t, repo.AddUserFavorite(ctx, 10, newInsight(3, "text")))
+	assert.NoError(t, repo.DeleteUserFavorite(ctx, 2, 1))
+	ids, err = repo.GetUserIDs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 10}, ids)
+}
+
+func testGetUserFavorites(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test every asset type is returned as it was stored
+	for _, id := range []int{1, 2} {
+		favorites, err := repo.GetUserFavorites(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, Users()[id].Favourites, favorites, "favourites of user %d", id)
+	}
+
+	// Test a user without favourites has an empty map
+	favorites, err := repo.GetUserFavorites(ctx, 10)
+	assert.NoError(t, err)
+	assert.NotNil(t, favorites)
+	assert.Empty(t, favorites)
+
+	// Test unknown users
+	for _, id := range []int{3, 999, 0, -1} {
+		_, err := repo.GetUserFavorites(ctx, id)
+		assert.EqualError(t, err, "user not found", "user %d", id)
+	}
+}
+
+func testGetUserFavoritesReturnsCopy(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test changing the returned map changes nothing in the repository
+	favorites, _ := repo.GetUserFavorites(ctx, 1)
+	delete(favorites, 1)
+	favorites[99] = newInsight(99, "not stored")
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, Users()[1].Favourites, favorites)
+
+	// Test the returned map does not follow later changes
+	assert.NoError(t, repo.AddUserFavorite(ctx, 1, newInsight(3, "text")))
+	assert.NotContains(t, favorites, 3)
+}
+
+func testAddUserFavorite(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test adding every asset type to a user without favourites
+	assets := []models.Asset{
+		&models.Chart{ID: 5, Type: models.ChartType, Title: "Chart", DataPoints: []models.Point{{X: 0.5, Y: -1}}},
+		newInsight(6, "text"),
+		&models.Audience{ID: 7, Type: models.AudienceType, Age: 70, AgeGroup: models.AgeGroupSenior, Gender: models.GenderMale, BirthCountry: "US"},
+	}
+	for _, asset := range assets {
+		assert.NoError(t, repo.AddUserFavorite(ctx, 10, asset))
+	}
+	favorites, err := repo.GetUserFavorites(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]models.Asset{5: assets[0], 6: assets[1], 7: assets[2]}, favorites)
+
+	// Test adding an asset twice
+	assert.EqualError(t, repo.AddUserFavorite(ctx, 10, newInsight(6, "other text")), "asset already exists")
+	favorites, _ = repo.GetUserFavorites(ctx, 10)
+	assert.Equal(t, assets[1], favorites[6])
+
+	// Test adding to an unknown user
+	assert.EqualError(t, repo.AddUserFavorite(ctx, 999, newInsight(6, "text")), "user not found")
+	ids, _ := repo.GetUserIDs(ctx)
+	assert.Equal(t, []int{1, 2, 10}, ids)
+
+	// Test the same asset ID can be a favourite of several users, independently
+	assert.NoError(t, repo.AddUserFavorite(ctx, 2, newInsight(6, "text of user 2")))
+	favorites, _ = repo.GetUserFavorites(ctx, 2)
+	assert.Equal(t, newInsight(6, "text of user 2"), favorites[6])
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, Users()[1].Favourites, favorites)
+}
+
+func testDeleteUserFavorite(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test deleting an asset keeps the other ones
+	assert.NoError(t, repo.DeleteUserFavorite(ctx, 1, 1))
+	favorites, _ := repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, map[int]models.Asset{2: Users()[1].Favourites[2]}, favorites)
+
+	// Test deleting a missing asset, and an asset of an unknown user
+	assert.EqualError(t, repo.DeleteUserFavorite(ctx, 1, 1), "asset not found")
+	assert.EqualError(t, repo.DeleteUserFavorite(ctx, 10, 1), "asset not found")
+	assert.EqualError(t, repo.DeleteUserFavorite(ctx, 999, 1), "user not found")
+
+	// Test the assets of other users with the same ID are kept
+	favorites, _ = repo.GetUserFavorites(ctx, 2)
+	assert.Equal(t, Users()[2].Favourites, favorites)
+
+	// Test a deleted asset can be added again
+	assert.NoError(t, repo.AddUserFavorite(ctx, 1, newInsight(1, "added again")))
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, newInsight(1, "added again"), favorites[1])
+}
+
+func testDeleteUser(t *testing.T, repo repository.UserRepository) {
+	deleter, ok := repo.(repository.UserDeleter)
+	if !ok {
+		t.Skip("the repository cannot delete its users")
+	}
+	ctx := context.Background()
+
+	// Test the user is deleted with their favourites, reading them first so they would be cached
+	repo.GetUserFavorites(ctx, 1)
+	assert.NoError(t, deleter.DeleteUser(ctx, 1))
+	_, err := repo.GetUserFavorites(ctx, 1)
+	assert.EqualError(t, err, "user not found")
+	ids, _ := repo.GetUserIDs(ctx)
+	assert.Equal(t, []int{2, 10}, ids)
+	assert.EqualError(t, deleter.DeleteUser(ctx, 1), "user not found")
+
+	// Test the other users are kept
+	favorites, _ := repo.GetUserFavorites(ctx, 2)
+	assert.Equal(t, Users()[2].Favourites, favorites)
+}
+
+func testEditUserFavorite(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test editing every asset type
+	chart := &models.Chart{ID: 1, Type: models.ChartType, Description: "Edited Chart", Title: "Costs", DataPoints: []models.Point{{X: 9, Y: 9}}}
+	insight := &models.Insight{ID: 2, Type: models.InsightType, Description: "Edited Insight", Text: "Edited text"}
+	audience := &models.Audience{ID: 1, Type: models.AudienceType, Description: "Edited Audience", Age: 16, AgeGroup: models.AgeGroupTeen}
+	assert.NoError(t, repo.EditUserFavorite(ctx, 1, 1, chart))
+	assert.NoError(t, repo.EditUserFavorite(ctx, 1, 2, insight))
+	assert.NoError(t, repo.EditUserFavorite(ctx, 2, 1, audience))
+	favorites, _ := repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, map[int]models.Asset{1: chart, 2: insight}, favorites)
+	favorites, _ = repo.GetUserFavorites(ctx, 2)
+	assert.Equal(t, map[int]models.Asset{1: audience}, favorites)
+
+	// Test the errors, checked in order: user, asset, ID and type
+	mismatched := &models.Chart{ID: 3, Type: models.ChartType}
+	assert.EqualError(t, repo.EditUserFavorite(ctx, 999, 2, mismatched), "user not found")
+	assert.EqualError(t, repo.EditUserFavorite(ctx, 1, 3, mismatched), "asset not found")
+	assert.EqualError(t, repo.EditUserFavorite(ctx, 10, 1, chart), "asset not found")
+	assert.EqualError(t, repo.EditUserFavorite(ctx, 1, 2, mismatched), "edited asset ID does not match existing asset ID")
+	assert.EqualError(t, repo.EditUserFavorite(ctx, 1, 2, &models.Chart{ID: 2, Type: models.ChartType}),
+		"edited asset type does not match existing asset type")
+
+	// Test failed edits change nothing
+	favorites, _ = repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, map[int]models.Asset{1: chart, 2: insight}, favorites)
+	favorites, _ = repo.GetUserFavorites(ctx, 10)
+	assert.Empty(t, favorites)
+}
+
+func testConcurrentAdds(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test concurrent adds of different assets all succeed, and of the same asset exactly once
+	var wg sync.WaitGroup
+	results := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			assert.NoError(t, repo.AddUserFavorite(ctx, 10, newInsight(100+id, "concurrent")))
+		}(i)
+		go func() {
+			defer wg.Done()
+			results <- repo.AddUserFavorite(ctx, 10, newInsight(3, "same"))
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	assert.Equal(t, 1, countSucceeded(t, results, "asset already exists"))
+	favorites, err := repo.GetUserFavorites(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 21)
+}
+
+func testConcurrentDeletes(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test concurrent deletes of the same asset succeed exactly once
+	var wg sync.WaitGroup
+	results := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- repo.DeleteUserFavorite(ctx, 1, 1)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	assert.Equal(t, 1, countSucceeded(t, results, "asset not found"))
+	favorites, _ := repo.GetUserFavorites(ctx, 1)
+	assert.Equal(t, map[int]models.Asset{2: Users()[1].Favourites[2]}, favorites)
+}
+
+func testConcurrentEdits(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test concurrent edits all succeed and one of them is kept whole
+	var wg sync.WaitGroup
+	edits := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		text := fmt.Sprintf("edit %d", i)
+		edits[text] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			edited := &models.Insight{ID: 2, Type: models.InsightType, Description: text, Text: text}
+			assert.NoError(t, repo.EditUserFavorite(ctx, 1, 2, edited))
+		}()
+	}
+	wg.Wait()
+
+	favorites, _ := repo.GetUserFavorites(ctx, 1)
+	insight, ok := favorites[2].(*models.Insight)
+	if assert.True(t, ok, "edited insight is a %T", favorites[2]) {
+		assert.True(t, edits[insight.Text])
+		assert.Equal(t, insight.Text, insight.Description)
+	}
+	assert.Len(t, favorites, 2)
+}
+
+func testConcurrentReadsAndWrites(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+
+	// Test reads running with changes always see the favourites before or after each change
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			assert.NoError(t, repo.AddUserFavorite(ctx, 10, newInsight(100+id, "concurrent")))
+			assert.NoError(t, repo.DeleteUserFavorite(ctx, 10, 100+id))
+		}(i)
+		go func() {
+			defer wg.Done()
+			favorites, err := repo.GetUserFavorites(ctx, 10)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(favorites), 20)
+			for id, asset := range favorites {
+				assert.Equal(t, id, asset.GetID())
+			}
+		}()
+	}
+	wg.Wait()
+
+	favorites, _ := repo.GetUserFavorites(ctx, 10)
+	assert.Empty(t, favorites)
+}
+
+// countSucceeded returns how many results are nil, failing the test for errors other than expected
+func countSucceeded(t *testing.T, results <-chan error, expected string) int {
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.EqualError(t, err, expected)
+		}
+	}
+	return succeeded
+}
@@ -1,13 +1,26 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 )
 
 // UserRepository defines the methods that any type of user repository must implement
 type UserRepository interface {
-	GetUserFavorites(userID int) (map[int]models.Asset, error)
-	AddUserFavorite(userID int, asset models.Asset) error
-	DeleteUserFavorite(userID, assetID int) error
-	EditUserFavorite(userID int, assetID int, asset models.Asset) error
+	GetUserIDs(ctx context.Context) ([]int, error)
+	GetUserFavorites(ctx context.Context, userID int) (map[int]models.Asset, error)
+	AddUserFavorite(ctx context.Context, userID int, asset models.Asset) error
+	DeleteUserFavorite(ctx context.Context, userID, assetID int) error
+	EditUserFavorite(ctx context.Context, userID int, assetID int, asset models.Asset) error
+}
+
+// UserDeleter is implemented by the repositories whose users can be deleted, together with their favourites
+type UserDeleter interface {
+	DeleteUser(ctx context.Context, userID int) error
+}
+
+// UserReplacer is implemented by the repositories whose users can be replaced at once, e.g. by seeded sample users
+type UserReplacer interface {
+	ReplaceUsers(ctx context.Context, users map[int]models.User) error
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// WebhookRepository defines the methods that any type of webhook repository must implement
+type WebhookRepository interface {
+	AddSubscription(subscription models.WebhookSubscription) error
+	GetSubscription(id string) (models.WebhookSubscription, error)
+	GetSubscriptions() ([]models.WebhookSubscription, error)
+	DeleteSubscription(id string) error
+	SaveDelivery(delivery models.WebhookDelivery) error
+	GetDeliveries(subscriptionID string) ([]models.WebhookDelivery, error)
+	GetDeadLetters() ([]models.WebhookDelivery, error)
+	AnonymizeUserDeliveries(userID int) (int, error)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+// DefaultSnapshotDir is the directory the snapshots are written to by default
+const DefaultSnapshotDir = "snapshots"
+
+// MaxSampleFavorites is the largest number of favourites the sample data can be regenerated with
+const MaxSampleFavorites = 10_000_000
+
+// ConfigReloader reloads the configuration of the running server
+type ConfigReloader func(ctx context.Context) (ReloadResult, error)
+
+// ReloadResult lists the settings applied by a reload, and the changed ones that only take effect after a restart
+type ReloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restartRequired"`
+}
+
+// RepositoryStats describes the users and favourites held by the repository
+type RepositoryStats struct {
+	Users           int                      `json:"users"`
+	Favorites       int                      `json:"favorites"`
+	FavoritesByType map[models.AssetType]int `json:"favoritesByType"`
+	EstimatedBytes  int64                    `json:"estimatedBytes"` // size of the favourites encoded as JSON, a lower bound of the memory they take
+	HeapAllocBytes  uint64                   `json:"heapAllocBytes"` // memory allocated by the whole process
+	Goroutines      int                      `json:"goroutines"`
+	AuditFailures   int64                    `json:"auditFailures"` // changes missing from the audit log since the server started
+}
+
+// Snapshot describes a copy of the users written to a fixture file, which the server can be started from
+type Snapshot struct {
+	Path      string    `json:"path"`
+	Users     int       `json:"users"`
+	Favorites int       `json:"favorites"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PurgeResult counts what was removed when purging the data of a user
+type PurgeResult struct {
+	UserID             int `json:"userId"`
+	Favorites          int `json:"favorites"`
+	HistoryEntries     int `json:"historyEntries"`
+	TrashItems         int `json:"trashItems"`
+	CollectionsDeleted int `json:"collectionsDeleted"`
+	CollectionsLeft    int `json:"collectionsLeft"`
+}
+
+// AdminService implements the operations on the running instance used by its operators
+type AdminService struct {
+	UserService       *UserService
+	CollectionService *CollectionService // nil leaves the collections untouched when purging users
+	WebhookService    *WebhookService    // nil leaves the webhook deliveries untouched when erasing users
+	EventStream       *EventStream       // nil leaves the streamed events untouched when erasing users
+	ErasureRepository repository.ErasureRepository
+	SnapshotDir       string
+	Reloader          ConfigReloader // nil disables reloading the configuration
+}
+
+// NewAdminService creates a new AdminService instance writing the snapshots to DefaultSnapshotDir
+// and keeping the erasure records in memory
+func NewAdminService(userService *UserService, collectionService *CollectionService) *AdminService {
+	return &AdminService{
+		UserService:       userService,
+		CollectionService: collectionService,
+		ErasureRepository: repository.NewInMemoryErasureRepository(),
+		SnapshotDir:       DefaultSnapshotDir,
+	}
+}
+
+// Stats counts the users and their favourites. Every user is read, so it takes as long as a full scan of the repository.
+func (s *AdminService) Stats(ctx context.Context) (stats RepositoryStats, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.Stats")
+	defer func() { telemetry.End(span, err) }()
+
+	users, err := s.readUsers(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Users = len(users)
+	stats.FavoritesByType = map[models.AssetType]int{models.ChartType: 0, models.InsightType: 0, models.AudienceType: 0}
+	for _, user := range users {
+		for _, asset := range user.Favourites {
+			stats.Favorites++
+			stats.FavoritesByType[asset.GetType()]++
+			data, err := json.Marshal(asset)
+			if err != nil {
+				return stats, err
+			}
+			stats.EstimatedBytes += int64(len(data))
+		}
+	}
+
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	stats.HeapAllocBytes = memory.HeapAlloc
+	stats.Goroutines = runtime.NumGoroutine()
+	stats.AuditFailures = s.UserService.AuditFailures()
+	return stats, nil
+}
+
+// Snapshot writes the users to a new fixture file in SnapshotDir, named after the time it was taken.
+// The users are read one after the other, so changes made meanwhile may be in the snapshot for some users only.
+func (s *AdminService) Snapshot(ctx context.Context) (snapshot Snapshot, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.Snapshot")
+	defer func() { telemetry.End(span, err) }()
+
+	users, err := s.readUsers(ctx)
+	if err != nil {
+		return snapshot, err
+	}
+	if err := os.MkdirAll(s.SnapshotDir, 0o755); err != nil {
+		return snapshot, err
+	}
+
+	// Write to a temporary file renamed once complete, so a snapshot file is never partially written
+	snapshot.CreatedAt = time.Now().UTC()
+	snapshot.Path = filepath.Join(s.SnapshotDir, "snapshot-"+snapshot.CreatedAt.Format("20060102T150405.000Z")+".json")
+	file, err := os.CreateTemp(s.SnapshotDir, ".snapshot-*.json")
+	if err != nil {
+		return snapshot, err
+	}
+	defer os.Remove(file.Name())
+	if err := mock_data.WriteFixture(file, users); err != nil {
+		file.Close()
+		return snapshot, err
+	}
+	if err := file.Close(); err != nil {
+		return snapshot, err
+	}
+	if err := os.Rename(file.Name(), snapshot.Path); err != nil {
+		return snapshot, err
+	}
+
+	snapshot.Users = len(users)
+	for _, user := range users {
+		snapshot.Favorites += len(user.Favourites)
+	}
+	return snapshot, nil
+}
+
+// RegenerateSampleData replaces all users with numberOfUsers sample users with numberOfAssets assets each.
+// A zero seed generates the same users as at startup, another seed the users of the seed command with that seed.
+// The history, trash and collections of the replaced users are deleted too, since they refer to the replaced assets.
+func (s *AdminService) RegenerateSampleData(ctx context.Context, numberOfUsers, numberOfAssets int, seed int64) (err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.RegenerateSampleData")
+	defer func() { telemetry.End(span, err) }()
+
+	if numberOfUsers < 0 || numberOfAssets < 0 || int64(numberOfUsers)*int64(numberOfAssets) > MaxSampleFavorites {
+		return errors.New("invalid number of users or assets")
+	}
+	replacer, ok := s.UserService.UserRepository.(repository.UserReplacer)
+	if !ok {
+		return errors.New("repository cannot replace its users")
+	}
+
+	var users map[int]models.User
+	if seed == 0 {
+		users = mock_data.GenerateMockData(numberOfUsers, numberOfAssets)
+	} else {
+		config := mock_data.DefaultGeneratorConfig(numberOfUsers, numberOfAssets)
+		config.Seed = seed
+		if users, err = mock_data.NewGenerator(config).Generate(); err != nil {
+			return err
+		}
+	}
+
+	replaced, err := s.UserService.UserRepository.GetUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := replacer.ReplaceUsers(ctx, users); err != nil {
+		return err
+	}
+	for _, userID := range replaced {
+		if _, _, err := s.UserService.HistoryRepository.DeleteUserHistory(userID); err != nil {
+			return err
+		}
+		if s.CollectionService != nil {
+			if _, _, err := s.CollectionService.RemoveUser(ctx, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PurgeUser permanently deletes the favourites of a user with their history and trash, and removes the user
+// from the collections. The user is kept, without any favourites.
+func (s *AdminService) PurgeUser(ctx context.Context, userID int) (result PurgeResult, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.PurgeUser", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	result.UserID = userID
+	favorites, err := s.UserService.UserRepository.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	// The favourites are deleted from the repository directly, so they are neither trashed nor recorded in the history
+	for assetID := range favorites {
+		if err := s.UserService.UserRepository.DeleteUserFavorite(ctx, userID, assetID); err != nil && err.Error() != "asset not found" {
+			return result, err
+		}
+		result.Favorites++
+	}
+
+	result.HistoryEntries, result.TrashItems, err = s.UserService.HistoryRepository.DeleteUserHistory(userID)
+	if err != nil {
+		return result, err
+	}
+
+	if s.CollectionService != nil {
+		result.CollectionsDeleted, result.CollectionsLeft, err = s.CollectionService.RemoveUser(ctx, userID)
+	}
+	return result, err
+}
+
+// ReloadConfig reloads the configuration of the running server
+func (s *AdminService) ReloadConfig(ctx context.Context) (result ReloadResult, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.ReloadConfig")
+	defer func() { telemetry.End(span, err) }()
+
+	if s.Reloader == nil {
+		return result, errors.New("configuration reload not available")
+	}
+	return s.Reloader(ctx)
+}
+
+// readUsers reads every user with its favourites
+func (s *AdminService) readUsers(ctx context.Context) (map[int]models.User, error) {
+	ids, err := s.UserService.UserRepository.GetUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int]models.User, len(ids))
+	for _, id := range ids {
+		favorites, err := s.UserService.UserRepository.GetUserFavorites(ctx, id)
+		if err != nil {
+			// The user was deleted since the IDs were read
+			if err.Error() == "user not found" {
+				continue
+			}
+			return nil, fmt.Errorf("user %d: %v", id, err)
+		}
+		users[id] = models.User{ID: id, Favourites: favorites}
+	}
+	return users, nil
+}
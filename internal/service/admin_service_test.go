@@ -0,0 +1,157 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository/mock_data"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAdmin returns an AdminService over 3 users with 3 assets each, one of every type, writing snapshots to a temporary directory
+func setupAdmin(t *testing.T) *service.AdminService {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(3, 3)
+	userService := service.NewUserService(repo)
+	admin := service.NewAdminService(userService, service.NewCollectionService(repo, repository.NewInMemoryCollectionRepository()))
+	admin.SnapshotDir = filepath.Join(t.TempDir(), "snapshots")
+	return admin
+}
+
+func TestAdminStats(t *testing.T) {
+	s := setupAdmin(t)
+
+	stats, err := s.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Users)
+	assert.Equal(t, 9, stats.Favorites)
+	assert.Equal(t, map[models.AssetType]int{models.ChartType: 3, models.InsightType: 3, models.AudienceType: 3}, stats.FavoritesByType)
+	assert.Greater(t, stats.EstimatedBytes, int64(0))
+	assert.Greater(t, stats.HeapAllocBytes, uint64(0))
+	assert.Greater(t, stats.Goroutines, 0)
+}
+
+func TestAdminSnapshot(t *testing.T) {
+	s := setupAdmin(t)
+	ctx := context.Background()
+
+	snapshot, err := s.Snapshot(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, snapshot.Users)
+	assert.Equal(t, 9, snapshot.Favorites)
+	assert.Equal(t, s.SnapshotDir, filepath.Dir(snapshot.Path))
+
+	// Test the snapshot is a fixture holding the users, without temporary files left
+	file, err := os.Open(snapshot.Path)
+	assert.NoError(t, err)
+	defer file.Close()
+	users, err := mock_data.ReadFixture(file)
+	assert.NoError(t, err)
+	favorites, _ := s.UserService.GetUserFavorites(ctx, 2)
+	assert.Equal(t, favorites, users[2].Favourites)
+
+	entries, _ := os.ReadDir(s.SnapshotDir)
+	assert.Len(t, entries, 1)
+}
+
+func TestAdminRegenerateSampleData(t *testing.T) {
+	s := setupAdmin(t)
+	ctx := context.Background()
+
+	// Test the sample data replaces the users
+	assert.NoError(t, s.RegenerateSampleData(ctx, 5, 2, 0))
+	ids, _ := s.UserService.GetUserIDs(ctx)
+	assert.Len(t, ids, 5)
+
+	// Test the history, trash and collections of the replaced users are deleted with them
+	assert.NoError(t, s.UserService.EditUserFavorite(ctx, 1, 1, models.Insight{ID: 1, Type: models.InsightType, Text: "Edited"}))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 1, 2))
+	_, err := s.CollectionService.CreateCollection(ctx, 1, "Mine")
+	assert.NoError(t, err)
+	assert.NoError(t, s.RegenerateSampleData(ctx, 5, 2, 0))
+	_, err = s.UserService.RestoreUserFavoriteVersion(ctx, 1, 1, 1)
+	assert.EqualError(t, err, "version not found")
+	trash, _ := s.UserService.GetUserTrash(ctx, 1)
+	assert.Empty(t, trash)
+	collections, _ := s.CollectionService.GetUserCollections(ctx, 1)
+	assert.Empty(t, collections)
+
+	// Test a seed generates the same users as the seed command
+	assert.NoError(t, s.RegenerateSampleData(ctx, 4, 6, 42))
+	config := mock_data.DefaultGeneratorConfig(4, 6)
+	config.Seed = 42
+	expected, _ := mock_data.NewGenerator(config).Generate()
+	favorites, _ := s.UserService.GetUserFavorites(ctx, 3)
+	assert.Equal(t, expected[3].Favourites, favorites)
+
+	// Test invalid sizes and repositories that cannot replace their users
+	assert.EqualError(t, s.RegenerateSampleData(ctx, -1, 2, 0), "invalid number of users or assets")
+	assert.EqualError(t, s.RegenerateSampleData(ctx, 1_000_000, 1_000, 0), "invalid number of users or assets")
+	s.UserService.UserRepository = struct{ repository.UserRepository }{s.UserService.UserRepository}
+	assert.EqualError(t, s.RegenerateSampleData(ctx, 1, 1, 0), "repository cannot replace its users")
+}
+
+func TestAdminPurgeUser(t *testing.T) {
+	s := setupAdmin(t)
+	ctx := context.Background()
+
+	// The user has a history, a trashed favourite, an owned collection and one shared with them
+	assert.NoError(t, s.UserService.EditUserFavorite(ctx, 1, 1, models.Insight{ID: 1, Type: models.InsightType, Text: "Edited"}))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 1, 2))
+	_, err := s.CollectionService.CreateCollection(ctx, 1, "Mine")
+	assert.NoError(t, err)
+	shared, _ := s.CollectionService.CreateCollection(ctx, 2, "Shared")
+	_, err = s.CollectionService.ShareCollection(ctx, 2, shared.ID, 1, models.RoleEditor)
+	assert.NoError(t, err)
+
+	result, err := s.PurgeUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, service.PurgeResult{UserID: 1, Favorites: 2, HistoryEntries: 2, TrashItems: 1, CollectionsDeleted: 1, CollectionsLeft: 1}, result)
+
+	// Test nothing is left of the user, and the other users are untouched
+	favorites, err := s.UserService.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, favorites)
+	history, _ := s.UserService.GetUserFavoriteHistory(ctx, 1, 1)
+	assert.Empty(t, history)
+	trash, _ := s.UserService.GetUserTrash(ctx, 1)
+	assert.Empty(t, trash)
+	collections, _ := s.CollectionService.GetUserCollections(ctx, 1)
+	assert.Empty(t, collections)
+	sharedWith, _ := s.CollectionService.GetSharedCollections(ctx, 1)
+	assert.Empty(t, sharedWith)
+	favorites, _ = s.UserService.GetUserFavorites(ctx, 2)
+	assert.Len(t, favorites, 3)
+
+	// Test non-existing user
+	_, err = s.PurgeUser(ctx, 999)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestAdminReloadConfig(t *testing.T) {
+	s := setupAdmin(t)
+	ctx := context.Background()
+
+	_, err := s.ReloadConfig(ctx)
+	assert.EqualError(t, err, "configuration reload not available")
+
+	s.Reloader = func(ctx context.Context) (service.ReloadResult, error) {
+		return service.ReloadResult{Applied: []string{"rateLimit"}, RestartRequired: []string{"addr"}}, nil
+	}
+	result, err := s.ReloadConfig(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rateLimit"}, result.Applied)
+	assert.Equal(t, []string{"addr"}, result.RestartRequired)
+
+	s.Reloader = func(ctx context.Context) (service.ReloadResult, error) {
+		return service.ReloadResult{}, errors.New("invalid configuration")
+	}
+	_, err = s.ReloadConfig(ctx)
+	assert.EqualError(t, err, "invalid configuration")
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+// AudienceDimension is an Audience field that favourites can be grouped or filtered by
+type AudienceDimension string
+
+// Define constants for the supported audience dimensions
+const (
+	DimensionAge          AudienceDimension = "age"
+	DimensionAgeGroup     AudienceDimension = "ageGroup"
+	DimensionGender       AudienceDimension = "gender"
+	DimensionBirthCountry AudienceDimension = "birthCountry"
+)
+
+// AudienceDimensions lists every supported dimension
+var AudienceDimensions = []AudienceDimension{DimensionAge, DimensionAgeGroup, DimensionGender, DimensionBirthCountry}
+
+// ParseAudienceDimension converts a string into an AudienceDimension
+func ParseAudienceDimension(s string) (AudienceDimension, error) {
+	for _, d := range AudienceDimensions {
+		if string(d) == s {
+			return d, nil
+		}
+	}
+	return "", errors.New("invalid audience dimension")
+}
+
+// AudienceQuery describes how Audience favourites are grouped and filtered.
+// A favourite matches the filters when, for every filtered dimension, its value is one of the listed values.
+type AudienceQuery struct {
+	GroupBy []AudienceDimension
+	Filters map[AudienceDimension][]string
+}
+
+// AudienceSums holds the sums of the numeric Audience measures
+type AudienceSums struct {
+	Age               uint64 `json:"age"`
+	HoursSpentOnMedia uint64 `json:"hoursSpentOnMedia"`
+	NumberOfPurchases uint64 `json:"numberOfPurchases"`
+}
+
+// AudienceAverages holds the averages of the numeric Audience measures
+type AudienceAverages struct {
+	Age               float64 `json:"age"`
+	HoursSpentOnMedia float64 `json:"hoursSpentOnMedia"`
+	NumberOfPurchases float64 `json:"numberOfPurchases"`
+}
+
+// AudienceGroup holds the aggregated measures of the favourites sharing the same dimension values
+type AudienceGroup struct {
+	Key      map[AudienceDimension]string `json:"key"`
+	Count    int                          `json:"count"`
+	Sum      AudienceSums                 `json:"sum"`
+	Averages AudienceAverages             `json:"avg"`
+}
+
+// AudienceAggregation is the result of an aggregation, with groups sorted by their key values
+type AudienceAggregation struct {
+	GroupBy []AudienceDimension `json:"groupBy"`
+	Groups  []AudienceGroup     `json:"groups"`
+	Total   AudienceGroup       `json:"total"`
+}
+
+// AggregateUserAudiences aggregates the Audience favourites of a single user
+func (s *UserService) AggregateUserAudiences(ctx context.Context, userID int, query AudienceQuery) (aggregation AudienceAggregation, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.AggregateUserAudiences", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	query, err = query.normalize()
+	if err != nil {
+		return AudienceAggregation{}, err
+	}
+
+	favorites, err := s.UserRepository.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return AudienceAggregation{}, err
+	}
+
+	agg := newAudienceAggregator(query)
+	agg.addAll(favorites)
+	return agg.result(), nil
+}
+
+// AggregateAudiences aggregates the Audience favourites of all users
+func (s *UserService) AggregateAudiences(ctx context.Context, query AudienceQuery) (aggregation AudienceAggregation, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.AggregateAudiences")
+	defer func() { telemetry.End(span, err) }()
+
+	query, err = query.normalize()
+	if err != nil {
+		return AudienceAggregation{}, err
+	}
+
+	userIDs, err := s.UserRepository.GetUserIDs(ctx)
+	if err != nil {
+		return AudienceAggregation{}, err
+	}
+
+	agg := newAudienceAggregator(query)
+	for _, userID := range userIDs {
+		favorites, err := s.UserRepository.GetUserFavorites(ctx, userID)
+		if err != nil {
+			// The user may have been removed since the IDs were listed
+			continue
+		}
+		agg.addAll(favorites)
+	}
+	return agg.result(), nil
+}
+
+// normalize checks that the query only refers to supported dimensions, and returns a copy of it with the gender
+// and birth country filters normalised like the stored audiences, e.g. "Greece" to "GR"
+func (q AudienceQuery) normalize() (AudienceQuery, error) {
+	for _, d := range q.GroupBy {
+		if _, err := ParseAudienceDimension(string(d)); err != nil {
+			return q, err
+		}
+	}
+
+	filters := make(map[AudienceDimension][]string, len(q.Filters))
+	for d, values := range q.Filters {
+		if _, err := ParseAudienceDimension(string(d)); err != nil {
+			return q, err
+		}
+		normalized := make([]string, len(values))
+		for i, value := range values {
+			var err error
+			switch d {
+			case DimensionGender:
+				value, err = models.NormalizeGender(value)
+			case DimensionBirthCountry:
+				value, err = models.NormalizeCountry(value)
+			}
+			if err != nil {
+				return q, err
+			}
+			normalized[i] = value
+		}
+		filters[d] = normalized
+	}
+	q.Filters = filters
+	return q, nil
+}
+
+// audienceAggregator accumulates Audience favourites into groups
+type audienceAggregator struct {
+	query  AudienceQuery
+	groups map[string]*AudienceGroup
+	total  AudienceGroup
+}
+
+func newAudienceAggregator(query AudienceQuery) *audienceAggregator {
+	return &audienceAggregator{
+		query:  query,
+		groups: make(map[string]*AudienceGroup),
+		total:  AudienceGroup{Key: map[AudienceDimension]string{}},
+	}
+}
+
+// addAll adds every Audience favourite in the map, ignoring the other asset types
+func (a *audienceAggregator) addAll(favorites map[int]models.Asset) {
+	for _, asset := range favorites {
+		switch audience := asset.(type) {
+		case *models.Audience:
+			a.add(*audience)
+		case models.Audience:
+			a.add(audience)
+		}
+	}
+}
+
+// add adds a single Audience to its group when it matches the filters
+func (a *audienceAggregator) add(audience models.Audience) {
+	for d, values := range a.query.Filters {
+		if len(values) > 0 && !slices.Contains(values, dimensionValue(audience, d)) {
+			return
+		}
+	}
+
+	key := make(map[AudienceDimension]string, len(a.query.GroupBy))
+	parts := make([]string, len(a.query.GroupBy))
+	for i, d := range a.query.GroupBy {
+		key[d] = dimensionValue(audience, d)
+		parts[i] = key[d]
+	}
+
+	// The values are joined with a separator that cannot appear in them to build the map key
+	id := strings.Join(parts, "\x00")
+	group, ok := a.groups[id]
+	if !ok {
+		group = &AudienceGroup{Key: key}
+		a.groups[id] = group
+	}
+
+	group.accumulate(audience)
+	a.total.accumulate(audience)
+}
+
+// result returns the groups sorted by their key values, with the averages computed
+func (a *audienceAggregator) result() AudienceAggregation {
+	groups := make([]AudienceGroup, 0, len(a.groups))
+	for _, g := range a.groups {
+		g.computeAverages()
+		groups = append(groups, *g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		for _, d := range a.query.GroupBy {
+			vi, vj := groups[i].Key[d], groups[j].Key[d]
+			if vi != vj {
+				return lessDimensionValue(d, vi, vj)
+			}
+		}
+		return false
+	})
+
+	a.total.computeAverages()
+
+	groupBy := a.query.GroupBy
+	if groupBy == nil {
+		groupBy = []AudienceDimension{}
+	}
+	return AudienceAggregation{GroupBy: groupBy, Groups: groups, Total: a.total}
+}
+
+// accumulate adds the measures of an Audience to the group
+func (g *AudienceGroup) accumulate(audience models.Audience) {
+	g.Count++
+	g.Sum.Age += uint64(audience.Age)
+	g.Sum.HoursSpentOnMedia += uint64(audience.HoursSpentOnMedia)
+	g.Sum.NumberOfPurchases += uint64(audience.NumberOfPurchases)
+}
+
+// computeAverages derives the averages from the sums and the count
+func (g *AudienceGroup) computeAverages() {
+	if g.Count == 0 {
+		return
+	}
+	n := float64(g.Count)
+	g.Averages = AudienceAverages{
+		Age:               float64(g.Sum.Age) / n,
+		HoursSpentOnMedia: float64(g.Sum.HoursSpentOnMedia) / n,
+		NumberOfPurchases: float64(g.Sum.NumberOfPurchases) / n,
+	}
+}
+
+// dimensionValue returns the value of a dimension for an Audience as a string
+func dimensionValue(audience models.Audience, d AudienceDimension) string {
+	switch d {
+	case DimensionAge:
+		return strconv.FormatUint(uint64(audience.Age), 10)
+	case DimensionAgeGroup:
+		return audience.AgeGroup
+	case DimensionGender:
+		return audience.Gender
+	case DimensionBirthCountry:
+		return audience.BirthCountry
+	default:
+		return ""
+	}
+}
+
+// lessDimensionValue orders numeric dimensions numerically and the others alphabetically
+func lessDimensionValue(d AudienceDimension, a, b string) bool {
+	if d == DimensionAge {
+		na, _ := strconv.Atoi(a)
+		nb, _ := strconv.Atoi(b)
+		return na < nb
+	}
+	return a < b
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAudiences returns a UserService with 2 users owning a mix of Audience and other assets
+func setupAudiences() *service.UserService {
+	repo := repository.NewInMemoryUserRepository()
+	repo.Users = map[int]models.User{
+		1: {
+			ID: 1,
+			Favourites: map[int]models.Asset{
+				1: &models.Audience{ID: 1, Type: models.AudienceType, Age: 20, AgeGroup: "18-25", Gender: "Male", BirthCountry: "GR", HoursSpentOnMedia: 10, NumberOfPurchases: 2},
+				2: &models.Audience{ID: 2, Type: models.AudienceType, Age: 30, AgeGroup: "26-40", Gender: "Female", BirthCountry: "GR", HoursSpentOnMedia: 20, NumberOfPurchases: 4},
+				3: &models.Insight{ID: 3, Type: models.InsightType, Text: "Not an audience"},
+			},
+		},
+		2: {
+			ID: 2,
+			Favourites: map[int]models.Asset{
+				1: models.Audience{ID: 1, Type: models.AudienceType, Age: 22, AgeGroup: "18-25", Gender: "Male", BirthCountry: "US", HoursSpentOnMedia: 30, NumberOfPurchases: 6},
+			},
+		},
+	}
+	return service.NewUserService(repo)
+}
+
+func TestAggregateUserAudiences(t *testing.T) {
+	s := setupAudiences()
+	ctx := context.Background()
+
+	// Test grouping a single user's favourites
+	result, err := s.AggregateUserAudiences(ctx, 1, service.AudienceQuery{GroupBy: []service.AudienceDimension{service.DimensionGender}})
+	assert.NoError(t, err)
+	assert.Len(t, result.Groups, 2)
+	assert.Equal(t, "Female", result.Groups[0].Key[service.DimensionGender])
+	assert.Equal(t, "Male", result.Groups[1].Key[service.DimensionGender])
+	assert.Equal(t, 2, result.Total.Count)
+	assert.Equal(t, uint64(30), result.Total.Sum.HoursSpentOnMedia)
+	assert.Equal(t, 25.0, result.Total.Averages.Age)
+
+	// Test non-existing user
+	_, err = s.AggregateUserAudiences(ctx, 999, service.AudienceQuery{})
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestAggregateAudiences(t *testing.T) {
+	s := setupAudiences()
+	ctx := context.Background()
+
+	// Test multi-dimensional grouping across all users
+	result, err := s.AggregateAudiences(ctx, service.AudienceQuery{
+		GroupBy: []service.AudienceDimension{service.DimensionAgeGroup, service.DimensionBirthCountry},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Total.Count)
+	assert.Len(t, result.Groups, 3)
+	assert.Equal(t, map[service.AudienceDimension]string{service.DimensionAgeGroup: "18-25", service.DimensionBirthCountry: "GR"}, result.Groups[0].Key)
+	assert.Equal(t, map[service.AudienceDimension]string{service.DimensionAgeGroup: "18-25", service.DimensionBirthCountry: "US"}, result.Groups[1].Key)
+	assert.Equal(t, map[service.AudienceDimension]string{service.DimensionAgeGroup: "26-40", service.DimensionBirthCountry: "GR"}, result.Groups[2].Key)
+
+	// Test filtering on a dimension
+	result, err = s.AggregateAudiences(ctx, service.AudienceQuery{
+		GroupBy: []service.AudienceDimension{service.DimensionAge},
+		Filters: map[service.AudienceDimension][]string{service.DimensionGender: {"Male"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Groups, 2)
+	assert.Equal(t, "20", result.Groups[0].Key[service.DimensionAge])
+	assert.Equal(t, "22", result.Groups[1].Key[service.DimensionAge])
+	assert.Equal(t, uint64(8), result.Total.Sum.NumberOfPurchases)
+	assert.Equal(t, 20.0, result.Total.Averages.HoursSpentOnMedia)
+
+	// Test the filters are normalised like the stored audiences
+	result, err = s.AggregateAudiences(ctx, service.AudienceQuery{
+		Filters: map[service.AudienceDimension][]string{service.DimensionGender: {"m"}, service.DimensionBirthCountry: {"Greece", "USA"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total.Count)
+
+	// Test invalid dimension and filter values
+	_, err = s.AggregateAudiences(ctx, service.AudienceQuery{GroupBy: []service.AudienceDimension{"height"}})
+	assert.EqualError(t, err, "invalid audience dimension")
+	_, err = s.AggregateAudiences(ctx, service.AudienceQuery{Filters: map[service.AudienceDimension][]string{service.DimensionBirthCountry: {"Atlantis"}}})
+	assert.EqualError(t, err, "invalid birth country")
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// audit appends the audit record of a history entry to the audit log, if enabled
+func (s *UserService) audit(ctx context.Context, entry models.HistoryEntry) error {
+	if s.AuditSink == nil {
+		return nil
+	}
+
+	changes, err := fieldChanges(entry.Before, entry.After)
+	if err != nil {
+		return err
+	}
+	record := models.AuditRecord{
+		Timestamp: entry.Timestamp,
+		Actor:     entry.Actor,
+		RequestID: RequestIDFromContext(ctx),
+		UserID:    entry.UserID,
+		AssetID:   entry.AssetID,
+		Operation: entry.Operation,
+		Version:   entry.Version,
+		Fields:    make([]string, 0, len(changes)),
+	}
+	for _, change := range changes {
+		record.Fields = append(record.Fields, change.Field)
+	}
+	if s.AuditKeys != nil {
+		key, _, err := s.AuditKeys.GetAuditKey(entry.UserID, true)
+		if err != nil {
+			return err
+		}
+		if record.EncryptedChanges, err = sealChanges(key, changes); err != nil {
+			return err
+		}
+	}
+	return s.AuditSink.AppendAuditRecord(record)
+}
+
+// auditFailed reports a stored change that could not be appended to the audit log, without failing the change:
+// the failure is counted, recorded on the span of the change and passed to the AuditErrorHandler
+func (s *UserService) auditFailed(ctx context.Context, err error) {
+	s.auditFailures.Add(1)
+	trace.SpanFromContext(ctx).RecordError(err)
+	if s.AuditErrorHandler != nil {
+		s.AuditErrorHandler(err)
+	}
+}
+
+// AuditFailures returns how many stored changes could not be appended to the audit log since the service was created
+func (s *UserService) AuditFailures() int64 {
+	return s.auditFailures.Load()
+}
+
+// fieldChanges returns the values of the fields of the JSON form of two versions of an asset that differ, sorted by name.
+// A nil version has no fields, so every field of the other version changed.
+func fieldChanges(before, after models.Asset) ([]models.FieldChange, error) {
+	fieldsBefore, err := assetFields(before)
+	if err != nil {
+		return nil, err
+	}
+	fieldsAfter, err := assetFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]models.FieldChange, 0, len(fieldsBefore)+len(fieldsAfter))
+	for name, value := range fieldsBefore {
+		if !bytes.Equal(value, fieldsAfter[name]) {
+			changes = append(changes, models.FieldChange{Field: name, Before: value, After: fieldsAfter[name]})
+		}
+	}
+	for name, value := range fieldsAfter {
+		if _, ok := fieldsBefore[name]; !ok {
+			changes = append(changes, models.FieldChange{Field: name, After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// sealChanges encrypts the JSON form of the changes with AES-GCM, prefixed with the random nonce
+func sealChanges(key []byte, changes []models.FieldChange) ([]byte, error) {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// openChanges decrypts changes encrypted by sealChanges, failing if they were encrypted with another key
+func openChanges(key, sealed []byte) ([]models.FieldChange, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted changes")
+	}
+	data, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	var changes []models.FieldChange
+	err = json.Unmarshal(data, &changes)
+	return changes, err
+}
+
+// newAEAD returns the AES-GCM cipher of a key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// assetFields returns the top-level fields of the JSON form of an asset, with their encoded values
+func assetFields(asset models.Asset) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if asset == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// GetAuditRecords returns a page of the records of the audit log selected by the filter, oldest first
+func (s *AdminService) GetAuditRecords(ctx context.Context, filter repository.AuditFilter) (page models.AuditPage, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.GetAuditRecords")
+	defer func() { telemetry.End(span, err) }()
+
+	if s.UserService.AuditSink == nil {
+		return page, errors.New("audit log not available")
+	}
+	if page, err = s.UserService.AuditSink.GetAuditRecords(filter); err != nil {
+		return page, err
+	}
+
+	// Decrypt the changes of the users whose key exists, the changes of the erased users staying unreadable
+	for i := range page.Records {
+		record := &page.Records[i]
+		if len(record.EncryptedChanges) > 0 && s.UserService.AuditKeys != nil {
+			key, ok, err := s.UserService.AuditKeys.GetAuditKey(record.UserID, false)
+			if err != nil {
+				return page, err
+			}
+			if ok {
+				// Changes encrypted with the key of an erased user fail to open with the key created afterwards
+				record.Changes, _ = openChanges(key, record.EncryptedChanges)
+			}
+		}
+		record.EncryptedChanges = nil
+	}
+	return page, nil
+}
@@ -0,0 +1,139 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingAuditSink is an AuditSink whose log cannot be written
+type failingAuditSink struct {
+	repository.AuditSink
+}
+
+func (failingAuditSink) AppendAuditRecord(models.AuditRecord) error {
+	return errors.New("disk full")
+}
+
+func TestAuditLog(t *testing.T) {
+	s := setup()
+	s.AuditSink = repository.NewInMemoryAuditSink(0)
+	s.AuditKeys = repository.NewInMemoryAuditKeyStore()
+	ctx := service.WithRequestID(service.WithActor(context.Background(), "user:alice"), "req-1")
+
+	assert.NoError(t, s.AddUserFavorite(ctx, 1, models.Insight{ID: 2, Type: models.InsightType, Text: "Original"}))
+	assert.NoError(t, s.EditUserFavorite(ctx, 1, 2, models.Insight{ID: 2, Type: models.InsightType, Text: "Edited"}))
+	assert.NoError(t, s.DeleteUserFavorite(service.WithActor(context.Background(), "user:bob"), 1, 2))
+	_, err := s.RestoreUserFavoriteFromTrash(ctx, 1, 2)
+	assert.NoError(t, err)
+
+	// Test every change is recorded with its actor, request and version, in order
+	page, err := s.AuditSink.GetAuditRecords(repository.AuditFilter{})
+	assert.NoError(t, err)
+	records := page.Records
+	assert.Len(t, records, 4)
+	for i, op := range []models.Operation{models.OperationAdd, models.OperationEdit, models.OperationDelete, models.OperationRestore} {
+		assert.Equal(t, op, records[i].Operation)
+		assert.Equal(t, 1, records[i].UserID)
+		assert.Equal(t, 2, records[i].AssetID)
+		assert.Equal(t, i+1, records[i].Version)
+		assert.False(t, records[i].Timestamp.IsZero())
+	}
+	assert.Equal(t, "user:alice", records[0].Actor)
+	assert.Equal(t, "req-1", records[0].RequestID)
+	assert.Equal(t, "user:bob", records[2].Actor)
+	assert.Empty(t, records[2].RequestID)
+
+	// Test an addition sets every field, an edit changes only the edited fields and a deletion removes every field
+	assert.Equal(t, []string{"description", "id", "text", "type"}, records[0].Fields)
+	assert.Equal(t, []string{"text"}, records[1].Fields)
+	assert.Equal(t, []string{"description", "id", "text", "type"}, records[2].Fields)
+
+	// Test the values of the fields are stored encrypted
+	data, err := json.Marshal(records)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "Original")
+	assert.NotContains(t, string(data), "Edited")
+	for _, record := range records {
+		assert.Empty(t, record.Changes)
+		assert.NotEmpty(t, record.EncryptedChanges)
+	}
+
+	// Test the admin API returns the values of the fields before and after every change, decrypted
+	page, err = service.NewAdminService(s, nil).GetAuditRecords(context.Background(), repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Field: "text", Before: json.RawMessage(`"Original"`), After: json.RawMessage(`"Edited"`)}}, page.Records[1].Changes)
+	assert.Equal(t, models.FieldChange{Field: "text", After: json.RawMessage(`"Original"`)}, page.Records[0].Changes[2])
+	assert.Equal(t, models.FieldChange{Field: "text", Before: json.RawMessage(`"Edited"`)}, page.Records[2].Changes[2])
+	for _, record := range page.Records {
+		assert.Empty(t, record.EncryptedChanges)
+	}
+}
+
+func TestAuditLogWithoutKeys(t *testing.T) {
+	// Test only the names of the changed fields are recorded without a key store
+	s := setup()
+	s.AuditSink = repository.NewInMemoryAuditSink(0)
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 2, Type: models.InsightType, Text: "Original"}))
+
+	page, err := service.NewAdminService(s, nil).GetAuditRecords(context.Background(), repository.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"description", "id", "text", "type"}, page.Records[0].Fields)
+	assert.Empty(t, page.Records[0].Changes)
+	assert.Empty(t, page.Records[0].EncryptedChanges)
+}
+
+func TestAuditLogDisabled(t *testing.T) {
+	// Test the audit log is disabled by default
+	s := setup()
+	assert.Nil(t, s.AuditSink)
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 2, Type: models.InsightType}))
+
+	_, err := service.NewAdminService(s, nil).GetAuditRecords(context.Background(), repository.AuditFilter{})
+	assert.EqualError(t, err, "audit log not available")
+}
+
+func TestAuditLogFailure(t *testing.T) {
+	s := setup()
+	s.AuditSink = failingAuditSink{}
+
+	var failures []error
+	s.AuditErrorHandler = func(err error) { failures = append(failures, err) }
+	var published []models.FavoriteEvent
+	s.AddListener(func(event models.FavoriteEvent) { published = append(published, event) })
+
+	// Test a stored change that cannot be audited succeeds and is published, reporting the failure
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 2, Type: models.InsightType}))
+	_, err := s.GetUserFavorite(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, published, 1)
+	assert.Equal(t, []error{errors.New("disk full")}, failures)
+	assert.Equal(t, int64(1), s.AuditFailures())
+
+	// Test the failures are counted without a handler too
+	s.AuditErrorHandler = nil
+	assert.NoError(t, s.DeleteUserFavorite(context.Background(), 1, 2))
+	assert.Equal(t, int64(2), s.AuditFailures())
+}
+
+func TestAdminGetAuditRecords(t *testing.T) {
+	s := setupAdmin(t)
+	s.UserService.AuditSink = repository.NewInMemoryAuditSink(0)
+	alice := service.WithActor(context.Background(), "user:alice")
+	bob := service.WithActor(context.Background(), "user:bob")
+	assert.NoError(t, s.UserService.DeleteUserFavorite(alice, 1, 1))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(bob, 2, 1))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(alice, 3, 1))
+
+	page, err := s.GetAuditRecords(context.Background(), repository.AuditFilter{Actor: "user:alice"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Records, 2)
+	assert.Equal(t, 1, page.Records[0].UserID)
+	assert.Equal(t, 3, page.Records[1].UserID)
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+)
+
+// maxCollectionNameLength is the longest name a collection can have
+const maxCollectionNameLength = 100
+
+// maxCollectionUpdateAttempts is how many times a change of a collection is attempted while other changes keep being stored
+const maxCollectionUpdateAttempts = 100
+
+// CollectionService manages the collections users share their favourites with.
+// Every method is called on behalf of a user, and the permissions of that user are enforced here:
+// owners can do anything, editors can add and remove assets and viewers can only see the collection.
+type CollectionService struct {
+	UserRepository       repository.UserRepository
+	CollectionRepository repository.CollectionRepository
+}
+
+// NewCollectionService creates a new CollectionService instance
+func NewCollectionService(userRepo repository.UserRepository, collectionRepo repository.CollectionRepository) *CollectionService {
+	return &CollectionService{
+		UserRepository:       userRepo,
+		CollectionRepository: collectionRepo,
+	}
+}
+
+// CreateCollection creates an empty collection owned by a user
+func (s *CollectionService) CreateCollection(ctx context.Context, userID int, name string) (models.Collection, error) {
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return models.Collection{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxCollectionNameLength {
+		return models.Collection{}, errors.New("invalid collection name")
+	}
+
+	return s.CollectionRepository.AddCollection(models.Collection{
+		OwnerID:   userID,
+		Name:      name,
+		Members:   map[int]models.Role{},
+		Assets:    []models.AssetRef{},
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// GetUserCollections returns the collections owned by a user
+func (s *CollectionService) GetUserCollections(ctx context.Context, userID int) ([]models.Collection, error) {
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.CollectionRepository.GetCollectionsByOwner(userID)
+}
+
+// GetSharedCollections returns the collections other users shared with a user, with the role the user was given
+func (s *CollectionService) GetSharedCollections(ctx context.Context, userID int) ([]models.SharedCollection, error) {
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	collections, err := s.CollectionRepository.GetCollectionsByMember(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make([]models.SharedCollection, len(collections))
+	for i, collection := range collections {
+		shared[i] = models.SharedCollection{Collection: collection, Role: collection.Members[userID]}
+	}
+	return shared, nil
+}
+
+// GetCollection returns a collection with its assets, if the user is its owner or one of its members
+func (s *CollectionService) GetCollection(ctx context.Context, userID, collectionID int) (models.CollectionDetails, error) {
+	collection, err := s.authorize(ctx, userID, collectionID, models.RoleViewer)
+	if err != nil {
+		return models.CollectionDetails{}, err
+	}
+
+	details := models.CollectionDetails{Collection: collection, Items: []models.CollectionItem{}}
+	favorites := make(map[int]map[int]models.Asset)
+	for _, ref := range collection.Assets {
+		if _, ok := favorites[ref.UserID]; !ok {
+			// Users may have been removed since they added their assets, which only hides their assets
+			favorites[ref.UserID], _ = s.UserRepository.GetUserFavorites(ctx, ref.UserID)
+		}
+		if asset, ok := favorites[ref.UserID][ref.AssetID]; ok {
+			details.Items = append(details.Items, models.CollectionItem{UserID: ref.UserID, Asset: asset})
+		}
+	}
+	return details, nil
+}
+
+// DeleteCollection deletes a collection, which only its owner can do
+func (s *CollectionService) DeleteCollection(ctx context.Context, userID, collectionID int) error {
+	if _, err := s.authorize(ctx, userID, collectionID, ""); err != nil {
+		return err
+	}
+	return s.CollectionRepository.DeleteCollection(collectionID)
+}
+
+// ShareCollection gives a user a role in a collection, or changes the role of an existing member.
+// Only the owner can share a collection.
+func (s *CollectionService) ShareCollection(ctx context.Context, userID, collectionID, memberID int, role models.Role) (models.Collection, error) {
+	return s.updateCollection(ctx, userID, collectionID, "", func(collection *models.Collection) error {
+		if role != models.RoleViewer && role != models.RoleEditor {
+			return errors.New("invalid role")
+		}
+		if memberID == collection.OwnerID {
+			return errors.New("cannot share a collection with its owner")
+		}
+		if _, err := s.UserRepository.GetUserFavorites(ctx, memberID); err != nil {
+			return errors.New("member not found")
+		}
+
+		collection.Members[memberID] = role
+		return nil
+	})
+}
+
+// UnshareCollection removes a member from a collection.
+// The owner can remove any member, and members can remove themselves.
+func (s *CollectionService) UnshareCollection(ctx context.Context, userID, collectionID, memberID int) error {
+	required := models.Role("")
+	if userID == memberID {
+		required = models.RoleViewer
+	}
+	_, err := s.updateCollection(ctx, userID, collectionID, required, func(collection *models.Collection) error {
+		if _, ok := collection.Members[memberID]; !ok {
+			return errors.New("member not found")
+		}
+		delete(collection.Members, memberID)
+		return nil
+	})
+	return err
+}
+
+// AddCollectionAsset adds one of the user's favourites to a collection the user owns or can edit
+func (s *CollectionService) AddCollectionAsset(ctx context.Context, userID, collectionID, assetID int) (models.Collection, error) {
+	return s.updateCollection(ctx, userID, collectionID, models.RoleEditor, func(collection *models.Collection) error {
+		favorites, err := s.UserRepository.GetUserFavorites(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if _, ok := favorites[assetID]; !ok {
+			return errors.New("asset not found")
+		}
+
+		ref := models.AssetRef{UserID: userID, AssetID: assetID}
+		for _, existing := range collection.Assets {
+			if existing == ref {
+				return errors.New("asset already in collection")
+			}
+		}
+
+		collection.Assets = append(collection.Assets, ref)
+		return nil
+	})
+}
+
+// RemoveCollectionAsset removes an asset, added from the favourites of ownerID, from a collection the user owns or can edit
+func (s *CollectionService) RemoveCollectionAsset(ctx context.Context, userID, collectionID, ownerID, assetID int) error {
+	_, err := s.updateCollection(ctx, userID, collectionID, models.RoleEditor, func(collection *models.Collection) error {
+		ref := models.AssetRef{UserID: ownerID, AssetID: assetID}
+		for i, existing := range collection.Assets {
+			if existing == ref {
+				collection.Assets = append(collection.Assets[:i], collection.Assets[i+1:]...)
+				return nil
+			}
+		}
+		return errors.New("asset not in collection")
+	})
+	return err
+}
+
+// RemoveUser deletes the collections a user owns and takes the user, and the assets the user added,
+// out of the collections shared with the user. It returns how many collections were deleted and left.
+func (s *CollectionService) RemoveUser(ctx context.Context, userID int) (deleted, left int, err error) {
+	owned, err := s.CollectionRepository.GetCollectionsByOwner(userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, collection := range owned {
+		if err := s.CollectionRepository.DeleteCollection(collection.ID); err != nil {
+			return deleted, left, err
+		}
+		deleted++
+	}
+
+	shared, err := s.CollectionRepository.GetCollectionsByMember(userID)
+	if err != nil {
+		return deleted, left, err
+	}
+	for _, collection := range shared {
+		read := func() (models.Collection, error) { return s.CollectionRepository.GetCollection(collection.ID) }
+		_, err := s.update(read, func(collection *models.Collection) error {
+			delete(collection.Members, userID)
+			assets := make([]models.AssetRef, 0, len(collection.Assets))
+			for _, ref := range collection.Assets {
+				if ref.UserID != userID {
+					assets = append(assets, ref)
+				}
+			}
+			collection.Assets = assets
+			return nil
+		})
+		switch {
+		case err == nil:
+			left++
+		case err.Error() != "collection not found": // deleted by its owner meanwhile
+			return deleted, left, err
+		}
+	}
+	return deleted, left, nil
+}
+
+// updateCollection applies a change to a collection the user can access with the required role, and stores it
+func (s *CollectionService) updateCollection(ctx context.Context, userID, collectionID int, required models.Role, change func(*models.Collection) error) (models.Collection, error) {
+	return s.update(func() (models.Collection, error) { return s.authorize(ctx, userID, collectionID, required) }, change)
+}
+
+// update reads a collection, applies a change to it and stores it. Whenever another change was stored in between,
+// the collection is read again, with the permissions checked by read, and the change applied again.
+func (s *CollectionService) update(read func() (models.Collection, error), change func(*models.Collection) error) (models.Collection, error) {
+	for attempt := 1; ; attempt++ {
+		collection, err := read()
+		if err != nil {
+			return models.Collection{}, err
+		}
+		if err := change(&collection); err != nil {
+			return models.Collection{}, err
+		}
+
+		updated, err := s.CollectionRepository.UpdateCollection(collection)
+		if err == nil || err.Error() != "collection modified concurrently" || attempt >= maxCollectionUpdateAttempts {
+			return updated, err
+		}
+	}
+}
+
+// authorize returns a collection if the user owns it or is a member with at least the required role.
+// An empty required role only allows the owner. Users who cannot see a collection are told it does not exist.
+func (s *CollectionService) authorize(ctx context.Context, userID, collectionID int, required models.Role) (models.Collection, error) {
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return models.Collection{}, err
+	}
+
+	collection, err := s.CollectionRepository.GetCollection(collectionID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	if collection.OwnerID == userID {
+		return collection, nil
+	}
+
+	role, ok := collection.Members[userID]
+	if !ok {
+		return models.Collection{}, errors.New("collection not found")
+	}
+	if required == "" || (required == models.RoleEditor && role != models.RoleEditor) {
+		return models.Collection{}, errors.New("permission denied")
+	}
+	return collection, nil
+}
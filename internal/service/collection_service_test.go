@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupCollections returns a CollectionService over 3 users owning one Insight each
+func setupCollections() *service.CollectionService {
+	repo := repository.NewInMemoryUserRepository()
+	repo.GenerateSampleUsers(3, 0)
+	for id := 1; id <= 3; id++ {
+		repo.AddUserFavorite(context.Background(), id, models.Insight{ID: 1, Type: models.InsightType, Text: "Insight"})
+	}
+	return service.NewCollectionService(repo, repository.NewInMemoryCollectionRepository())
+}
+
+func TestCreateCollection(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+
+	collection, err := s.CreateCollection(ctx, 1, "  Team picks ")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, collection.OwnerID)
+	assert.Equal(t, "Team picks", collection.Name)
+
+	collections, _ := s.GetUserCollections(ctx, 1)
+	assert.Len(t, collections, 1)
+
+	// Test invalid collections
+	_, err = s.CreateCollection(ctx, 1, " ")
+	assert.EqualError(t, err, "invalid collection name")
+	_, err = s.CreateCollection(ctx, 999, "Team picks")
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestShareCollection(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+	collection, _ := s.CreateCollection(ctx, 1, "Team picks")
+
+	// Test sharing lists the collection for the member with its role
+	_, err := s.ShareCollection(ctx, 1, collection.ID, 2, models.RoleViewer)
+	assert.NoError(t, err)
+	shared, err := s.GetSharedCollections(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, shared, 1)
+	assert.Equal(t, models.RoleViewer, shared[0].Role)
+
+	// Test only the owner can share
+	_, err = s.ShareCollection(ctx, 2, collection.ID, 3, models.RoleViewer)
+	assert.EqualError(t, err, "permission denied")
+	_, err = s.ShareCollection(ctx, 3, collection.ID, 3, models.RoleViewer)
+	assert.EqualError(t, err, "collection not found")
+
+	// Test invalid shares
+	_, err = s.ShareCollection(ctx, 1, collection.ID, 2, "admin")
+	assert.EqualError(t, err, "invalid role")
+	_, err = s.ShareCollection(ctx, 1, collection.ID, 1, models.RoleEditor)
+	assert.EqualError(t, err, "cannot share a collection with its owner")
+	_, err = s.ShareCollection(ctx, 1, collection.ID, 999, models.RoleEditor)
+	assert.EqualError(t, err, "member not found")
+
+	// Test members can leave, but cannot remove others
+	s.ShareCollection(ctx, 1, collection.ID, 3, models.RoleEditor)
+	assert.EqualError(t, s.UnshareCollection(ctx, 2, collection.ID, 3), "permission denied")
+	assert.NoError(t, s.UnshareCollection(ctx, 2, collection.ID, 2))
+	shared, _ = s.GetSharedCollections(ctx, 2)
+	assert.Empty(t, shared)
+	assert.NoError(t, s.UnshareCollection(ctx, 1, collection.ID, 3))
+	assert.EqualError(t, s.UnshareCollection(ctx, 1, collection.ID, 3), "member not found")
+}
+
+func TestCollectionAssets(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+	collection, _ := s.CreateCollection(ctx, 1, "Team picks")
+	s.ShareCollection(ctx, 1, collection.ID, 2, models.RoleEditor)
+	s.ShareCollection(ctx, 1, collection.ID, 3, models.RoleViewer)
+
+	// Test the owner and editors can add their own favourites
+	_, err := s.AddCollectionAsset(ctx, 1, collection.ID, 1)
+	assert.NoError(t, err)
+	_, err = s.AddCollectionAsset(ctx, 2, collection.ID, 1)
+	assert.NoError(t, err)
+
+	// Test viewers see the assets of every member
+	details, err := s.GetCollection(ctx, 3, collection.ID)
+	assert.NoError(t, err)
+	assert.Len(t, details.Items, 2)
+	assert.Equal(t, 1, details.Items[0].UserID)
+	assert.Equal(t, 2, details.Items[1].UserID)
+
+	// Test viewers cannot change the assets
+	_, err = s.AddCollectionAsset(ctx, 3, collection.ID, 1)
+	assert.EqualError(t, err, "permission denied")
+	assert.EqualError(t, s.RemoveCollectionAsset(ctx, 3, collection.ID, 1, 1), "permission denied")
+
+	// Test invalid assets
+	_, err = s.AddCollectionAsset(ctx, 1, collection.ID, 1)
+	assert.EqualError(t, err, "asset already in collection")
+	_, err = s.AddCollectionAsset(ctx, 1, collection.ID, 999)
+	assert.EqualError(t, err, "asset not found")
+
+	// Test editors can remove assets added by others
+	assert.NoError(t, s.RemoveCollectionAsset(ctx, 2, collection.ID, 1, 1))
+	assert.EqualError(t, s.RemoveCollectionAsset(ctx, 2, collection.ID, 1, 1), "asset not in collection")
+
+	// Test only the owner can delete the collection
+	assert.EqualError(t, s.DeleteCollection(ctx, 2, collection.ID), "permission denied")
+	assert.NoError(t, s.DeleteCollection(ctx, 1, collection.ID))
+	_, err = s.GetCollection(ctx, 1, collection.ID)
+	assert.EqualError(t, err, "collection not found")
+}
+
+func TestCollectionHidesDeletedFavorites(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+	collection, _ := s.CreateCollection(ctx, 1, "Team picks")
+	s.AddCollectionAsset(ctx, 1, collection.ID, 1)
+
+	// Test assets deleted from the favourites are left out of the collection
+	s.UserRepository.DeleteUserFavorite(ctx, 1, 1)
+	details, err := s.GetCollection(ctx, 1, collection.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, details.Items)
+	assert.Len(t, details.Assets, 1)
+}
+
+func TestCollectionRemoveUser(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+	owned, _ := s.CreateCollection(ctx, 2, "Owned")
+	shared, _ := s.CreateCollection(ctx, 1, "Shared")
+	s.ShareCollection(ctx, 1, shared.ID, 2, models.RoleEditor)
+	s.ShareCollection(ctx, 1, shared.ID, 3, models.RoleViewer)
+	s.AddCollectionAsset(ctx, 1, shared.ID, 1)
+	s.AddCollectionAsset(ctx, 2, shared.ID, 1)
+
+	// Test the user's collections are deleted and the user is taken out of the others with its assets
+	deleted, left, err := s.RemoveUser(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 1, left)
+
+	_, err = s.GetCollection(ctx, 2, owned.ID)
+	assert.EqualError(t, err, "collection not found")
+	details, err := s.GetCollection(ctx, 1, shared.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]models.Role{3: models.RoleViewer}, details.Members)
+	assert.Equal(t, []models.AssetRef{{UserID: 1, AssetID: 1}}, details.Assets)
+}
+
+func TestCollectionConcurrentChanges(t *testing.T) {
+	s := setupCollections()
+	ctx := context.Background()
+	for id := 2; id <= 20; id++ {
+		s.UserRepository.AddUserFavorite(ctx, 1, models.Insight{ID: id, Type: models.InsightType})
+	}
+	collection, _ := s.CreateCollection(ctx, 1, "Team picks")
+
+	// Test concurrent changes of the same collection are all kept
+	var wg sync.WaitGroup
+	errs := make(chan error, 22)
+	for id := 1; id <= 20; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			_, err := s.AddCollectionAsset(ctx, 1, collection.ID, id)
+			errs <- err
+		}(id)
+	}
+	for _, member := range []int{2, 3} {
+		wg.Add(1)
+		go func(member int) {
+			defer wg.Done()
+			_, err := s.ShareCollection(ctx, 1, collection.ID, member, models.RoleViewer)
+			errs <- err
+		}(member)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	details, err := s.GetCollection(ctx, 1, collection.ID)
+	assert.NoError(t, err)
+	assert.Len(t, details.Assets, 20)
+	assert.Equal(t, map[int]models.Role{2: models.RoleViewer, 3: models.RoleViewer}, details.Members)
+	assert.Equal(t, 22, details.Version)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// DefaultEventLogSize is how many events an EventStream keeps for resuming subscribers by default
+const DefaultEventLogSize = 1024
+
+// subscriberBuffer is how many events can wait for a subscriber before it is dropped
+const subscriberBuffer = 64
+
+// StreamedEvent is a favourite event with the position it was given in the stream
+type StreamedEvent struct {
+	ID    uint64
+	Event models.FavoriteEvent
+}
+
+// EventStream fans the favourite events out to live subscribers, keeping the latest events in a bounded log
+// so subscribers that reconnect can resume from the last event they received.
+type EventStream struct {
+	log         []StreamedEvent // log is a ring buffer of the latest events, ordered from start
+	start       int
+	lastID      uint64
+	subscribers map[*eventSubscriber]struct{}
+	mu          sync.Mutex
+}
+
+// eventSubscriber receives the events of a single user
+type eventSubscriber struct {
+	userID int
+	events chan StreamedEvent
+}
+
+// NewEventStream creates a new EventStream keeping the latest size events
+func NewEventStream(size int) *EventStream {
+	if size < 1 {
+		size = 1
+	}
+	return &EventStream{
+		log:         make([]StreamedEvent, 0, size),
+		subscribers: make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// Publish adds an event to the log and sends it to the subscribers of its user.
+// Subscribers that fall too far behind are dropped, closing their channel, and are expected to resume.
+// It can be registered as an EventListener of the UserService.
+func (s *EventStream) Publish(event models.FavoriteEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	streamed := StreamedEvent{ID: s.lastID, Event: event}
+	if len(s.log) < cap(s.log) {
+		s.log = append(s.log, streamed)
+	} else {
+		s.log[s.start] = streamed
+		s.start = (s.start + 1) % len(s.log)
+	}
+
+	for subscriber := range s.subscribers {
+		if subscriber.userID != event.UserID {
+			continue
+		}
+		select {
+		case subscriber.events <- streamed:
+		default:
+			delete(s.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+}
+
+// Subscribe returns the logged events of a user published after lastEventID, and a channel receiving
+// the user's following events. A zero lastEventID only subscribes to new events.
+// The returned function must be called to unsubscribe.
+func (s *EventStream) Subscribe(userID int, lastEventID uint64) ([]StreamedEvent, <-chan StreamedEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missed []StreamedEvent
+	if lastEventID > 0 {
+		for i := range s.log {
+			streamed := s.log[(s.start+i)%len(s.log)]
+			if streamed.ID > lastEventID && streamed.Event.UserID == userID {
+				missed = append(missed, streamed)
+			}
+		}
+	}
+
+	subscriber := &eventSubscriber{userID: userID, events: make(chan StreamedEvent, subscriberBuffer)}
+	s.subscribers[subscriber] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.subscribers[subscriber]; ok {
+			delete(s.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+	return missed, subscriber.events, unsubscribe
+}
+
+// ForgetUser removes the logged events of a user and disconnects the user's subscribers,
+// and returns how many events were removed. The other events keep their IDs, so their subscribers can still resume.
+func (s *EventStream) ForgetUser(userID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]StreamedEvent, 0, cap(s.log))
+	for i := range s.log {
+		streamed := s.log[(s.start+i)%len(s.log)]
+		if streamed.Event.UserID != userID {
+			kept = append(kept, streamed)
+		}
+	}
+	removed := len(s.log) - len(kept)
+	s.log, s.start = kept, 0
+
+	for subscriber := range s.subscribers {
+		if subscriber.userID == userID {
+			delete(s.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStream(t *testing.T) {
+	s := setup()
+	stream := service.NewEventStream(service.DefaultEventLogSize)
+	s.AddListener(stream.Publish)
+	ctx := context.Background()
+
+	_, events, unsubscribe := stream.Subscribe(1, 0)
+	defer unsubscribe()
+
+	// Test subscribers only receive the events of their user
+	stream.Publish(models.FavoriteEvent{Type: models.EventFavoriteDeleted, UserID: 2, AssetID: 1})
+	assert.NoError(t, s.AddUserFavorite(ctx, 1, models.Insight{ID: 10, Type: models.InsightType}))
+
+	event := <-events
+	assert.Equal(t, uint64(2), event.ID)
+	assert.Equal(t, models.EventFavoriteAdded, event.Event.Type)
+	assert.Equal(t, 10, event.Event.AssetID)
+
+	assert.NoError(t, s.EditUserFavorite(ctx, 1, 10, models.Insight{ID: 10, Type: models.InsightType, Text: "Edited"}))
+	event = <-events
+	assert.Equal(t, models.EventFavoriteEdited, event.Event.Type)
+
+	// Test resuming returns the user's events after the last received one
+	missed, _, unsubscribeResumed := stream.Subscribe(1, 1)
+	defer unsubscribeResumed()
+	assert.Len(t, missed, 2)
+	assert.Equal(t, uint64(2), missed[0].ID)
+	assert.Equal(t, uint64(3), missed[1].ID)
+
+	// Test unsubscribing closes the channel
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestEventStreamBounds(t *testing.T) {
+	stream := service.NewEventStream(3)
+
+	_, slow, unsubscribe := stream.Subscribe(1, 0)
+	defer unsubscribe()
+
+	for i := 1; i <= 100; i++ {
+		stream.Publish(models.FavoriteEvent{Type: models.EventFavoriteAdded, UserID: 1, AssetID: i})
+	}
+
+	// Test the log only keeps the latest events
+	missed, _, unsubscribeResumed := stream.Subscribe(1, 1)
+	defer unsubscribeResumed()
+	assert.Len(t, missed, 3)
+	assert.Equal(t, uint64(98), missed[0].ID)
+	assert.Equal(t, uint64(100), missed[2].ID)
+
+	// Test subscribers falling behind are dropped after their buffer fills up
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Less(t, received, 100)
+}
+
+func TestEventStreamForgetUser(t *testing.T) {
+	stream := service.NewEventStream(4)
+	_, forgotten, unsubscribe := stream.Subscribe(1, 0)
+	defer unsubscribe()
+
+	for i := 1; i <= 6; i++ {
+		stream.Publish(models.FavoriteEvent{Type: models.EventFavoriteAdded, UserID: i%2 + 1, AssetID: i})
+	}
+
+	// Test the user's events are removed and their subscribers disconnected
+	assert.Equal(t, 2, stream.ForgetUser(1))
+	missed, _, unsubscribeForgotten := stream.Subscribe(1, 1)
+	defer unsubscribeForgotten()
+	assert.Empty(t, missed)
+	received := 0
+	for range forgotten {
+		received++
+	}
+	assert.Equal(t, 3, received)
+
+	// Test the other users' events are kept with their IDs, and the log keeps its size
+	missed, _, unsubscribeOther := stream.Subscribe(2, 1)
+	defer unsubscribeOther()
+	assert.Len(t, missed, 2)
+	assert.Equal(t, uint64(3), missed[0].ID)
+	for i := 7; i <= 12; i++ {
+		stream.Publish(models.FavoriteEvent{Type: models.EventFavoriteAdded, UserID: 2, AssetID: i})
+	}
+	missed, _, unsubscribeLatest := stream.Subscribe(2, 1)
+	defer unsubscribeLatest()
+	assert.Len(t, missed, 4)
+	assert.Equal(t, uint64(12), missed[3].ID)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// EventListener is called with every change made to the favourites.
+// Listeners are called synchronously while the change is recorded, so they must not block.
+type EventListener func(event models.FavoriteEvent)
+
+// AddListener registers a listener notified of every change made to the favourites
+func (s *UserService) AddListener(listener EventListener) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// publish notifies every listener of the change recorded in a history entry
+func (s *UserService) publish(entry models.HistoryEntry) {
+	event := models.FavoriteEvent{
+		UserID:    entry.UserID,
+		AssetID:   entry.AssetID,
+		Version:   entry.Version,
+		Actor:     entry.Actor,
+		Timestamp: entry.Timestamp,
+		Asset:     entry.After,
+	}
+
+	// Restores are published as the addition or edit they result in
+	switch {
+	case entry.After == nil:
+		event.Type = models.EventFavoriteDeleted
+		event.Asset = entry.Before
+	case entry.Before == nil:
+		event.Type = models.EventFavoriteAdded
+	default:
+		event.Type = models.EventFavoriteEdited
+	}
+
+	s.listenersMu.RLock()
+	defer s.listenersMu.RUnlock()
+
+	for _, listener := range s.listeners {
+		listener(event)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetUserFavoriteHistory returns every recorded version of a user's favourite, oldest first
+func (s *UserService) GetUserFavoriteHistory(ctx context.Context, userID, assetID int) (history []models.HistoryEntry, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.GetUserFavoriteHistory", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
+	// Make sure the user exists, since the history of unknown users is always empty
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.HistoryRepository.GetHistory(userID, assetID)
+}
+
+// RestoreUserFavoriteVersion brings a favourite back to the state recorded in a version of its history.
+// The favourite is edited when it still exists, or added again (and taken out of the trash) when it was deleted.
+func (s *UserService) RestoreUserFavoriteVersion(ctx context.Context, userID, assetID, version int) (restored models.Asset, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.RestoreUserFavoriteVersion", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID), attribute.Int("version", version))
+	defer func() { telemetry.End(span, err) }()
+
+	history, err := s.GetUserFavoriteHistory(ctx, userID, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if version < 1 || version > len(history) {
+		return nil, errors.New("version not found")
+	}
+	target := history[version-1].After
+	if target == nil {
+		return nil, errors.New("cannot restore a deleted version")
+	}
+
+	current, err := s.GetUserFavorite(ctx, userID, assetID)
+	switch {
+	case err == nil:
+		err = s.UserRepository.EditUserFavorite(ctx, userID, assetID, target)
+	case err.Error() == "asset not found":
+		current = nil
+		err = s.UserRepository.AddUserFavorite(ctx, userID, target)
+		if err == nil {
+			// The asset is back in the favourites, so its trashed copy is no longer needed
+			s.HistoryRepository.RemoveFromTrash(userID, assetID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.record(ctx, models.OperationRestore, userID, assetID, current, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// GetUserTrash returns the deleted favourites of a user that can still be restored
+func (s *UserService) GetUserTrash(ctx context.Context, userID int) (trash []models.TrashItem, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.GetUserTrash", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.HistoryRepository.GetTrash(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expired items are left to the periodic purge, but never returned even if it has not run yet
+	now := time.Now()
+	trash = make([]models.TrashItem, 0, len(items))
+	for _, item := range items {
+		if !item.Expired(now) {
+			trash = append(trash, item)
+		}
+	}
+	return trash, nil
+}
+
+// RestoreUserFavoriteFromTrash adds a deleted favourite back to the user's favourites
+func (s *UserService) RestoreUserFavoriteFromTrash(ctx context.Context, userID, assetID int) (restored models.Asset, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.RestoreUserFavoriteFromTrash", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return nil, err
+	}
+	item, err := s.HistoryRepository.RemoveFromTrash(userID, assetID)
+	if err != nil {
+		return nil, err
+	}
+	// An expired item is as good as purged, so it stays removed from the trash
+	if item.Expired(time.Now()) {
+		return nil, errors.New("asset not in trash")
+	}
+
+	if err := s.UserRepository.AddUserFavorite(ctx, userID, item.Asset); err != nil {
+		// Keep the item in the trash, e.g. when a new asset with the same ID was added in the meantime
+		s.HistoryRepository.AddToTrash(item)
+		return nil, err
+	}
+
+	if _, err := s.record(ctx, models.OperationRestore, userID, assetID, nil, item.Asset); err != nil {
+		return nil, err
+	}
+	return item.Asset, nil
+}
+
+// DeleteUserFavoriteFromTrash permanently removes a deleted favourite from the user's trash
+func (s *UserService) DeleteUserFavoriteFromTrash(ctx context.Context, userID, assetID int) (err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.DeleteUserFavoriteFromTrash", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := s.UserRepository.GetUserFavorites(ctx, userID); err != nil {
+		return err
+	}
+	_, err = s.HistoryRepository.RemoveFromTrash(userID, assetID)
+	return err
+}
+
+// PurgeExpiredTrash permanently removes the trashed favourites whose retention expired at or before now
+func (s *UserService) PurgeExpiredTrash(now time.Time) (int, error) {
+	return s.HistoryRepository.PurgeTrash(now)
+}
+
+// record appends a mutation of a favourite to its history and the audit log, and publishes it to the listeners
+func (s *UserService) record(ctx context.Context, op models.Operation, userID, assetID int, before, after models.Asset) (models.HistoryEntry, error) {
+	entry, err := s.HistoryRepository.AddHistoryEntry(models.HistoryEntry{
+		UserID:    userID,
+		AssetID:   assetID,
+		Operation: op,
+		Actor:     ActorFromContext(ctx),
+		Timestamp: time.Now().UTC(),
+		Before:    before,
+		After:     after,
+	})
+	if err != nil {
+		return entry, err
+	}
+	if err := s.audit(ctx, entry); err != nil {
+		s.auditFailed(ctx, err)
+	}
+
+	s.publish(entry)
+	return entry, nil
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserFavoriteHistory(t *testing.T) {
+	s := setup()
+	ctx := service.WithActor(context.Background(), "tester")
+
+	added := models.Insight{ID: 2, Type: models.InsightType, Text: "Original"}
+	edited := models.Insight{ID: 2, Type: models.InsightType, Text: "Edited"}
+
+	assert.NoError(t, s.AddUserFavorite(ctx, 1, added))
+	assert.NoError(t, s.EditUserFavorite(ctx, 1, 2, edited))
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 2))
+
+	// Test every mutation is recorded with its actor and before/after states
+	history, err := s.GetUserFavoriteHistory(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	assert.Equal(t, 1, history[0].Version)
+	assert.Equal(t, models.OperationAdd, history[0].Operation)
+	assert.Equal(t, "tester", history[0].Actor)
+	assert.Nil(t, history[0].Before)
+	assert.Equal(t, added, history[0].After)
+
+	assert.Equal(t, models.OperationEdit, history[1].Operation)
+	assert.Equal(t, added, history[1].Before)
+	assert.Equal(t, edited, history[1].After)
+
+	assert.Equal(t, models.OperationDelete, history[2].Operation)
+	assert.Equal(t, edited, history[2].Before)
+	assert.Nil(t, history[2].After)
+
+	// Test changes without an actor are recorded as anonymous
+	assert.NoError(t, s.EditUserFavorite(context.Background(), 1, 1, models.Insight{ID: 1, Type: models.InsightType}))
+	history, _ = s.GetUserFavoriteHistory(ctx, 1, 1)
+	assert.Equal(t, service.AnonymousActor, history[0].Actor)
+
+	// Test non-existing user
+	_, err = s.GetUserFavoriteHistory(ctx, 999, 1)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestRestoreUserFavoriteVersion(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+
+	original := models.Insight{ID: 2, Type: models.InsightType, Text: "Original"}
+	assert.NoError(t, s.AddUserFavorite(ctx, 1, original))
+	assert.NoError(t, s.EditUserFavorite(ctx, 1, 2, models.Insight{ID: 2, Type: models.InsightType, Text: "Edited"}))
+
+	// Test restoring an existing favourite edits it back
+	asset, err := s.RestoreUserFavoriteVersion(ctx, 1, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, original, asset)
+	current, _ := s.GetUserFavorite(ctx, 1, 2)
+	assert.Equal(t, original, current)
+
+	// Test restoring a deleted favourite adds it back and takes it out of the trash
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 2))
+	_, err = s.RestoreUserFavoriteVersion(ctx, 1, 2, 2)
+	assert.NoError(t, err)
+	current, _ = s.GetUserFavorite(ctx, 1, 2)
+	assert.Equal(t, "Edited", current.(models.Insight).Text)
+	trash, _ := s.GetUserTrash(ctx, 1)
+	assert.Empty(t, trash)
+
+	// Test the restore is recorded too
+	history, _ := s.GetUserFavoriteHistory(ctx, 1, 2)
+	assert.Len(t, history, 5)
+	assert.Equal(t, models.OperationRestore, history[4].Operation)
+
+	// Test invalid versions
+	_, err = s.RestoreUserFavoriteVersion(ctx, 1, 2, 99)
+	assert.EqualError(t, err, "version not found")
+	_, err = s.RestoreUserFavoriteVersion(ctx, 1, 2, 4)
+	assert.EqualError(t, err, "cannot restore a deleted version")
+}
+
+func TestUserTrash(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+
+	// Test deleted favourites are moved to the trash
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 1))
+	trash, err := s.GetUserTrash(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Equal(t, 1, trash[0].Asset.GetID())
+	assert.Equal(t, trash[0].DeletedAt.Add(service.DefaultTrashRetention), trash[0].ExpiresAt)
+
+	// Test restoring from the trash
+	asset, err := s.RestoreUserFavoriteFromTrash(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, asset.GetID())
+	_, err = s.GetUserFavorite(ctx, 1, 1)
+	assert.NoError(t, err)
+
+	_, err = s.RestoreUserFavoriteFromTrash(ctx, 1, 1)
+	assert.EqualError(t, err, "asset not in trash")
+
+	// Test permanently deleting from the trash
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 1))
+	assert.NoError(t, s.DeleteUserFavoriteFromTrash(ctx, 1, 1))
+	trash, _ = s.GetUserTrash(ctx, 1)
+	assert.Empty(t, trash)
+
+	// Test non-existing user
+	_, err = s.GetUserTrash(ctx, 999)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+	s.TrashRetention = time.Hour
+
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 1))
+
+	// Test items are kept until their retention expires
+	purged, err := s.PurgeExpiredTrash(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, purged)
+
+	purged, err = s.PurgeExpiredTrash(time.Now().Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = s.RestoreUserFavoriteFromTrash(ctx, 1, 1)
+	assert.EqualError(t, err, "asset not in trash")
+}
+
+func TestExpiredTrashBeforePurge(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+	s.TrashRetention = time.Nanosecond
+	assert.NoError(t, s.DeleteUserFavorite(ctx, 1, 1))
+	time.Sleep(time.Millisecond)
+
+	// Test expired items are neither listed nor restored, without purging the trash on reads
+	trash, err := s.GetUserTrash(ctx, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	stored, _ := s.HistoryRepository.GetTrash(1)
+	assert.Len(t, stored, 1)
+
+	_, err = s.RestoreUserFavoriteFromTrash(ctx, 1, 1)
+	assert.EqualError(t, err, "asset not in trash")
+	_, err = s.GetUserFavorite(ctx, 1, 1)
+	assert.EqualError(t, err, "asset not found")
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"unicode/utf8"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+)
+
+// AssetLimits bounds the size of the assets users can add to their favourites. A zero limit disables the check.
+type AssetLimits struct {
+	MaxDataPoints   int `json:"maxDataPoints"`   // data points of a chart
+	MaxStringLength int `json:"maxStringLength"` // characters of descriptions, titles and axes titles
+	MaxTextLength   int `json:"maxTextLength"`   // characters of the text of an insight
+}
+
+// DefaultAssetLimits are the limits of a new UserService
+var DefaultAssetLimits = AssetLimits{
+	MaxDataPoints:   10000,
+	MaxStringLength: 1000,
+	MaxTextLength:   10000,
+}
+
+// checkAsset returns an error if an asset has no valid ID, which the API could not address it by,
+// or naming the first field of the asset that exceeds the limits
+func (l AssetLimits) checkAsset(asset models.Asset) error {
+	if asset.GetID() < 1 {
+		return errors.New("invalid asset id")
+	}
+	switch a := asset.(type) {
+	case *models.Chart:
+		return l.checkChart(*a)
+	case models.Chart:
+		return l.checkChart(a)
+	case *models.Insight:
+		return l.checkInsight(*a)
+	case models.Insight:
+		return l.checkInsight(a)
+	case *models.Audience:
+		return checkLength("description", a.Description, l.MaxStringLength)
+	case models.Audience:
+		return checkLength("description", a.Description, l.MaxStringLength)
+	}
+	return nil
+}
+
+func (l AssetLimits) checkChart(chart models.Chart) error {
+	if l.MaxDataPoints > 0 && len(chart.DataPoints) > l.MaxDataPoints {
+		return errors.New("too many data points")
+	}
+	// NaN and infinite values cannot be encoded as JSON, so they would break every listing of the favourites
+	for _, point := range chart.DataPoints {
+		if !finite(point.X) || !finite(point.Y) {
+			return errors.New("invalid data point")
+		}
+	}
+	if err := checkLength("description", chart.Description, l.MaxStringLength); err != nil {
+		return err
+	}
+	if err := checkLength("title", chart.Title, l.MaxStringLength); err != nil {
+		return err
+	}
+	if err := checkLength("x axes title", chart.XAxesTitle, l.MaxStringLength); err != nil {
+		return err
+	}
+	return checkLength("y axes title", chart.YAxesTitle, l.MaxStringLength)
+}
+
+func (l AssetLimits) checkInsight(insight models.Insight) error {
+	if err := checkLength("description", insight.Description, l.MaxStringLength); err != nil {
+		return err
+	}
+	return checkLength("text", insight.Text, l.MaxTextLength)
+}
+
+// finite reports whether a value is neither NaN nor infinite
+func finite(value float32) bool {
+	return !math.IsNaN(float64(value)) && !math.IsInf(float64(value), 0)
+}
+
+// checkLength returns an error if a string has more than max characters
+func checkLength(field, value string, max int) error {
+	if max > 0 && len(value) > max && utf8.RuneCountInString(value) > max {
+		return errors.New(field + " too long")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+// UserProfile is what is held about a user besides their favourites
+type UserProfile struct {
+	ID int `json:"id"`
+}
+
+// UserExport is everything held about a user, answering a data subject access request
+type UserExport struct {
+	UserID            int                       `json:"userId"`
+	ExportedAt        time.Time                 `json:"exportedAt"`
+	Profile           UserProfile               `json:"profile"`
+	Favorites         []models.Asset            `json:"favorites"` // ordered by asset id
+	History           []models.HistoryEntry     `json:"history"`
+	Trash             []models.TrashItem        `json:"trash"`
+	Collections       []models.Collection       `json:"collections"`       // owned by the user
+	SharedCollections []models.SharedCollection `json:"sharedCollections"` // shared with the user by other users
+}
+
+// ExportUser gathers everything held about a user: their profile, favourites, history, trash and collections
+func (s *AdminService) ExportUser(ctx context.Context, userID int) (export UserExport, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.ExportUser", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	favorites, err := s.UserService.UserRepository.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return export, err
+	}
+
+	export = UserExport{
+		UserID:            userID,
+		ExportedAt:        time.Now().UTC(),
+		Profile:           UserProfile{ID: userID},
+		Favorites:         make([]models.Asset, 0, len(favorites)),
+		Collections:       []models.Collection{},
+		SharedCollections: []models.SharedCollection{},
+	}
+	for _, asset := range favorites {
+		export.Favorites = append(export.Favorites, asset)
+	}
+	sort.Slice(export.Favorites, func(i, j int) bool { return export.Favorites[i].GetID() < export.Favorites[j].GetID() })
+
+	if export.History, err = s.UserService.HistoryRepository.GetUserHistory(userID); err != nil {
+		return export, err
+	}
+	if export.Trash, err = s.UserService.HistoryRepository.GetTrash(userID); err != nil {
+		return export, err
+	}
+	if s.CollectionService != nil {
+		if export.Collections, err = s.CollectionService.GetUserCollections(ctx, userID); err != nil {
+			return export, err
+		}
+		if export.SharedCollections, err = s.CollectionService.GetSharedCollections(ctx, userID); err != nil {
+			return export, err
+		}
+	}
+	return export, nil
+}
+
+// WriteArchive writes the export as a zip archive of JSON files, one per kind of data,
+// with a manifest.json listing them
+func (export UserExport) WriteArchive(w io.Writer) error {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"favorites.json", export.Favorites},
+		{"history.json", export.History},
+		{"trash.json", export.Trash},
+		{"collections.json", export.Collections},
+		{"shared-collections.json", export.SharedCollections},
+	}
+	manifest := struct {
+		UserID     int       `json:"userId"`
+		ExportedAt time.Time `json:"exportedAt"`
+		Files      []string  `json:"files"`
+	}{UserID: export.UserID, ExportedAt: export.ExportedAt}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	archive := zip.NewWriter(w)
+	add := func(name string, data interface{}) error {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+	if err := add("manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := add(file.name, file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// EraseUser erases everything held about a user: their favourites, history, trash and collections are purged,
+// the user is deleted if the repository can delete users, the retries of the user's webhook deliveries are cancelled,
+// and the deliveries and streamed events are anonymized or removed, and the key of the user's values in the audit log is deleted. The erasure is recorded, without any of the erased data, in the erasure records and the audit log, and the record returned.
+func (s *AdminService) EraseUser(ctx context.Context, userID int) (record models.ErasureRecord, err error) {
+	ctx, span := telemetry.Start(ctx, "AdminService.EraseUser", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	record = models.ErasureRecord{UserID: userID, Actor: ActorFromContext(ctx), RequestedAt: time.Now().UTC()}
+	purged, err := s.PurgeUser(ctx, userID)
+	if err != nil {
+		return record, err
+	}
+	record.Removed = map[string]int{
+		"favorites":          purged.Favorites,
+		"historyEntries":     purged.HistoryEntries,
+		"trashItems":         purged.TrashItems,
+		"collectionsDeleted": purged.CollectionsDeleted,
+		"collectionsLeft":    purged.CollectionsLeft,
+	}
+
+	// Delete the user, removing their cached favourites too, when the repository supports it
+	if deleter, ok := s.UserService.UserRepository.(repository.UserDeleter); ok {
+		switch err := deleter.DeleteUser(ctx, userID); {
+		case err == nil:
+			record.UserDeleted = true
+		case err.Error() != "repository cannot delete its users":
+			return record, err
+		}
+	}
+
+	if s.WebhookService != nil {
+		if record.Removed["webhookDeliveries"], err = s.WebhookService.ForgetUser(userID); err != nil {
+			return record, err
+		}
+	}
+	if s.EventStream != nil {
+		record.Removed["streamedEvents"] = s.EventStream.ForgetUser(userID)
+	}
+
+	// Delete the key of the user's values in the audit log, which is append-only, so they can no longer be read
+	if s.UserService.AuditKeys != nil {
+		deleted, err := s.UserService.AuditKeys.DeleteAuditKey(userID)
+		if err != nil {
+			return record, err
+		}
+		record.Removed["auditKeys"] = 0
+		if deleted {
+			record.Removed["auditKeys"] = 1
+		}
+	}
+
+	record.CompletedAt = time.Now().UTC()
+	if record, err = s.ErasureRepository.AddErasureRecord(record); err != nil {
+		return record, err
+	}
+
+	// Record the erasure in the audit log too, next to the changes of the user it leaves unreadable
+	if s.UserService.AuditSink != nil {
+		err := s.UserService.AuditSink.AppendAuditRecord(models.AuditRecord{
+			Timestamp: record.CompletedAt,
+			Actor:     record.Actor,
+			RequestID: RequestIDFromContext(ctx),
+			UserID:    userID,
+			Operation: models.OperationErase,
+			Fields:    []string{},
+		})
+		if err != nil {
+			s.UserService.auditFailed(ctx, err)
+		}
+	}
+	return record, nil
+}
+
+// GetErasureRecords returns the records of every erasure, oldest first
+func (s *AdminService) GetErasureRecords(ctx context.Context) ([]models.ErasureRecord, error) {
+	return s.ErasureRepository.GetErasureRecords()
+}
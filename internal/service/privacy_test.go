@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupPrivacy returns an AdminService whose user 1 has a history, a trashed favourite, an owned collection
+// and a collection shared with them, and whose changes are streamed
+func setupPrivacy(t *testing.T) *service.AdminService {
+	s := setupAdmin(t)
+	ctx := service.WithActor(context.Background(), "user:alice")
+	s.EventStream = service.NewEventStream(service.DefaultEventLogSize)
+	s.UserService.AddListener(s.EventStream.Publish)
+	s.UserService.AuditSink = repository.NewInMemoryAuditSink(0)
+	s.UserService.AuditKeys = repository.NewInMemoryAuditKeyStore()
+
+	assert.NoError(t, s.UserService.EditUserFavorite(ctx, 1, 1, models.Insight{ID: 1, Type: models.InsightType, Text: "Edited"}))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 1, 2))
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 2, 2))
+	_, err := s.CollectionService.CreateCollection(ctx, 1, "Mine")
+	assert.NoError(t, err)
+	shared, _ := s.CollectionService.CreateCollection(ctx, 2, "Shared")
+	_, err = s.CollectionService.ShareCollection(ctx, 2, shared.ID, 1, models.RoleViewer)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestExportUser(t *testing.T) {
+	s := setupPrivacy(t)
+	ctx := context.Background()
+
+	export, err := s.ExportUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, export.UserID)
+	assert.Equal(t, service.UserProfile{ID: 1}, export.Profile)
+	assert.Len(t, export.Favorites, 2)
+	assert.Equal(t, 1, export.Favorites[0].GetID())
+	assert.Equal(t, 3, export.Favorites[1].GetID())
+	assert.Len(t, export.History, 2)
+	assert.Len(t, export.Trash, 1)
+	assert.Len(t, export.Collections, 1)
+	assert.Len(t, export.SharedCollections, 1)
+
+	// Test the archive holds a JSON file for every kind of data, listed in the manifest
+	var archive bytes.Buffer
+	assert.NoError(t, export.WriteArchive(&archive))
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	assert.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range reader.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+
+	var manifest struct {
+		UserID int      `json:"userId"`
+		Files  []string `json:"files"`
+	}
+	assert.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, 1, manifest.UserID)
+	assert.Len(t, files, len(manifest.Files)+1)
+	for _, name := range manifest.Files {
+		assert.True(t, json.Valid(files[name]), name)
+	}
+	var history []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(files["history.json"], &history))
+	assert.Equal(t, "user:alice", history[0]["actor"])
+
+	// Test non-existing user
+	_, err = s.ExportUser(ctx, 999)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestEraseUser(t *testing.T) {
+	s := setupPrivacy(t)
+	ctx := service.WithActor(context.Background(), "user:dpo")
+	webhookRepo := repository.NewInMemoryWebhookRepository()
+	webhookRepo.AddSubscription(models.WebhookSubscription{ID: "a"})
+	webhookRepo.SaveDelivery(models.WebhookDelivery{ID: "1", SubscriptionID: "a", Event: models.FavoriteEvent{UserID: 1, Actor: "user:alice"}})
+	s.WebhookService = service.NewWebhookService(webhookRepo)
+	export, err := s.ExportUser(ctx, 1)
+	assert.NoError(t, err)
+	audit, err := s.GetAuditRecords(ctx, repository.AuditFilter{UserID: 1})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, audit.Records[0].Changes)
+
+	record, err := s.EraseUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, record.ID)
+	assert.Equal(t, 1, record.UserID)
+	assert.Equal(t, "user:dpo", record.Actor)
+	assert.True(t, record.UserDeleted)
+	assert.False(t, record.CompletedAt.Before(record.RequestedAt))
+	assert.Equal(t, map[string]int{
+		"favorites":          2,
+		"historyEntries":     2,
+		"trashItems":         1,
+		"collectionsDeleted": 1,
+		"collectionsLeft":    1,
+		"webhookDeliveries":  1,
+		"streamedEvents":     2,
+		"auditKeys":          1,
+	}, record.Removed)
+
+	// Test nothing is left of the user
+	_, err = s.ExportUser(ctx, 1)
+	assert.EqualError(t, err, "user not found")
+	history, _ := s.UserService.HistoryRepository.GetUserHistory(1)
+	assert.Empty(t, history)
+	sharedWith, _ := s.CollectionService.GetSharedCollections(ctx, 1)
+	assert.Empty(t, sharedWith)
+	deliveries, _ := webhookRepo.GetDeliveries("a")
+	assert.Zero(t, deliveries[0].Event.UserID)
+	assert.Empty(t, deliveries[0].Event.Actor)
+	missed, _, unsubscribe := s.EventStream.Subscribe(1, 1)
+	defer unsubscribe()
+	assert.Empty(t, missed)
+
+	// Test the audit log of the user's changes holds none of the erased favourites, as returned or as stored,
+	// even once the user gets a new key
+	_, _, err = s.UserService.AuditKeys.GetAuditKey(1, true)
+	assert.NoError(t, err)
+	audit, err = s.GetAuditRecords(ctx, repository.AuditFilter{UserID: 1})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, audit.Records)
+	for _, record := range audit.Records {
+		assert.Empty(t, record.Changes)
+	}
+	erasure := audit.Records[len(audit.Records)-1]
+	assert.Equal(t, models.OperationErase, erasure.Operation)
+	assert.Equal(t, "user:dpo", erasure.Actor)
+	assert.Equal(t, record.CompletedAt, erasure.Timestamp)
+	stored, _ := s.UserService.AuditSink.GetAuditRecords(repository.AuditFilter{UserID: 1})
+	data, _ := json.Marshal(append(audit.Records, stored.Records...))
+	checked := 0
+	for _, entry := range export.History {
+		for _, asset := range []models.Asset{entry.Before, entry.After} {
+			var fields struct{ Description, Text string }
+			encoded, _ := json.Marshal(asset)
+			json.Unmarshal(encoded, &fields)
+			for _, value := range []string{fields.Description, fields.Text} {
+				if value != "" {
+					assert.NotContains(t, string(data), value)
+					checked++
+				}
+			}
+		}
+	}
+	assert.NotZero(t, checked)
+
+	// Test the other users are untouched
+	export, err = s.ExportUser(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, export.Trash, 1)
+
+	// Test the erasure is recorded once, and erasing again fails
+	_, err = s.EraseUser(ctx, 1)
+	assert.EqualError(t, err, "user not found")
+	records, err := s.GetErasureRecords(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ErasureRecord{record}, records)
+}
+
+func TestEraseUserStopsWebhookRetries(t *testing.T) {
+	s := setupAdmin(t)
+	ctx := service.WithActor(context.Background(), "user:alice")
+
+	var requests int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	webhookRepo := repository.NewInMemoryWebhookRepository()
+	s.WebhookService = service.NewWebhookService(webhookRepo)
+	s.WebhookService.AllowPrivateTargets = true
+	s.WebhookService.InitialBackoff = 50 * time.Millisecond
+	defer s.WebhookService.Close()
+	s.UserService.AddListener(s.WebhookService.Notify)
+	subscription, err := s.WebhookService.Subscribe(receiver.URL, "secret", nil)
+	assert.NoError(t, err)
+
+	// Test the deliveries failing before the erasure are not retried with the erased data
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 1, 1))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&requests) >= 1 }, time.Second, time.Millisecond)
+
+	_, err = s.EraseUser(ctx, 1)
+	assert.NoError(t, err)
+	sent := atomic.LoadInt32(&requests)
+	deliveries, _ := webhookRepo.GetDeliveries(subscription.ID)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryCancelled, deliveries[0].Status)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+	assert.Zero(t, deliveries[0].Event.UserID)
+	assert.Nil(t, deliveries[0].Event.Asset)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, sent, atomic.LoadInt32(&requests))
+	deliveries, _ = webhookRepo.GetDeliveries(subscription.ID)
+	assert.Nil(t, deliveries[0].Event.Asset)
+
+	// Test the deliveries of the other users are still retried
+	assert.NoError(t, s.UserService.DeleteUserFavorite(ctx, 2, 1))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&requests) >= sent+2 }, time.Second, time.Millisecond)
+}
+
+func TestEraseUserKeptByRepository(t *testing.T) {
+	s := setupPrivacy(t)
+	ctx := context.Background()
+
+	// Test a repository that cannot delete users keeps the user without any favourites
+	s.UserService.UserRepository = struct{ repository.UserRepository }{s.UserService.UserRepository}
+	record, err := s.EraseUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.False(t, record.UserDeleted)
+	favorites, err := s.UserService.GetUserFavorites(ctx, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, favorites)
+}
@@ -1,38 +1,172 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
 )
 
+// DefaultTrashRetention is how long deleted favourites can be restored from the trash by default
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
 // UserService struct defines methods related to user operations
 type UserService struct {
-	UserRepository repository.UserRepository
+	UserRepository    repository.UserRepository
+	HistoryRepository repository.HistoryRepository
+	AuditSink         repository.AuditSink     // nil disables the audit log
+	AuditKeys         repository.AuditKeyStore // keys of the users encrypting the values in the audit log, nil recording only the names of the fields
+	TrashRetention    time.Duration            // zero keeps deleted favourites in the trash forever
+	AssetLimits       AssetLimits              // limits of the assets added or edited
+	// AuditErrorHandler is called with the error of every stored change that could not be appended to the audit log,
+	// e.g. to log it. The change itself does not fail, since it is already stored.
+	AuditErrorHandler func(err error)
+
+	listeners     []EventListener
+	listenersMu   sync.RWMutex
+	auditFailures atomic.Int64
 }
 
-// NewUserService creates a new UserService instance
+// NewUserService creates a new UserService instance, recording the favourites history in memory, without an audit log
 func NewUserService(repo repository.UserRepository) *UserService {
 	return &UserService{
-		UserRepository: repo,
+		UserRepository:    repo,
+		HistoryRepository: repository.NewInMemoryHistoryRepository(),
+		TrashRetention:    DefaultTrashRetention,
+		AssetLimits:       DefaultAssetLimits,
 	}
 }
 
+// GetUserIDs returns the IDs of all users in ascending order
+func (s *UserService) GetUserIDs(ctx context.Context) (ids []int, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.GetUserIDs")
+	defer func() { telemetry.End(span, err) }()
+
+	return s.UserRepository.GetUserIDs(ctx)
+}
+
 // GetUserFavorites returns a map of user's favorite assets
-func (s *UserService) GetUserFavorites(userID int) (map[int]models.Asset, error) {
-	return s.UserRepository.GetUserFavorites(userID)
+func (s *UserService) GetUserFavorites(ctx context.Context, userID int) (favorites map[int]models.Asset, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.GetUserFavorites", telemetry.UserIDKey.Int(userID))
+	defer func() { telemetry.End(span, err) }()
+
+	return s.UserRepository.GetUserFavorites(ctx, userID)
+}
+
+// GetUserFavorite returns a single asset from the user's favorites
+func (s *UserService) GetUserFavorite(ctx context.Context, userID, assetID int) (asset models.Asset, err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.GetUserFavorite", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
+	favorites, err := s.UserRepository.GetUserFavorites(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, ok := favorites[assetID]
+	if !ok {
+		return nil, errors.New("asset not found")
+	}
+	return asset, nil
 }
 
 // AddUserFavorite adds an asset to the user's favorites
-func (s *UserService) AddUserFavorite(userID int, asset models.Asset) error {
-	return s.UserRepository.AddUserFavorite(userID, asset)
+func (s *UserService) AddUserFavorite(ctx context.Context, userID int, asset models.Asset) (err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.AddUserFavorite", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(asset.GetID()), telemetry.AssetTypeKey.String(string(asset.GetType())))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := s.AssetLimits.checkAsset(asset); err != nil {
+		return err
+	}
+	asset, err = normalizeAsset(asset)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepository.AddUserFavorite(ctx, userID, asset); err != nil {
+		return err
+	}
+
+	_, err = s.record(ctx, models.OperationAdd, userID, asset.GetID(), nil, asset)
+	return err
 }
 
-// DeleteUserFavorite deletes an asset from the user's favorites
-func (s *UserService) DeleteUserFavorite(userID, assetID int) error {
-	return s.UserRepository.DeleteUserFavorite(userID, assetID)
+// DeleteUserFavorite deletes an asset from the user's favorites.
+// The asset is moved to the user's trash, from where it can be restored until the trash retention expires.
+func (s *UserService) DeleteUserFavorite(ctx context.Context, userID, assetID int) (err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.DeleteUserFavorite", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID))
+	defer func() { telemetry.End(span, err) }()
+
+	before, err := s.GetUserFavorite(ctx, userID, assetID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepository.DeleteUserFavorite(ctx, userID, assetID); err != nil {
+		return err
+	}
+
+	entry, err := s.record(ctx, models.OperationDelete, userID, assetID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	item := models.TrashItem{
+		UserID:    userID,
+		Asset:     before,
+		DeletedBy: entry.Actor,
+		DeletedAt: entry.Timestamp,
+	}
+	if s.TrashRetention > 0 {
+		item.ExpiresAt = entry.Timestamp.Add(s.TrashRetention)
+	}
+	return s.HistoryRepository.AddToTrash(item)
 }
 
 // EditUserFavorite edits an asset in the user's favorites
-func (s *UserService) EditUserFavorite(userID int, assetID int, asset models.Asset) error {
-	return s.UserRepository.EditUserFavorite(userID, assetID, asset)
+func (s *UserService) EditUserFavorite(ctx context.Context, userID int, assetID int, asset models.Asset) (err error) {
+	ctx, span := telemetry.Start(ctx, "UserService.EditUserFavorite", telemetry.UserIDKey.Int(userID), telemetry.AssetIDKey.Int(assetID), telemetry.AssetTypeKey.String(string(asset.GetType())))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := s.AssetLimits.checkAsset(asset); err != nil {
+		return err
+	}
+	asset, err = normalizeAsset(asset)
+	if err != nil {
+		return err
+	}
+
+	before, err := s.GetUserFavorite(ctx, userID, assetID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepository.EditUserFavorite(ctx, userID, assetID, asset); err != nil {
+		return err
+	}
+
+	_, err = s.record(ctx, models.OperationEdit, userID, assetID, before, asset)
+	return err
+}
+
+// normalizeAsset replaces the free text values of an asset with the canonical reference data before it is stored.
+// Assets passed by pointer are normalised in place, so callers see the stored values.
+func normalizeAsset(asset models.Asset) (models.Asset, error) {
+	switch a := asset.(type) {
+	case *models.Audience:
+		if err := models.NormalizeAudience(a); err != nil {
+			return nil, err
+		}
+	case models.Audience:
+		if err := models.NormalizeAudience(&a); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	return asset, nil
 }
@@ -1,6 +1,8 @@
 package service_test
 
 import (
+	"context"
+	"math"
 	"testing"
 
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
@@ -15,21 +17,49 @@ func setup() *service.UserService {
 	return service.NewUserService(repo)
 }
 
+func TestGetUserIDs(t *testing.T) {
+	s := setup()
+
+	ids, err := s.GetUserIDs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+}
+
 func TestGetUserFavorites(t *testing.T) {
 	s := setup()
+	ctx := context.Background()
 
 	// Test existing user
-	favorites, err := s.GetUserFavorites(1)
+	favorites, err := s.GetUserFavorites(ctx, 1)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, favorites)
 
 	// Test non-existing user
-	_, err = s.GetUserFavorites(999)
+	_, err = s.GetUserFavorites(ctx, 999)
 	assert.Error(t, err)
 }
 
+func TestGetUserFavorite(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+
+	// Test existing asset
+	asset, err := s.GetUserFavorite(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, asset.GetID())
+
+	// Test non-existing asset
+	_, err = s.GetUserFavorite(ctx, 1, 999)
+	assert.EqualError(t, err, "asset not found")
+
+	// Test non-existing user
+	_, err = s.GetUserFavorite(ctx, 999, 1)
+	assert.EqualError(t, err, "user not found")
+}
+
 func TestAddUserFavorite(t *testing.T) {
 	s := setup()
+	ctx := context.Background()
 	newAsset := models.Insight{
 		ID:          2,
 		Type:        models.InsightType,
@@ -38,36 +68,65 @@ func TestAddUserFavorite(t *testing.T) {
 	}
 
 	// Test adding asset to existing user
-	err := s.AddUserFavorite(1, newAsset)
+	err := s.AddUserFavorite(ctx, 1, newAsset)
 	assert.NoError(t, err)
 
 	// Test adding existing asset to user
-	err = s.AddUserFavorite(1, newAsset)
+	err = s.AddUserFavorite(ctx, 1, newAsset)
 	assert.Error(t, err)
 
 	// Test adding asset to non-existing user
-	err = s.AddUserFavorite(999, newAsset)
+	err = s.AddUserFavorite(ctx, 999, newAsset)
 	assert.Error(t, err)
 }
 
+func TestAddUserFavoriteNormalizesAudience(t *testing.T) {
+	s := setup()
+	ctx := context.Background()
+
+	// Test aliases are normalised and the age group is derived from the age
+	audience := &models.Audience{ID: 2, Type: models.AudienceType, Age: 40, AgeGroup: "25-45", Gender: "f", BirthCountry: "United States of America"}
+	err := s.AddUserFavorite(ctx, 1, audience)
+	assert.NoError(t, err)
+
+	favorites, _ := s.GetUserFavorites(ctx, 1)
+	stored := favorites[2].(*models.Audience)
+	assert.Equal(t, models.AgeGroupAdult, stored.AgeGroup)
+	assert.Equal(t, models.GenderFemale, stored.Gender)
+	assert.Equal(t, "US", stored.BirthCountry)
+
+	// Test assets passed by value are normalised too
+	err = s.AddUserFavorite(ctx, 1, models.Audience{ID: 3, Type: models.AudienceType, Age: 70, BirthCountry: "uk"})
+	assert.NoError(t, err)
+	favorites, _ = s.GetUserFavorites(ctx, 1)
+	assert.Equal(t, "GB", favorites[3].(models.Audience).BirthCountry)
+	assert.Equal(t, models.AgeGroupSenior, favorites[3].(models.Audience).AgeGroup)
+
+	// Test values outside the controlled vocabularies are rejected
+	err = s.AddUserFavorite(ctx, 1, models.Audience{ID: 4, Type: models.AudienceType, Gender: "unknown"})
+	assert.EqualError(t, err, "invalid gender")
+}
+
 func TestDeleteUserFavorite(t *testing.T) {
 	s := setup()
+	ctx := context.Background()
 
 	// Test deleting existing asset
-	err := s.DeleteUserFavorite(1, 1)
+	err := s.DeleteUserFavorite(ctx, 1, 1)
 	assert.NoError(t, err)
 
 	// Test deleting non-existing asset
-	err = s.DeleteUserFavorite(1, 999)
+	err = s.DeleteUserFavorite(ctx, 1, 999)
 	assert.Error(t, err)
 
 	// Test deleting asset from non-existing user
-	err = s.DeleteUserFavorite(999, 1)
+	err = s.DeleteUserFavorite(ctx, 999, 1)
 	assert.Error(t, err)
 }
 
 func TestEditUserFavorite(t *testing.T) {
 	s := setup()
+	ctx := context.Background()
 	editedAsset := models.Insight{
 		ID:          1,
 		Type:        models.InsightType,
@@ -76,25 +135,64 @@ func TestEditUserFavorite(t *testing.T) {
 	}
 
 	// Test editing existing asset
-	err := s.EditUserFavorite(1, 1, editedAsset)
+	err := s.EditUserFavorite(ctx, 1, 1, editedAsset)
 	assert.NoError(t, err)
 
 	// Test editing non-existing asset
-	err = s.EditUserFavorite(1, 999, editedAsset)
+	err = s.EditUserFavorite(ctx, 1, 999, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset for non-existing user
-	err = s.EditUserFavorite(999, 1, editedAsset)
+	err = s.EditUserFavorite(ctx, 999, 1, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset with mismatched ID
 	editedAsset.ID = 999
-	err = s.EditUserFavorite(1, 1, editedAsset)
+	err = s.EditUserFavorite(ctx, 1, 1, editedAsset)
 	assert.Error(t, err)
 
 	// Test editing asset with mismatched type
 	editedAsset.ID = 1
 	editedAsset.Type = models.AudienceType
-	err = s.EditUserFavorite(1, 1, editedAsset)
+	err = s.EditUserFavorite(ctx, 1, 1, editedAsset)
 	assert.Error(t, err)
 }
+
+func TestAssetLimits(t *testing.T) {
+	s := setup()
+	s.AssetLimits = service.AssetLimits{MaxDataPoints: 2, MaxStringLength: 5, MaxTextLength: 10}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		asset    models.Asset
+		expected string
+	}{
+		{"TooManyDataPoints", &models.Chart{ID: 100, Type: models.ChartType, DataPoints: make([]models.Point, 3)}, "too many data points"},
+		{"TitleTooLong", models.Chart{ID: 100, Type: models.ChartType, Title: "Sample"}, "title too long"},
+		{"AxesTitleTooLong", &models.Chart{ID: 100, Type: models.ChartType, YAxesTitle: "Y-Axis"}, "y axes title too long"},
+		{"DescriptionTooLong", &models.Audience{ID: 100, Type: models.AudienceType, Description: "Sample"}, "description too long"},
+		{"TextTooLong", &models.Insight{ID: 100, Type: models.InsightType, Text: "Sample text"}, "text too long"},
+		{"ZeroID", &models.Insight{Type: models.InsightType}, "invalid asset id"},
+		{"NaNDataPoint", &models.Chart{ID: 100, Type: models.ChartType, DataPoints: []models.Point{{X: float32(math.NaN())}}}, "invalid data point"},
+		{"InfiniteDataPoint", models.Chart{ID: 100, Type: models.ChartType, DataPoints: []models.Point{{Y: float32(math.Inf(-1))}}}, "invalid data point"},
+		{"NegativeID", models.Audience{ID: -1, Type: models.AudienceType}, "invalid asset id"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, s.AddUserFavorite(ctx, 1, tc.asset), tc.expected)
+		})
+	}
+
+	// Test lengths are counted in characters, not bytes
+	err := s.AddUserFavorite(ctx, 1, &models.Insight{ID: 100, Type: models.InsightType, Description: "ελλην", Text: "κείμενο"})
+	assert.NoError(t, err)
+
+	// Test edits are checked too
+	err = s.EditUserFavorite(ctx, 1, 100, &models.Insight{ID: 100, Type: models.InsightType, Text: "Sample text"})
+	assert.EqualError(t, err, "text too long")
+
+	// Test zero limits disable the checks
+	s.AssetLimits = service.AssetLimits{}
+	assert.NoError(t, s.AddUserFavorite(ctx, 1, &models.Chart{ID: 101, Type: models.ChartType, DataPoints: make([]models.Point, 3)}))
+}
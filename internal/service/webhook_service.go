@@ -0,0 +1,435 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookService delivers the favourite events to the webhook subscriptions.
+// Every event is delivered asynchronously and retried with exponential backoff until it succeeds,
+// or is moved to the dead letters once MaxAttempts requests have failed.
+type WebhookService struct {
+	WebhookRepository repository.WebhookRepository
+	Client            *http.Client
+	MaxAttempts       int
+	InitialBackoff    time.Duration // InitialBackoff is the wait before the second attempt, doubled after every failure
+	MaxBackoff        time.Duration
+	// AllowPrivateTargets allows subscribing and delivering to loopback, private and link-local addresses,
+	// e.g. for local development. They are refused by default, so webhooks cannot reach internal services.
+	AllowPrivateTargets bool
+
+	wg        sync.WaitGroup
+	done      chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex
+	users     map[int]*userDeliveries // the deliveries in progress by the user of their event
+}
+
+// userDeliveries are the deliveries in progress of the events of a user, which are cancelled when the user is forgotten
+type userDeliveries struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending int
+	wg      sync.WaitGroup
+}
+
+// NewWebhookService creates a new WebhookService instance with the default retry policy.
+// Its client connects to the subscriptions directly, without a proxy, and only to the addresses allowed by AllowPrivateTargets.
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	s := &WebhookService{
+		WebhookRepository: repo,
+		MaxAttempts:       5,
+		InitialBackoff:    time.Second,
+		MaxBackoff:        time.Minute,
+		done:              make(chan struct{}),
+		users:             make(map[int]*userDeliveries),
+	}
+
+	// The address is checked when connecting, after the host was resolved, so a host resolving to another
+	// address than when subscribing (DNS rebinding) or a redirect to an internal address is refused too
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: s.checkDial}
+	s.Client = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+	}
+	return s
+}
+
+// Subscribe creates a webhook subscription for the given event types, or for every event type if none is given.
+// A random secret is generated when no secret is given.
+func (s *WebhookService) Subscribe(rawURL, secret string, events []models.EventType) (models.WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.WebhookSubscription{}, errors.New("invalid webhook url")
+	}
+	if err := s.checkHost(u.Hostname()); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	for _, event := range events {
+		if !isEventType(event) {
+			return models.WebhookSubscription{}, errors.New("invalid event type")
+		}
+	}
+	if secret == "" {
+		secret = newID(32)
+	}
+
+	subscription := models.WebhookSubscription{
+		ID:        newID(8),
+		URL:       u.String(),
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: time.Now().UTC(),
+	}
+	if subscription.Events == nil {
+		subscription.Events = []models.EventType{}
+	}
+
+	if err := s.WebhookRepository.AddSubscription(subscription); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions returns every webhook subscription
+func (s *WebhookService) GetSubscriptions() ([]models.WebhookSubscription, error) {
+	return s.WebhookRepository.GetSubscriptions()
+}
+
+// DeleteSubscription removes a webhook subscription, stopping the retries of its pending deliveries
+func (s *WebhookService) DeleteSubscription(id string) error {
+	return s.WebhookRepository.DeleteSubscription(id)
+}
+
+// GetDeliveries returns the delivery log of a subscription, most recent first
+func (s *WebhookService) GetDeliveries(subscriptionID string) ([]models.WebhookDelivery, error) {
+	return s.WebhookRepository.GetDeliveries(subscriptionID)
+}
+
+// GetDeadLetters returns the deliveries that failed all their attempts
+func (s *WebhookService) GetDeadLetters() ([]models.WebhookDelivery, error) {
+	return s.WebhookRepository.GetDeadLetters()
+}
+
+// Notify starts the delivery of an event to every subscription interested in it.
+// It does not wait for the deliveries, so it can be registered as an EventListener of the UserService.
+func (s *WebhookService) Notify(event models.FavoriteEvent) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	subscriptions, err := s.WebhookRepository.GetSubscriptions()
+	if err != nil {
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscribedTo(subscription, event.Type) {
+			continue
+		}
+
+		delivery := models.WebhookDelivery{
+			ID:             newID(8),
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			Status:         models.DeliveryPending,
+			Attempts:       []models.WebhookAttempt{},
+			CreatedAt:      time.Now().UTC(),
+		}
+		// Track the delivery before saving it, so forgetting the user meanwhile waits for it to stop
+		ctx, release := s.track(event.UserID)
+		if err := s.WebhookRepository.SaveDelivery(delivery); err != nil {
+			release()
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer release()
+			s.deliver(ctx, delivery)
+		}()
+	}
+}
+
+// ForgetUser cancels the deliveries in progress of a user's events, waiting for their attempts in flight to finish,
+// then removes the user, the actor and the asset from the events of the user's deliveries.
+// It returns how many deliveries were anonymized.
+func (s *WebhookService) ForgetUser(userID int) (int, error) {
+	s.mu.Lock()
+	deliveries := s.users[userID]
+	delete(s.users, userID)
+	s.mu.Unlock()
+
+	if deliveries != nil {
+		deliveries.cancel()
+		deliveries.wg.Wait()
+	}
+	return s.WebhookRepository.AnonymizeUserDeliveries(userID)
+}
+
+// track registers a delivery of an event of a user. It returns the context cancelled when the user is forgotten,
+// and the function to call once the delivery stopped.
+func (s *WebhookService) track(userID int) (context.Context, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries := s.users[userID]
+	if deliveries == nil {
+		deliveries = &userDeliveries{}
+		deliveries.ctx, deliveries.cancel = context.WithCancel(context.Background())
+		s.users[userID] = deliveries
+	}
+	deliveries.pending++
+	deliveries.wg.Add(1)
+
+	return deliveries.ctx, func() {
+		s.mu.Lock()
+		deliveries.pending--
+		if deliveries.pending == 0 && s.users[userID] == deliveries {
+			delete(s.users, userID)
+			deliveries.cancel()
+		}
+		s.mu.Unlock()
+		deliveries.wg.Done()
+	}
+}
+
+// Close stops the pending retries and waits for the requests in flight to finish
+func (s *WebhookService) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// deliver sends a delivery to its subscription until it succeeds, runs out of attempts or ctx is cancelled
+func (s *WebhookService) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Look the subscription up before every attempt, since it may have been deleted while waiting
+		subscription, err := s.WebhookRepository.GetSubscription(delivery.SubscriptionID)
+		if err != nil {
+			return
+		}
+
+		if ctx.Err() == nil {
+			delivery.Attempts = append(delivery.Attempts, s.send(ctx, subscription, delivery, body, attempt))
+		}
+		delivery.NextAttemptAt = nil
+
+		switch {
+		case ctx.Err() != nil:
+			// The user was erased, so the event must not be sent again
+			delivery.Status = models.DeliveryCancelled
+		case delivery.Attempts[len(delivery.Attempts)-1].Error == "":
+			delivery.Status = models.DeliverySucceeded
+		case attempt >= s.MaxAttempts:
+			delivery.Status = models.DeliveryDead
+		default:
+			delivery.Status = models.DeliveryRetrying
+			next := time.Now().UTC().Add(s.backoff(attempt))
+			delivery.NextAttemptAt = &next
+		}
+		s.WebhookRepository.SaveDelivery(delivery)
+
+		if delivery.Status != models.DeliveryRetrying {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(*delivery.NextAttemptAt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		case <-s.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// send makes a single signed request for a delivery. Any response other than 2xx is a failure.
+func (s *WebhookService) send(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery, body []byte, number int) models.WebhookAttempt {
+	attempt := models.WebhookAttempt{Number: number, Timestamp: time.Now().UTC()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, delivery.ID)
+	req.Header.Set(WebhookEventHeader, string(delivery.Event.Type))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, body))
+
+	start := time.Now()
+	resp, err := s.Client.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// checkHost resolves the host of a webhook URL and refuses it if any of its addresses is not allowed
+func (s *WebhookService) checkHost(host string) error {
+	if s.AllowPrivateTargets {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(context.Background(), host)
+	if err != nil || len(addrs) == 0 {
+		return errors.New("webhook host cannot be resolved")
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return errors.New("webhook url not allowed")
+		}
+	}
+	return nil
+}
+
+// checkDial refuses the connections to addresses that are not allowed, it is the Control function of the client's dialer
+func (s *WebhookService) checkDial(network, address string, _ syscall.RawConn) error {
+	if s.AllowPrivateTargets {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return errors.New("webhook url not allowed")
+	}
+	return nil
+}
+
+// reservedNetworks are the special-purpose ranges that net.IP does not classify but webhooks cannot reach either,
+// since they are not globally routable, are shared inside a provider's network, or translate to IPv4 addresses
+var reservedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, with the broadcast address
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("2001::/32"),      // Teredo
+	netip.MustParsePrefix("2002::/16"),      // 6to4
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// publicIP reports whether an address can be reached by webhooks: loopback, private, link-local (e.g. the cloud
+// metadata service at 169.254.169.254), multicast, unspecified and reserved addresses cannot
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range reservedNetworks {
+		if network.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff returns how long to wait after the given failed attempt
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	wait := s.InitialBackoff
+	for i := 1; i < attempt && wait < s.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if s.MaxBackoff > 0 && wait > s.MaxBackoff {
+		wait = s.MaxBackoff
+	}
+	return wait
+}
+
+// SignWebhookPayload returns the value of the signature header of a payload: "sha256=" followed by
+// the hex encoded HMAC-SHA256 of the body, keyed with the subscription secret
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether a signature header matches the payload, for use by receivers
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+// subscribedTo reports whether a subscription receives events of the given type
+func subscribedTo(subscription models.WebhookSubscription, eventType models.EventType) bool {
+	if len(subscription.Events) == 0 {
+		return true
+	}
+	for _, t := range subscription.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// isEventType reports whether t is a known event type
+func isEventType(t models.EventType) bool {
+	for _, eventType := range models.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// newID returns a random hex encoded identifier of n bytes
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,217 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/repository"
+	"github.com/ceciivanov/platform-go-challenge/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupWebhooks returns a UserService publishing its events to a WebhookService with a fast retry policy,
+// delivering to the test receivers on the loopback address
+func setupWebhooks(t *testing.T) (*service.UserService, *service.WebhookService) {
+	webhooks := service.NewWebhookService(repository.NewInMemoryWebhookRepository())
+	webhooks.AllowPrivateTargets = true
+	webhooks.MaxAttempts = 3
+	webhooks.InitialBackoff = time.Millisecond
+	webhooks.MaxBackoff = 5 * time.Millisecond
+	t.Cleanup(webhooks.Close)
+
+	s := setup()
+	s.AddListener(webhooks.Notify)
+	return s, webhooks
+}
+
+// waitForStatus waits until the latest delivery of a subscription has the given status
+func waitForStatus(t *testing.T, webhooks *service.WebhookService, subscriptionID string, status models.DeliveryStatus) models.WebhookDelivery {
+	var delivery models.WebhookDelivery
+	assert.Eventually(t, func() bool {
+		deliveries, _ := webhooks.GetDeliveries(subscriptionID)
+		if len(deliveries) == 0 {
+			return false
+		}
+		delivery = deliveries[0]
+		return delivery.Status == status
+	}, 2*time.Second, time.Millisecond)
+	return delivery
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	s, webhooks := setupWebhooks(t)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	subscription, err := webhooks.Subscribe(receiver.URL, "secret", nil)
+	assert.NoError(t, err)
+
+	// Test the event is delivered signed with the subscription secret
+	assert.NoError(t, s.DeleteUserFavorite(service.WithActor(context.Background(), "tester"), 1, 1))
+
+	r := <-received
+	body := <-bodies
+	assert.Equal(t, "favorite.deleted", r.Header.Get(service.WebhookEventHeader))
+	assert.True(t, service.VerifyWebhookSignature("secret", body, r.Header.Get(service.WebhookSignatureHeader)))
+	assert.False(t, service.VerifyWebhookSignature("other", body, r.Header.Get(service.WebhookSignatureHeader)))
+
+	var event map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "favorite.deleted", event["type"])
+	assert.Equal(t, float64(1), event["userId"])
+	assert.Equal(t, "tester", event["actor"])
+
+	delivery := waitForStatus(t, webhooks, subscription.ID, models.DeliverySucceeded)
+	assert.Equal(t, r.Header.Get(service.WebhookIDHeader), delivery.ID)
+	assert.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusOK, delivery.Attempts[0].StatusCode)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	s, webhooks := setupWebhooks(t)
+
+	// Test failed deliveries are retried until they succeed
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	subscription, _ := webhooks.Subscribe(receiver.URL, "", nil)
+	assert.NotEmpty(t, subscription.Secret)
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 10, Type: models.InsightType}))
+
+	delivery := waitForStatus(t, webhooks, subscription.ID, models.DeliverySucceeded)
+	assert.Len(t, delivery.Attempts, 3)
+	assert.Equal(t, "unexpected status code 503", delivery.Attempts[0].Error)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	deadLetters, _ := webhooks.GetDeadLetters()
+	assert.Empty(t, deadLetters)
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	s, webhooks := setupWebhooks(t)
+
+	// Test deliveries failing every attempt are moved to the dead letters
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	subscription, _ := webhooks.Subscribe(receiver.URL, "secret", nil)
+	assert.NoError(t, s.EditUserFavorite(context.Background(), 1, 1, models.Insight{ID: 1, Type: models.InsightType}))
+
+	delivery := waitForStatus(t, webhooks, subscription.ID, models.DeliveryDead)
+	assert.Len(t, delivery.Attempts, 3)
+	assert.Equal(t, models.EventFavoriteEdited, delivery.Event.Type)
+
+	deadLetters, err := webhooks.GetDeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, delivery.ID, deadLetters[0].ID)
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	s, webhooks := setupWebhooks(t)
+
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	// Test invalid subscriptions
+	_, err := webhooks.Subscribe("ftp://example.com", "", nil)
+	assert.EqualError(t, err, "invalid webhook url")
+	_, err = webhooks.Subscribe(receiver.URL, "", []models.EventType{"favorite.viewed"})
+	assert.EqualError(t, err, "invalid event type")
+
+	// Test subscriptions only receive the events they subscribed to
+	added, _ := webhooks.Subscribe(receiver.URL, "", []models.EventType{models.EventFavoriteAdded})
+	assert.NoError(t, s.DeleteUserFavorite(context.Background(), 1, 1))
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 10, Type: models.InsightType}))
+
+	delivery := waitForStatus(t, webhooks, added.ID, models.DeliverySucceeded)
+	assert.Equal(t, models.EventFavoriteAdded, delivery.Event.Type)
+	deliveries, _ := webhooks.GetDeliveries(added.ID)
+	assert.Len(t, deliveries, 1)
+
+	// Test deleted subscriptions no longer receive events
+	assert.NoError(t, webhooks.DeleteSubscription(added.ID))
+	assert.NoError(t, s.AddUserFavorite(context.Background(), 1, models.Insight{ID: 11, Type: models.InsightType}))
+	webhooks.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err = webhooks.GetDeliveries(added.ID)
+	assert.EqualError(t, err, "subscription not found")
+}
+
+func TestWebhookPrivateTargets(t *testing.T) {
+	s, webhooks := setupWebhooks(t)
+	webhooks.AllowPrivateTargets = false
+
+	// Test the internal addresses are refused when subscribing
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://0.0.0.0/hook",
+		"http://0.1.2.3/hook",
+		"http://100.64.0.1/hook",
+		"http://100.127.255.254/hook",
+		"http://192.0.0.8/hook",
+		"http://198.18.0.1/hook",
+		"http://240.0.0.1/hook",
+		"http://255.255.255.255/hook",
+		"http://[::ffff:10.0.0.1]/hook",
+		"http://[::ffff:100.64.0.1]/hook",
+		"http://[64:ff9b::a00:1]/hook",
+		"http://[2001:0:a00:1::]/hook",
+		"http://[2002:a00:1::]/hook",
+		"http://[fc00::1]/hook",
+		"http://[fe80::1]/hook",
+	} {
+		_, err := webhooks.Subscribe(target, "", nil)
+		assert.EqualError(t, err, "webhook url not allowed", target)
+	}
+	for _, target := range []string{"https://203.0.113.10/hook", "https://100.128.0.1/hook", "https://[2606:4700::1111]/hook"} {
+		_, err := webhooks.Subscribe(target, "", nil)
+		assert.NoError(t, err, target)
+	}
+
+	// Test a subscription whose host resolves to an internal address when delivering is refused too
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+	webhooks.AllowPrivateTargets = true
+	rebound, err := webhooks.Subscribe(receiver.URL, "", nil)
+	assert.NoError(t, err)
+	webhooks.AllowPrivateTargets = false
+
+	assert.NoError(t, s.DeleteUserFavorite(context.Background(), 1, 1))
+	delivery := waitForStatus(t, webhooks, rebound.ID, models.DeliveryDead)
+	assert.Contains(t, delivery.Attempts[0].Error, "webhook url not allowed")
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
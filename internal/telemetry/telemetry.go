@@ -0,0 +1,131 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer creating the spans of the application
+const instrumentationName = "github.com/ceciivanov/platform-go-challenge"
+
+// Attributes recorded on the spans of every layer
+const (
+	UserIDKey    = attribute.Key("user.id")
+	AssetIDKey   = attribute.Key("asset.id")
+	AssetTypeKey = attribute.Key("asset.type")
+	OutcomeKey   = attribute.Key("outcome") // "ok" or "error"
+
+	CacheResultKey = attribute.Key("cache.result") // "local", "remote" or "miss"
+)
+
+// Config selects where the spans are exported
+type Config struct {
+	Exporter    string  `json:"exporter"`    // "none", "stdout", "file" or "otlp"
+	File        string  `json:"file"`        // file the "file" exporter appends the spans to, one JSON object per line
+	Endpoint    string  `json:"endpoint"`    // host:port of the OTLP/HTTP collector used by the "otlp" exporter
+	Insecure    bool    `json:"insecure"`    // send the spans to the collector over plain HTTP
+	ServiceName string  `json:"serviceName"` // name of the service the spans belong to
+	SampleRatio float64 `json:"sampleRatio"` // fraction of the new traces sampled, traces started by a caller follow its decision
+}
+
+// DefaultConfig returns the configuration exporting no spans
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    "none",
+		Endpoint:    "localhost:4318",
+		ServiceName: "platform-go-challenge",
+		SampleRatio: 1,
+	}
+}
+
+// Setup installs the global propagator of the W3C traceparent and baggage headers and, unless the exporter is "none",
+// a global TracerProvider exporting the spans. The returned function flushes the remaining spans and stops the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var closer io.Closer
+	var err error
+	switch cfg.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case "file":
+		var file *os.File
+		if file, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			return nil, err
+		}
+		closer = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case "otlp":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, errors.New("unknown trace exporter " + cfg.Exporter)
+	}
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the tracer of the application from the global TracerProvider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named after the operation as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the outcome of an operation on its span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(OutcomeKey.String("error"))
+	} else {
+		span.SetAttributes(OutcomeKey.String("ok"))
+	}
+	span.End()
+}
@@ -0,0 +1,57 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/telemetry"
+)
+
+func TestSetupFileExporter(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+
+	cfg := telemetry.DefaultConfig()
+	cfg.Exporter = "file"
+	cfg.File = path
+	shutdown, err := telemetry.Setup(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	_, span := telemetry.Start(context.Background(), "Test.Operation", telemetry.UserIDKey.Int(1))
+	telemetry.End(span, errors.New("user not found"))
+	assert.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"Test.Operation"`)
+	assert.Contains(t, string(data), `"Key":"user.id"`)
+	assert.Contains(t, string(data), `"Description":"user not found"`)
+	assert.Contains(t, string(data), `"Value":"platform-go-challenge"`)
+}
+
+func TestSetupExporters(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	for _, exporter := range []string{"", "none", "stdout", "otlp"} {
+		t.Run(exporter, func(t *testing.T) {
+			cfg := telemetry.DefaultConfig()
+			cfg.Exporter = exporter
+			shutdown, err := telemetry.Setup(context.Background(), cfg)
+			assert.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+
+	_, err := telemetry.Setup(context.Background(), telemetry.Config{Exporter: "jaeger"})
+	assert.EqualError(t, err, "unknown trace exporter jaeger")
+
+	_, err = telemetry.Setup(context.Background(), telemetry.Config{Exporter: "file", File: filepath.Join(t.TempDir(), "missing", "spans.jsonl")})
+	assert.Error(t, err)
+}
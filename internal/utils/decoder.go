@@ -7,35 +7,44 @@ import (
 	"github.com/ceciivanov/platform-go-challenge/internal/models"
 )
 
+// UnmarshalFunc decodes data in some encoding into v, like json.Unmarshal
+type UnmarshalFunc func(data []byte, v interface{}) error
+
 // DecodeAsset decodes JSON into the correct asset type
 func DecodeAsset(data []byte) (models.Asset, error) {
+	return DecodeAssetWith(data, json.Unmarshal)
+}
+
+// DecodeAssetWith decodes data into the correct asset type with the unmarshal function of any encoding
+// that names the fields of the assets after their JSON tags
+func DecodeAssetWith(data []byte, unmarshal UnmarshalFunc) (models.Asset, error) {
 
 	var base struct {
 		Type models.AssetType `json:"type"`
 	}
 
-	// Unmarshal the JSON into a struct that only contains the type field to determine the asset type
-	if err := json.Unmarshal(data, &base); err != nil {
+	// Unmarshal the data into a struct that only contains the type field to determine the asset type
+	if err := unmarshal(data, &base); err != nil {
 		return nil, err
 	}
 
-	// Unmarshal the JSON into the correct asset type
+	// Unmarshal the data into the correct asset type
 	switch base.Type {
 	case models.ChartType:
 		var chart models.Chart
-		if err := json.Unmarshal(data, &chart); err != nil {
+		if err := unmarshal(data, &chart); err != nil {
 			return nil, err
 		}
 		return &chart, nil
 	case models.InsightType:
 		var insight models.Insight
-		if err := json.Unmarshal(data, &insight); err != nil {
+		if err := unmarshal(data, &insight); err != nil {
 			return nil, err
 		}
 		return &insight, nil
 	case models.AudienceType:
 		var audience models.Audience
-		if err := json.Unmarshal(data, &audience); err != nil {
+		if err := unmarshal(data, &audience); err != nil {
 			return nil, err
 		}
 		return &audience, nil
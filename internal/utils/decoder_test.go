@@ -0,0 +1,64 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ceciivanov/platform-go-challenge/internal/models"
+	"github.com/ceciivanov/platform-go-challenge/internal/utils"
+)
+
+func TestDecodeAsset(t *testing.T) {
+	asset, err := utils.DecodeAsset([]byte(`{"id": 1, "type": "Chart", "title": "Title", "dataPoints": [{"X": 1, "Y": 2}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Chart{ID: 1, Type: models.ChartType, Title: "Title", DataPoints: []models.Point{{X: 1, Y: 2}}}, asset)
+
+	_, err = utils.DecodeAsset([]byte(`{"id": 1, "type": "Unknown"}`))
+	assert.EqualError(t, err, "invalid asset type")
+
+	_, err = utils.DecodeAsset([]byte(`{"id": "1", "type": "Insight"}`))
+	assert.Error(t, err)
+}
+
+// FuzzDecodeAsset checks that DecodeAsset never panics, and that every asset it decodes
+// has a valid type and decodes to the same asset again once encoded
+func FuzzDecodeAsset(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("..", "..", "json", "*.json"))
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(data)
+		}
+	}
+	f.Add([]byte(`{"id": 1, "type": "Chart", "dataPoints": [{"X": 1e38, "Y": -0}]}`))
+	f.Add([]byte(`{"id": 1, "type": "Insight", "text": "é\ud800"}`))
+	f.Add([]byte(`{"type": "Audience", "type": "Chart"}`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		asset, err := utils.DecodeAsset(data)
+		if err != nil {
+			return
+		}
+
+		switch asset.GetType() {
+		case models.ChartType, models.InsightType, models.AudienceType:
+		default:
+			t.Fatalf("decoded an asset of type %q", asset.GetType())
+		}
+
+		encoded, err := json.Marshal(asset)
+		if err != nil {
+			t.Fatalf("cannot encode the decoded asset: %v", err)
+		}
+		decoded, err := utils.DecodeAsset(encoded)
+		if err != nil {
+			t.Fatalf("cannot decode the encoded asset: %v", err)
+		}
+		assert.Equal(t, asset, decoded)
+	})
+}
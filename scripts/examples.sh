@@ -18,7 +18,7 @@ curl -X POST http://localhost:8080/users/1/favorites \
           "type": "Audience",
           "description": "This audience is a 40 year old",
           "age": 40,
-          "ageGroup": "25-45",
+          "ageGroup": "26-40",
           "gender": "Male",
           "birthCountry": "USA",
           "hoursSpentOnMedia": 4,